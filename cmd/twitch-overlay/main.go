@@ -9,10 +9,13 @@ import (
 	"time"
 
 	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/faxmanager"
 	"github.com/nantokaworks/twitch-overlay/internal/fontmanager"
 	localdb "github.com/nantokaworks/twitch-overlay/internal/localdb"
 	"github.com/nantokaworks/twitch-overlay/internal/music"
 	"github.com/nantokaworks/twitch-overlay/internal/output"
+	"github.com/nantokaworks/twitch-overlay/internal/printrules"
+	"github.com/nantokaworks/twitch-overlay/internal/selftest"
 	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
 	"github.com/nantokaworks/twitch-overlay/internal/shared/paths"
 	"github.com/nantokaworks/twitch-overlay/internal/status"
@@ -101,8 +104,12 @@ func checkStreamStatus() {
 
 	if streamInfo.IsLive {
 		// 配信中
-		startTime := time.Now() // 本来はAPIから取得すべきだが、現在のAPIでは開始時刻が取れない
-		status.UpdateStreamStatus(true, &startTime, streamInfo.ViewerCount)
+		// APIの開始時刻を使う（取得できない場合は既知の開始時刻を維持）
+		var startTime *time.Time
+		if !streamInfo.StartedAt.IsZero() {
+			startTime = &streamInfo.StartedAt
+		}
+		status.UpdateStreamStatus(true, startTime, streamInfo.ViewerCount)
 		logger.Debug("Stream is live", zap.Int("viewers", streamInfo.ViewerCount))
 	} else {
 		// オフライン
@@ -133,6 +140,16 @@ func startStreamMonitoring(done <-chan struct{}) {
 	}
 }
 
+// reloadSettings は SIGHUP 受信時にDBから設定を再読み込みします
+// （時計/キープアライブの再起動などは env.Subscribe した各サブシステムが行う）
+func reloadSettings() {
+	logger.Info("Received SIGHUP, reloading settings from database")
+
+	if err := env.ReloadFromDatabase(); err != nil {
+		logger.Error("Failed to reload settings", zap.Error(err))
+	}
+}
+
 func main() {
 	// Display version
 	fmt.Println("🖨️  Twitch Overlay " + version.String())
@@ -156,6 +173,16 @@ func main() {
 		logger.Error("Failed to initialize music database", zap.Error(err))
 	}
 
+	// init print rules database
+	if err := printrules.InitDB(); err != nil {
+		logger.Error("Failed to initialize print rules database", zap.Error(err))
+	}
+
+	// init fax history database
+	if err := faxmanager.InitDB(); err != nil {
+		logger.Error("Failed to initialize fax history database", zap.Error(err))
+	}
+
 	// init font manager
 	if err := fontmanager.Initialize(); err != nil {
 		logger.Error("Failed to initialize font manager", zap.Error(err))
@@ -163,7 +190,8 @@ func main() {
 	}
 
 	// フォントが設定されているか確認（必須）
-	if info := fontmanager.GetCurrentFontInfo(); info["path"] == nil || info["path"] == "" {
+	// ALLOW_SYSTEM_FONT が有効でシステムフォントが使える場合は待機しない
+	if info := fontmanager.GetCurrentFontInfo(); (info["path"] == nil || info["path"] == "") && !fontmanager.SystemFontAvailable() {
 		fmt.Println("")
 		fmt.Println("========================================")
 		fmt.Println("❌ エラー: フォントがアップロードされていません")
@@ -219,6 +247,9 @@ func main() {
 	// start web server (always start, even without token)
 	webserver.StartWebServer(env.Value.ServerPort)
 
+	// 起動時セルフテスト（プリンター接続を待つためgoroutineで実行）
+	go selftest.Run()
+
 	// Create a done channel for goroutines
 	done := make(chan struct{})
 
@@ -271,12 +302,16 @@ func main() {
 		go startStreamMonitoring(done)
 	}
 
-	// Setup signal handling for graceful shutdown
+	// Setup signal handling for graceful shutdown (SIGHUP reloads settings)
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 
 	// Wait for signal
 	sig := <-sigChan
+	for sig == syscall.SIGHUP {
+		reloadSettings()
+		sig = <-sigChan
+	}
 	logger.Info("Received signal, shutting down...", zap.String("signal", sig.String()))
 
 	// Signal all goroutines to stop
@@ -7,6 +7,7 @@ require (
 	github.com/joeyak/go-twitch-eventsub/v3 v3.0.0
 	github.com/joho/godotenv v1.5.1
 	github.com/mattn/go-sqlite3 v1.14.27
+	github.com/rivo/uniseg v0.4.7
 	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	go.uber.org/zap v1.27.0
 	golang.org/x/image v0.26.0
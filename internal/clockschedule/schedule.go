@@ -0,0 +1,136 @@
+// Package clockschedule parses CLOCK_SCHEDULE, which decides at which minutes the clock is printed.
+//
+// 書式は2通り:
+//   - 分単位の間隔（例: "60" は毎正時、"30" は毎時0分と30分）。0時0分を起点に数える
+//   - cron 形式の5フィールド「分 時 日 月 曜日」（例: "0 9-23 * * *"、"0,30 * * * 1-5"）
+package clockschedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default prints the clock on the hour
+const Default = "60"
+
+const minutesPerDay = 24 * 60
+
+// Schedule reports whether the clock is due at a given minute
+type Schedule struct {
+	interval int // 間隔指定のときの分数（0 なら cron 形式）
+
+	minutes, hours, days, months, weekdays map[int]bool
+	daysRestricted, weekdaysRestricted     bool
+}
+
+// Parse parses a CLOCK_SCHEDULE value. 空文字は Default（毎正時）として扱う
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		spec = Default
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 1:
+		n, err := strconv.Atoi(fields[0])
+		if err != nil || n < 1 || n > minutesPerDay {
+			return nil, fmt.Errorf("interval must be between 1 and %d minutes", minutesPerDay)
+		}
+		return &Schedule{interval: n}, nil
+	case 5:
+		return parseCron(fields)
+	default:
+		return nil, fmt.Errorf("expected an interval in minutes or 5 cron fields (minute hour day month weekday)")
+	}
+}
+
+// Matches reports whether the clock should be printed at the minute containing t (t のタイムゾーンで判定)
+func (s *Schedule) Matches(t time.Time) bool {
+	if s.interval > 0 {
+		return (t.Hour()*60+t.Minute())%s.interval == 0
+	}
+
+	if !s.minutes[t.Minute()] || !s.hours[t.Hour()] || !s.months[int(t.Month())] {
+		return false
+	}
+	// cron と同じく、日と曜日の両方が指定されていればどちらかに一致すればよい
+	day, weekday := s.days[t.Day()], s.weekdays[int(t.Weekday())]
+	if s.daysRestricted && s.weekdaysRestricted {
+		return day || weekday
+	}
+	return day && weekday
+}
+
+func parseCron(fields []string) (*Schedule, error) {
+	s := &Schedule{}
+	var err error
+	if s.minutes, _, err = parseField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if s.hours, _, err = parseField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if s.days, s.daysRestricted, err = parseField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("day: %w", err)
+	}
+	if s.months, _, err = parseField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	if s.weekdays, s.weekdaysRestricted, err = parseField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("weekday: %w", err)
+	}
+	// 7 も日曜日
+	if s.weekdays[7] {
+		s.weekdays[0] = true
+	}
+	return s, nil
+}
+
+// parseField parses one cron field ("*", "5", "1-5", "*/15", "0-30/10", comma-separated lists).
+// restricted は "*" 以外が指定されたかどうか
+func parseField(field string, min, max int) (map[int]bool, bool, error) {
+	values := make(map[int]bool)
+	restricted := !strings.HasPrefix(field, "*") // cron と同じく "*/2" なども制限なしとみなす
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return nil, false, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			var err error
+			if i := strings.Index(rangePart, "-"); i >= 0 {
+				lo, err = strconv.Atoi(rangePart[:i])
+				if err == nil {
+					hi, err = strconv.Atoi(rangePart[i+1:])
+				}
+			} else {
+				lo, err = strconv.Atoi(rangePart)
+				hi = lo
+				if step > 1 {
+					hi = max // "5/15" は 5 から最後まで
+				}
+			}
+			if err != nil {
+				return nil, false, fmt.Errorf("invalid value %q", part)
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return nil, false, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			values[v] = true
+		}
+	}
+	return values, restricted, nil
+}
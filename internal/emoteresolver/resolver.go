@@ -0,0 +1,314 @@
+// Package emoteresolver resolves BTTV/FFZ/7TV emote codes that arrive in chat as plain text.
+package emoteresolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	twitch "github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// refreshInterval はエモート一覧を取り直す間隔
+const refreshInterval = time.Hour
+
+// Provider names stored in ChatMessageFragmentEmote.EmoteSetId for third-party emotes
+const (
+	ProviderBTTV = "bttv"
+	Provider7TV  = "7tv"
+	ProviderFFZ  = "ffz"
+)
+
+var (
+	mu         sync.Mutex
+	emotes     map[string]emote // code -> emote
+	loadedFor  string           // 取得済みのブロードキャスターID
+	loadedAt   time.Time
+	loading    bool   // 取得中（重複して取得しない）
+	generation uint64 // Invalidate のたびに増やし、取得中に無効化された結果を捨てる
+	httpClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+func init() {
+	// 機能の切り替えや配信者の変更で一覧を取り直す
+	env.Subscribe(func(changed []string) {
+		Invalidate()
+	}, "ThirdPartyEmotes", "TwitchUserID")
+}
+
+type emote struct {
+	provider string
+	url      string
+}
+
+// IsThirdParty reports whether an emote fragment was synthesized by this package.
+// その場合 Emote.Id には画像のURLが入っている
+func IsThirdParty(e *twitch.ChatMessageFragmentEmote) bool {
+	if e == nil {
+		return false
+	}
+	switch e.EmoteSetId {
+	case ProviderBTTV, Provider7TV, ProviderFFZ:
+		return strings.HasPrefix(e.Id, "https://")
+	}
+	return false
+}
+
+// ReplaceText splits text into words and replaces known third-party emote codes
+// with synthetic emote fragments. 置き換えがない場合は元のテキスト1つだけを返す
+func ReplaceText(text string) []twitch.ChatMessageFragment {
+	table := currentEmotes()
+	if len(table) == 0 || text == "" {
+		return []twitch.ChatMessageFragment{{Type: "text", Text: text}}
+	}
+
+	var out []twitch.ChatMessageFragment
+	var pending strings.Builder
+	flush := func() {
+		if pending.Len() > 0 {
+			out = append(out, twitch.ChatMessageFragment{Type: "text", Text: pending.String()})
+			pending.Reset()
+		}
+	}
+
+	// スペースは保持したまま単語単位で照合する
+	for i, word := range strings.Split(text, " ") {
+		if i > 0 {
+			pending.WriteByte(' ')
+		}
+		e, ok := table[word]
+		if !ok {
+			pending.WriteString(word)
+			continue
+		}
+		flush()
+		out = append(out, twitch.ChatMessageFragment{
+			Type: "emote",
+			Text: word,
+			Emote: &twitch.ChatMessageFragmentEmote{
+				Id:         e.url,
+				EmoteSetId: e.provider,
+			},
+		})
+	}
+	flush()
+	return out
+}
+
+// currentEmotes returns the emote table for the broadcaster, (re)loading it when stale
+func currentEmotes() map[string]emote {
+	if !env.Value.ThirdPartyEmotes || env.Value.TwitchUserID == nil || *env.Value.TwitchUserID == "" {
+		return nil
+	}
+	broadcasterID := *env.Value.TwitchUserID
+
+	mu.Lock()
+	if loadedFor == broadcasterID && time.Since(loadedAt) < refreshInterval {
+		defer mu.Unlock()
+		return emotes
+	}
+	// 他の goroutine が取得中なら待たずに手元の一覧を使う（なければ文字のまま印刷）
+	if loading {
+		defer mu.Unlock()
+		if loadedFor == broadcasterID {
+			return emotes
+		}
+		return nil
+	}
+	loading = true
+	gen := generation
+	mu.Unlock()
+
+	// HTTP の取得中はロックを持たない
+	table := fetchEmotes(broadcasterID)
+
+	mu.Lock()
+	defer mu.Unlock()
+	loading = false
+	if gen != generation {
+		return table
+	}
+	emotes = table
+	loadedFor = broadcasterID
+	loadedAt = time.Now()
+	logger.Info("Third-party emotes loaded", zap.Int("count", len(table)))
+	return emotes
+}
+
+// fetchEmotes downloads the BTTV/FFZ/7TV emote sets for the broadcaster
+func fetchEmotes(broadcasterID string) map[string]emote {
+	table := make(map[string]emote)
+	// 後から読み込んだものが優先（チャンネル固有 > グローバル、7TV > FFZ > BTTV）
+	loaders := []struct {
+		name string
+		load func(string, map[string]emote) error
+	}{
+		{ProviderBTTV, loadBTTV},
+		{ProviderFFZ, loadFFZ},
+		{Provider7TV, load7TV},
+	}
+	for _, l := range loaders {
+		// 取得に失敗したプロバイダーのエモートは文字のまま印刷される
+		if err := l.load(broadcasterID, table); err != nil {
+			logger.Warn("Failed to load third-party emotes", zap.String("provider", l.name), zap.Error(err))
+		}
+	}
+	return table
+}
+
+// Invalidate drops the cached emote sets so they are fetched again on next use
+func Invalidate() {
+	mu.Lock()
+	defer mu.Unlock()
+	loadedFor = ""
+	emotes = nil
+	generation++
+}
+
+func getJSON(url string, v interface{}) error {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// チャンネル未登録は 404 になるので空として扱う
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+type bttvEmote struct {
+	ID   string `json:"id"`
+	Code string `json:"code"`
+}
+
+func loadBTTV(broadcasterID string, table map[string]emote) error {
+	add := func(list []bttvEmote) {
+		for _, e := range list {
+			table[e.Code] = emote{provider: ProviderBTTV, url: "https://cdn.betterttv.net/emote/" + e.ID + "/3x"}
+		}
+	}
+
+	var global []bttvEmote
+	if err := getJSON("https://api.betterttv.net/3/cached/emotes/global", &global); err != nil {
+		return err
+	}
+	add(global)
+
+	var channel struct {
+		ChannelEmotes []bttvEmote `json:"channelEmotes"`
+		SharedEmotes  []bttvEmote `json:"sharedEmotes"`
+	}
+	if err := getJSON("https://api.betterttv.net/3/cached/users/twitch/"+broadcasterID, &channel); err != nil {
+		return err
+	}
+	add(channel.SharedEmotes)
+	add(channel.ChannelEmotes)
+	return nil
+}
+
+func loadFFZ(broadcasterID string, table map[string]emote) error {
+	var room struct {
+		Sets map[string]struct {
+			Emoticons []struct {
+				Name string            `json:"name"`
+				URLs map[string]string `json:"urls"`
+			} `json:"emoticons"`
+		} `json:"sets"`
+	}
+	if err := getJSON("https://api.frankerfacez.com/v1/room/id/"+broadcasterID, &room); err != nil {
+		return err
+	}
+	for _, set := range room.Sets {
+		for _, e := range set.Emoticons {
+			// 一番大きい画像を使う
+			url := e.URLs["4"]
+			if url == "" {
+				url = e.URLs["2"]
+			}
+			if url == "" {
+				url = e.URLs["1"]
+			}
+			if url == "" {
+				continue
+			}
+			if strings.HasPrefix(url, "//") {
+				url = "https:" + url
+			}
+			table[e.Name] = emote{provider: ProviderFFZ, url: url}
+		}
+	}
+	return nil
+}
+
+type sevenTVFile struct {
+	Name       string `json:"name"`
+	StaticName string `json:"static_name"`
+	Width      int    `json:"width"`
+	Format     string `json:"format"`
+}
+
+func load7TV(broadcasterID string, table map[string]emote) error {
+	var user struct {
+		EmoteSet struct {
+			Emotes []struct {
+				Name string `json:"name"`
+				Data struct {
+					Host struct {
+						URL   string        `json:"url"`
+						Files []sevenTVFile `json:"files"`
+					} `json:"host"`
+				} `json:"data"`
+			} `json:"emotes"`
+		} `json:"emote_set"`
+	}
+	if err := getJSON("https://7tv.io/v3/users/twitch/"+broadcasterID, &user); err != nil {
+		return err
+	}
+	for _, e := range user.EmoteSet.Emotes {
+		file := pick7TVFile(e.Data.Host.Files)
+		if file == "" || e.Data.Host.URL == "" {
+			continue
+		}
+		url := e.Data.Host.URL + "/" + file
+		if strings.HasPrefix(url, "//") {
+			url = "https:" + url
+		}
+		table[e.Name] = emote{provider: Provider7TV, url: url}
+	}
+	return nil
+}
+
+// pick7TVFile returns the largest PNG (静止画があればそちら) of a 7TV emote.
+// WebP/AVIF はデコードできないので、PNG がない場合は GIF（1フレーム目を印刷）を使う
+func pick7TVFile(files []sevenTVFile) string {
+	var best string
+	bestWidth := -1
+	for _, format := range []string{"PNG", "GIF"} {
+		for _, f := range files {
+			if !strings.EqualFold(f.Format, format) || f.Width <= bestWidth {
+				continue
+			}
+			best = f.Name
+			if format == "PNG" && f.StaticName != "" {
+				best = f.StaticName
+			}
+			bestWidth = f.Width
+		}
+		if best != "" {
+			return best
+		}
+	}
+	return ""
+}
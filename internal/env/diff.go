@@ -0,0 +1,29 @@
+package env
+
+import "reflect"
+
+// ChangedFields returns the names of the EnvValue fields that differ between before and after.
+// ポインタ型のフィールドは参照先の値で比較する（値そのものは返さないのでシークレットも安全にログ出力できる）
+func ChangedFields(before, after EnvValue) []string {
+	var changed []string
+
+	b := reflect.ValueOf(before)
+	a := reflect.ValueOf(after)
+	for i := 0; i < b.NumField(); i++ {
+		bf, af := b.Field(i), a.Field(i)
+		if bf.Kind() == reflect.Ptr {
+			if bf.IsNil() != af.IsNil() {
+				changed = append(changed, b.Type().Field(i).Name)
+				continue
+			}
+			if bf.IsNil() {
+				continue
+			}
+			bf, af = bf.Elem(), af.Elem()
+		}
+		if !reflect.DeepEqual(bf.Interface(), af.Interface()) {
+			changed = append(changed, b.Type().Field(i).Name)
+		}
+	}
+	return changed
+}
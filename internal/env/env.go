@@ -31,6 +31,73 @@ type EnvValue struct {
 	ClockEnabled          bool
 	DryRunMode            bool
 	RotatePrint           bool
+	ColorPrint            bool
+	FaxTheme              string
+	Locale                string
+	EmoteTransparency     string
+	ClockCondensed        bool
+	FaxSeparator          string
+	EmoteCacheMaxWriters  int
+	AllowSystemFont       bool
+	SingleCharScale       bool
+	SingleCharMaxScale    float32
+	FaxAllowedUsers       string
+	MusicMaxFileMB        int
+	MusicAllowedFormats   string
+	MusicServerSequencing bool
+	PaperWidth            int
+	FontSize              int
+	EmoteCacheMaxMB       int
+	EmoteCacheTTLHours    int
+	MusicTranscode        bool
+	FfmpegPath            string
+	ThirdPartyEmotes      bool
+	UnderlineStyle        string
+	UnderlineThickness    int
+	UnderlineDashLength   int
+	UnderlineDashGap      int
+	QrRecoveryLevel       string
+	QrMaxSize             int
+	QrOnly                bool
+	PrintURLMode          string
+	PrintBeep             bool
+	PrintFollowEnabled    bool
+	PrintCheerEnabled     bool
+	PrintRaidEnabled      bool
+	PrintShoutoutEnabled  bool
+	PrintSubscribeEnabled bool
+	PrintGiftSubEnabled   bool
+	PrintResubEnabled     bool
+	CheerMinBits          int
+	TemplateCheerTitle    string
+	TemplateCheerDetail   string
+	TemplateFollowTitle   string
+	TemplateFollowDetail  string
+	TemplateRaidTitle     string
+	TemplateRaidDetail    string
+	TemplateShoutoutTitle string
+	TemplateShoutoutDetail string
+	TemplateSubscribeTitle string
+	TemplateSubscribeDetail string
+	TemplateGiftSubTitle  string
+	TemplateGiftSubDetail string
+	TemplateResubTitle    string
+	TemplateResubDetail   string
+	PrintQueueFullPolicy  string
+	PrintQueueBlockTimeout int
+	PrintHypeTrainEnabled bool
+	PrintPollEnabled      bool
+	PrintPredictionEnabled bool
+	PrintGoalEnabled      bool
+	APIToken              string
+	AllowedOrigins        string
+	DebugMode             bool
+	LogLevel              string
+	ClockSchedule         string
+	QuietHoursStart       string
+	QuietHoursEnd         string
+	LeaderboardSize       int
+	LeaderboardPeriod     string
 	ServerPort            int
 	TimeZone              string
 	AutoDryRunWhenOffline bool
@@ -48,6 +115,18 @@ func init() {
 		logger.Warn("Failed to load from database, using environment variables", zap.Error(err))
 		loadFromEnvironment()
 	}
+
+	// LOG_LEVEL は起動時と設定変更時にロガーへ反映する
+	applyLogLevel()
+	Subscribe(func(changed []string) {
+		applyLogLevel()
+	}, "LogLevel")
+}
+
+func applyLogLevel() {
+	if err := logger.SetLevel(Value.LogLevel); err != nil {
+		logger.Warn("Invalid LOG_LEVEL, keeping current level", zap.String("value", Value.LogLevel), zap.Error(err))
+	}
 }
 
 func loadDotEnv() {
@@ -160,6 +239,73 @@ func loadFromDatabase() error {
 	clockEnabled, _ := settingsManager.GetRealValue("CLOCK_ENABLED")
 	dryRunMode, _ := settingsManager.GetRealValue("DRY_RUN_MODE")
 	rotatePrint, _ := settingsManager.GetRealValue("ROTATE_PRINT")
+	colorPrint, _ := settingsManager.GetRealValue("COLOR_PRINT")
+	faxTheme, _ := settingsManager.GetRealValue("FAX_THEME")
+	locale, _ := settingsManager.GetRealValue("LOCALE")
+	emoteTransparency, _ := settingsManager.GetRealValue("EMOTE_TRANSPARENCY")
+	clockCondensed, _ := settingsManager.GetRealValue("CLOCK_CONDENSED")
+	faxSeparator, _ := settingsManager.GetRealValue("FAX_SEPARATOR")
+	emoteCacheMaxWriters, _ := settingsManager.GetRealValue("EMOTE_CACHE_MAX_WRITERS")
+	allowSystemFont, _ := settingsManager.GetRealValue("ALLOW_SYSTEM_FONT")
+	singleCharScale, _ := settingsManager.GetRealValue("SINGLE_CHAR_SCALE")
+	singleCharMaxScale, _ := settingsManager.GetRealValue("SINGLE_CHAR_MAX_SCALE")
+	faxAllowedUsers, _ := settingsManager.GetRealValue("FAX_ALLOWED_USERS")
+	musicMaxFileMB, _ := settingsManager.GetRealValue("MUSIC_MAX_FILE_MB")
+	musicAllowedFormats, _ := settingsManager.GetRealValue("MUSIC_ALLOWED_FORMATS")
+	musicServerSequencing, _ := settingsManager.GetRealValue("MUSIC_SERVER_SEQUENCING")
+	paperWidth, _ := settingsManager.GetRealValue("PAPER_WIDTH")
+	fontSize, _ := settingsManager.GetRealValue("FONT_SIZE")
+	emoteCacheMaxMB, _ := settingsManager.GetRealValue("EMOTE_CACHE_MAX_MB")
+	emoteCacheTTLHours, _ := settingsManager.GetRealValue("EMOTE_CACHE_TTL_HOURS")
+	musicTranscode, _ := settingsManager.GetRealValue("MUSIC_TRANSCODE")
+	ffmpegPath, _ := settingsManager.GetRealValue("FFMPEG_PATH")
+	thirdPartyEmotes, _ := settingsManager.GetRealValue("THIRD_PARTY_EMOTES")
+	underlineStyle, _ := settingsManager.GetRealValue("UNDERLINE_STYLE")
+	underlineThickness, _ := settingsManager.GetRealValue("UNDERLINE_THICKNESS")
+	underlineDashLength, _ := settingsManager.GetRealValue("UNDERLINE_DASH_LENGTH")
+	underlineDashGap, _ := settingsManager.GetRealValue("UNDERLINE_DASH_GAP")
+	qrRecoveryLevel, _ := settingsManager.GetRealValue("QR_RECOVERY_LEVEL")
+	qrMaxSize, _ := settingsManager.GetRealValue("QR_MAX_SIZE")
+	qrOnly, _ := settingsManager.GetRealValue("QR_ONLY")
+	printURLMode, _ := settingsManager.GetRealValue("PRINT_URL_MODE")
+	printBeep, _ := settingsManager.GetRealValue("PRINT_BEEP")
+	printFollowEnabled, _ := settingsManager.GetRealValue("PRINT_FOLLOW_ENABLED")
+	printCheerEnabled, _ := settingsManager.GetRealValue("PRINT_CHEER_ENABLED")
+	printRaidEnabled, _ := settingsManager.GetRealValue("PRINT_RAID_ENABLED")
+	printShoutoutEnabled, _ := settingsManager.GetRealValue("PRINT_SHOUTOUT_ENABLED")
+	printSubscribeEnabled, _ := settingsManager.GetRealValue("PRINT_SUBSCRIBE_ENABLED")
+	printGiftSubEnabled, _ := settingsManager.GetRealValue("PRINT_GIFT_SUB_ENABLED")
+	printResubEnabled, _ := settingsManager.GetRealValue("PRINT_RESUB_ENABLED")
+	cheerMinBits, _ := settingsManager.GetRealValue("CHEER_MIN_BITS")
+	templateCheerTitle, _ := settingsManager.GetRealValue("TEMPLATE_CHEER_TITLE")
+	templateCheerDetail, _ := settingsManager.GetRealValue("TEMPLATE_CHEER_DETAIL")
+	templateFollowTitle, _ := settingsManager.GetRealValue("TEMPLATE_FOLLOW_TITLE")
+	templateFollowDetail, _ := settingsManager.GetRealValue("TEMPLATE_FOLLOW_DETAIL")
+	templateRaidTitle, _ := settingsManager.GetRealValue("TEMPLATE_RAID_TITLE")
+	templateRaidDetail, _ := settingsManager.GetRealValue("TEMPLATE_RAID_DETAIL")
+	templateShoutoutTitle, _ := settingsManager.GetRealValue("TEMPLATE_SHOUTOUT_TITLE")
+	templateShoutoutDetail, _ := settingsManager.GetRealValue("TEMPLATE_SHOUTOUT_DETAIL")
+	templateSubscribeTitle, _ := settingsManager.GetRealValue("TEMPLATE_SUBSCRIBE_TITLE")
+	templateSubscribeDetail, _ := settingsManager.GetRealValue("TEMPLATE_SUBSCRIBE_DETAIL")
+	templateGiftSubTitle, _ := settingsManager.GetRealValue("TEMPLATE_GIFT_SUB_TITLE")
+	templateGiftSubDetail, _ := settingsManager.GetRealValue("TEMPLATE_GIFT_SUB_DETAIL")
+	templateResubTitle, _ := settingsManager.GetRealValue("TEMPLATE_RESUB_TITLE")
+	templateResubDetail, _ := settingsManager.GetRealValue("TEMPLATE_RESUB_DETAIL")
+	printQueueFullPolicy, _ := settingsManager.GetRealValue("PRINT_QUEUE_FULL_POLICY")
+	printQueueBlockTimeout, _ := settingsManager.GetRealValue("PRINT_QUEUE_BLOCK_TIMEOUT")
+	printHypeTrainEnabled, _ := settingsManager.GetRealValue("PRINT_HYPE_TRAIN_ENABLED")
+	printPollEnabled, _ := settingsManager.GetRealValue("PRINT_POLL_ENABLED")
+	printPredictionEnabled, _ := settingsManager.GetRealValue("PRINT_PREDICTION_ENABLED")
+	printGoalEnabled, _ := settingsManager.GetRealValue("PRINT_GOAL_ENABLED")
+	apiToken, _ := settingsManager.GetRealValue("API_TOKEN")
+	allowedOrigins, _ := settingsManager.GetRealValue("ALLOWED_ORIGINS")
+	debugMode, _ := settingsManager.GetRealValue("DEBUG_MODE")
+	logLevel, _ := settingsManager.GetRealValue("LOG_LEVEL")
+	clockSchedule, _ := settingsManager.GetRealValue("CLOCK_SCHEDULE")
+	quietHoursStart, _ := settingsManager.GetRealValue("QUIET_HOURS_START")
+	quietHoursEnd, _ := settingsManager.GetRealValue("QUIET_HOURS_END")
+	leaderboardSize, _ := settingsManager.GetRealValue("LEADERBOARD_SIZE")
+	leaderboardPeriod, _ := settingsManager.GetRealValue("LEADERBOARD_PERIOD")
 	timeZone, _ := settingsManager.GetRealValue("TIMEZONE")
 	autoDryRunWhenOffline, _ := settingsManager.GetRealValue("AUTO_DRY_RUN_WHEN_OFFLINE")
 
@@ -189,6 +335,73 @@ func loadFromDatabase() error {
 		ClockEnabled:          clockEnabled == "true",
 		DryRunMode:            dryRunMode == "true",
 		RotatePrint:           rotatePrint == "true",
+		ColorPrint:            colorPrint == "true",
+		FaxTheme:             faxTheme,
+		Locale:               locale,
+		EmoteTransparency:    emoteTransparency,
+		ClockCondensed:       clockCondensed == "true",
+		FaxSeparator:         faxSeparator,
+		EmoteCacheMaxWriters: parseIntStr(emoteCacheMaxWriters),
+		AllowSystemFont:      allowSystemFont == "true",
+		SingleCharScale:      singleCharScale == "true",
+		SingleCharMaxScale:   parseFloatStr(singleCharMaxScale),
+		FaxAllowedUsers:      faxAllowedUsers,
+		MusicMaxFileMB:       parseIntStr(musicMaxFileMB),
+		MusicAllowedFormats:  musicAllowedFormats,
+		MusicServerSequencing: musicServerSequencing == "true",
+		PaperWidth:           parseIntStr(paperWidth),
+		FontSize:             parseIntStr(fontSize),
+		EmoteCacheMaxMB:      parseIntStr(emoteCacheMaxMB),
+		EmoteCacheTTLHours:   parseIntStr(emoteCacheTTLHours),
+		MusicTranscode:       musicTranscode == "true",
+		FfmpegPath:           ffmpegPath,
+		ThirdPartyEmotes:     thirdPartyEmotes == "true",
+		UnderlineStyle:       underlineStyle,
+		UnderlineThickness:   parseIntStr(underlineThickness),
+		UnderlineDashLength:  parseIntStr(underlineDashLength),
+		UnderlineDashGap:     parseIntStr(underlineDashGap),
+		QrRecoveryLevel:      qrRecoveryLevel,
+		QrMaxSize:            parseIntStr(qrMaxSize),
+		QrOnly:               qrOnly == "true",
+		PrintURLMode:         printURLMode,
+		PrintBeep:            printBeep == "true",
+		PrintFollowEnabled:   printFollowEnabled == "true",
+		PrintCheerEnabled:    printCheerEnabled == "true",
+		PrintRaidEnabled:     printRaidEnabled == "true",
+		PrintShoutoutEnabled: printShoutoutEnabled == "true",
+		PrintSubscribeEnabled: printSubscribeEnabled == "true",
+		PrintGiftSubEnabled:  printGiftSubEnabled == "true",
+		PrintResubEnabled:    printResubEnabled == "true",
+		CheerMinBits:         parseIntStr(cheerMinBits),
+		TemplateCheerTitle:   templateCheerTitle,
+		TemplateCheerDetail:  templateCheerDetail,
+		TemplateFollowTitle:  templateFollowTitle,
+		TemplateFollowDetail: templateFollowDetail,
+		TemplateRaidTitle:    templateRaidTitle,
+		TemplateRaidDetail:   templateRaidDetail,
+		TemplateShoutoutTitle: templateShoutoutTitle,
+		TemplateShoutoutDetail: templateShoutoutDetail,
+		TemplateSubscribeTitle: templateSubscribeTitle,
+		TemplateSubscribeDetail: templateSubscribeDetail,
+		TemplateGiftSubTitle: templateGiftSubTitle,
+		TemplateGiftSubDetail: templateGiftSubDetail,
+		TemplateResubTitle:   templateResubTitle,
+		TemplateResubDetail:  templateResubDetail,
+		PrintQueueFullPolicy: printQueueFullPolicy,
+		PrintQueueBlockTimeout: parseIntStr(printQueueBlockTimeout),
+		PrintHypeTrainEnabled: printHypeTrainEnabled == "true",
+		PrintPollEnabled:     printPollEnabled == "true",
+		PrintPredictionEnabled: printPredictionEnabled == "true",
+		PrintGoalEnabled:     printGoalEnabled == "true",
+		APIToken:             apiToken,
+		AllowedOrigins:       allowedOrigins,
+		DebugMode:            debugMode == "true",
+		LogLevel:             logLevel,
+		ClockSchedule:        clockSchedule,
+		QuietHoursStart:      quietHoursStart,
+		QuietHoursEnd:        quietHoursEnd,
+		LeaderboardSize:      parseIntStr(leaderboardSize),
+		LeaderboardPeriod:    leaderboardPeriod,
 		ServerPort:            parseIntStr(*serverPortStr),
 		TimeZone:              timeZone,
 		AutoDryRunWhenOffline: autoDryRunWhenOffline == "true",
@@ -245,6 +458,73 @@ func loadFromEnvironment() {
 	clockEnabled := getEnvOrDefault("CLOCK_ENABLED", "false")
 	dryRunMode := getEnvOrDefault("DRY_RUN_MODE", "true") // セキュリティ上trueをデフォルトに
 	rotatePrint := getEnvOrDefault("ROTATE_PRINT", "false")
+	colorPrint := getEnvOrDefault("COLOR_PRINT", "false")
+	faxTheme := getEnvOrDefault("FAX_THEME", "plain")
+	locale := getEnvOrDefault("LOCALE", "ja-JP")
+	emoteTransparency := getEnvOrDefault("EMOTE_TRANSPARENCY", "over")
+	clockCondensed := getEnvOrDefault("CLOCK_CONDENSED", "false")
+	faxSeparator := getEnvOrDefault("FAX_SEPARATOR", "none")
+	emoteCacheMaxWriters := getEnvOrDefault("EMOTE_CACHE_MAX_WRITERS", "2")
+	allowSystemFont := getEnvOrDefault("ALLOW_SYSTEM_FONT", "false")
+	singleCharScale := getEnvOrDefault("SINGLE_CHAR_SCALE", "true")
+	singleCharMaxScale := getEnvOrDefault("SINGLE_CHAR_MAX_SCALE", "4")
+	faxAllowedUsers := getEnvOrDefault("FAX_ALLOWED_USERS", "")
+	musicMaxFileMB := getEnvOrDefault("MUSIC_MAX_FILE_MB", "50")
+	musicAllowedFormats := getEnvOrDefault("MUSIC_ALLOWED_FORMATS", "mp3,wav,m4a,ogg,flac")
+	musicServerSequencing := getEnvOrDefault("MUSIC_SERVER_SEQUENCING", "false")
+	paperWidth := getEnvOrDefault("PAPER_WIDTH", "384")
+	fontSize := getEnvOrDefault("FONT_SIZE", "32")
+	emoteCacheMaxMB := getEnvOrDefault("EMOTE_CACHE_MAX_MB", "100")
+	emoteCacheTTLHours := getEnvOrDefault("EMOTE_CACHE_TTL_HOURS", "168")
+	musicTranscode := getEnvOrDefault("MUSIC_TRANSCODE", "false")
+	ffmpegPath := getEnvOrDefault("FFMPEG_PATH", "")
+	thirdPartyEmotes := getEnvOrDefault("THIRD_PARTY_EMOTES", "false")
+	underlineStyle := getEnvOrDefault("UNDERLINE_STYLE", "dashed")
+	underlineThickness := getEnvOrDefault("UNDERLINE_THICKNESS", "4")
+	underlineDashLength := getEnvOrDefault("UNDERLINE_DASH_LENGTH", "8")
+	underlineDashGap := getEnvOrDefault("UNDERLINE_DASH_GAP", "4")
+	qrRecoveryLevel := getEnvOrDefault("QR_RECOVERY_LEVEL", "medium")
+	qrMaxSize := getEnvOrDefault("QR_MAX_SIZE", "0")
+	qrOnly := getEnvOrDefault("QR_ONLY", "false")
+	printURLMode := getEnvOrDefault("PRINT_URL_MODE", "image_qr")
+	printBeep := getEnvOrDefault("PRINT_BEEP", "false")
+	printFollowEnabled := getEnvOrDefault("PRINT_FOLLOW_ENABLED", "true")
+	printCheerEnabled := getEnvOrDefault("PRINT_CHEER_ENABLED", "true")
+	printRaidEnabled := getEnvOrDefault("PRINT_RAID_ENABLED", "true")
+	printShoutoutEnabled := getEnvOrDefault("PRINT_SHOUTOUT_ENABLED", "true")
+	printSubscribeEnabled := getEnvOrDefault("PRINT_SUBSCRIBE_ENABLED", "true")
+	printGiftSubEnabled := getEnvOrDefault("PRINT_GIFT_SUB_ENABLED", "true")
+	printResubEnabled := getEnvOrDefault("PRINT_RESUB_ENABLED", "true")
+	cheerMinBits := getEnvOrDefault("CHEER_MIN_BITS", "0")
+	templateCheerTitle := getEnvOrDefault("TEMPLATE_CHEER_TITLE", "")
+	templateCheerDetail := getEnvOrDefault("TEMPLATE_CHEER_DETAIL", "")
+	templateFollowTitle := getEnvOrDefault("TEMPLATE_FOLLOW_TITLE", "")
+	templateFollowDetail := getEnvOrDefault("TEMPLATE_FOLLOW_DETAIL", "")
+	templateRaidTitle := getEnvOrDefault("TEMPLATE_RAID_TITLE", "")
+	templateRaidDetail := getEnvOrDefault("TEMPLATE_RAID_DETAIL", "")
+	templateShoutoutTitle := getEnvOrDefault("TEMPLATE_SHOUTOUT_TITLE", "")
+	templateShoutoutDetail := getEnvOrDefault("TEMPLATE_SHOUTOUT_DETAIL", "")
+	templateSubscribeTitle := getEnvOrDefault("TEMPLATE_SUBSCRIBE_TITLE", "")
+	templateSubscribeDetail := getEnvOrDefault("TEMPLATE_SUBSCRIBE_DETAIL", "")
+	templateGiftSubTitle := getEnvOrDefault("TEMPLATE_GIFT_SUB_TITLE", "")
+	templateGiftSubDetail := getEnvOrDefault("TEMPLATE_GIFT_SUB_DETAIL", "")
+	templateResubTitle := getEnvOrDefault("TEMPLATE_RESUB_TITLE", "")
+	templateResubDetail := getEnvOrDefault("TEMPLATE_RESUB_DETAIL", "")
+	printQueueFullPolicy := getEnvOrDefault("PRINT_QUEUE_FULL_POLICY", "drop_newest")
+	printQueueBlockTimeout := getEnvOrDefault("PRINT_QUEUE_BLOCK_TIMEOUT", "10")
+	printHypeTrainEnabled := getEnvOrDefault("PRINT_HYPE_TRAIN_ENABLED", "true")
+	printPollEnabled := getEnvOrDefault("PRINT_POLL_ENABLED", "true")
+	printPredictionEnabled := getEnvOrDefault("PRINT_PREDICTION_ENABLED", "true")
+	printGoalEnabled := getEnvOrDefault("PRINT_GOAL_ENABLED", "true")
+	apiToken := getEnvOrDefault("API_TOKEN", "")
+	allowedOrigins := getEnvOrDefault("ALLOWED_ORIGINS", "*")
+	debugMode := getEnvOrDefault("DEBUG_MODE", "false")
+	logLevel := getEnvOrDefault("LOG_LEVEL", "info")
+	clockSchedule := getEnvOrDefault("CLOCK_SCHEDULE", "60")
+	quietHoursStart := getEnvOrDefault("QUIET_HOURS_START", "")
+	quietHoursEnd := getEnvOrDefault("QUIET_HOURS_END", "")
+	leaderboardSize := getEnvOrDefault("LEADERBOARD_SIZE", "5")
+	leaderboardPeriod := getEnvOrDefault("LEADERBOARD_PERIOD", "month")
 	serverPort := getEnvOrDefault("SERVER_PORT", "8080")
 	timeZone := getEnvOrDefault("TIMEZONE", "Asia/Tokyo")
 	autoDryRunWhenOffline := getEnvOrDefault("AUTO_DRY_RUN_WHEN_OFFLINE", "false")
@@ -266,6 +546,73 @@ func loadFromEnvironment() {
 		ClockEnabled:          *clockEnabled == "true",
 		DryRunMode:            *dryRunMode == "true",
 		RotatePrint:           *rotatePrint == "true",
+		ColorPrint:            *colorPrint == "true",
+		FaxTheme:             *faxTheme,
+		Locale:               *locale,
+		EmoteTransparency:    *emoteTransparency,
+		ClockCondensed:       *clockCondensed == "true",
+		FaxSeparator:         *faxSeparator,
+		EmoteCacheMaxWriters: parseInt(emoteCacheMaxWriters),
+		AllowSystemFont:      *allowSystemFont == "true",
+		SingleCharScale:      *singleCharScale == "true",
+		SingleCharMaxScale:   parseFloat(singleCharMaxScale),
+		FaxAllowedUsers:      *faxAllowedUsers,
+		MusicMaxFileMB:       parseInt(musicMaxFileMB),
+		MusicAllowedFormats:  *musicAllowedFormats,
+		MusicServerSequencing: *musicServerSequencing == "true",
+		PaperWidth:           parseInt(paperWidth),
+		FontSize:             parseInt(fontSize),
+		EmoteCacheMaxMB:      parseInt(emoteCacheMaxMB),
+		EmoteCacheTTLHours:   parseInt(emoteCacheTTLHours),
+		MusicTranscode:       *musicTranscode == "true",
+		FfmpegPath:           *ffmpegPath,
+		ThirdPartyEmotes:     *thirdPartyEmotes == "true",
+		UnderlineStyle:       *underlineStyle,
+		UnderlineThickness:   parseInt(underlineThickness),
+		UnderlineDashLength:  parseInt(underlineDashLength),
+		UnderlineDashGap:     parseInt(underlineDashGap),
+		QrRecoveryLevel:      *qrRecoveryLevel,
+		QrMaxSize:            parseInt(qrMaxSize),
+		QrOnly:               *qrOnly == "true",
+		PrintURLMode:         *printURLMode,
+		PrintBeep:            *printBeep == "true",
+		PrintFollowEnabled:   *printFollowEnabled == "true",
+		PrintCheerEnabled:    *printCheerEnabled == "true",
+		PrintRaidEnabled:     *printRaidEnabled == "true",
+		PrintShoutoutEnabled: *printShoutoutEnabled == "true",
+		PrintSubscribeEnabled: *printSubscribeEnabled == "true",
+		PrintGiftSubEnabled:  *printGiftSubEnabled == "true",
+		PrintResubEnabled:    *printResubEnabled == "true",
+		CheerMinBits:         parseInt(cheerMinBits),
+		TemplateCheerTitle:   *templateCheerTitle,
+		TemplateCheerDetail:  *templateCheerDetail,
+		TemplateFollowTitle:  *templateFollowTitle,
+		TemplateFollowDetail: *templateFollowDetail,
+		TemplateRaidTitle:    *templateRaidTitle,
+		TemplateRaidDetail:   *templateRaidDetail,
+		TemplateShoutoutTitle: *templateShoutoutTitle,
+		TemplateShoutoutDetail: *templateShoutoutDetail,
+		TemplateSubscribeTitle: *templateSubscribeTitle,
+		TemplateSubscribeDetail: *templateSubscribeDetail,
+		TemplateGiftSubTitle: *templateGiftSubTitle,
+		TemplateGiftSubDetail: *templateGiftSubDetail,
+		TemplateResubTitle:   *templateResubTitle,
+		TemplateResubDetail:  *templateResubDetail,
+		PrintQueueFullPolicy: *printQueueFullPolicy,
+		PrintQueueBlockTimeout: parseInt(printQueueBlockTimeout),
+		PrintHypeTrainEnabled: *printHypeTrainEnabled == "true",
+		PrintPollEnabled:     *printPollEnabled == "true",
+		PrintPredictionEnabled: *printPredictionEnabled == "true",
+		PrintGoalEnabled:     *printGoalEnabled == "true",
+		APIToken:             *apiToken,
+		AllowedOrigins:       *allowedOrigins,
+		DebugMode:            *debugMode == "true",
+		LogLevel:             *logLevel,
+		ClockSchedule:        *clockSchedule,
+		QuietHoursStart:      *quietHoursStart,
+		QuietHoursEnd:        *quietHoursEnd,
+		LeaderboardSize:      parseInt(leaderboardSize),
+		LeaderboardPeriod:    *leaderboardPeriod,
 		ServerPort:            parseInt(serverPort),
 		TimeZone:              *timeZone,
 		AutoDryRunWhenOffline: *autoDryRunWhenOffline == "true",
@@ -290,11 +637,6 @@ func getEnvOrDefault(key, defaultValue string) *string {
 	return &value
 }
 
-// ReloadFromDatabase reloads settings from database
-func ReloadFromDatabase() error {
-	return loadFromDatabase()
-}
-
 func stringPtr(s string) *string {
 	return &s
 }
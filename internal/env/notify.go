@@ -0,0 +1,80 @@
+package env
+
+import (
+	"sync"
+
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// ChangeHandler is called after a reload with the names of the EnvValue fields that changed
+type ChangeHandler func(changed []string)
+
+type subscription struct {
+	fields  map[string]bool // nil はすべての変更を受け取る
+	handler ChangeHandler
+}
+
+var (
+	subscribersMu sync.Mutex
+	subscribers   []subscription
+)
+
+// Subscribe registers handler to be called when a reload changes any of the given
+// EnvValue fields (e.g. "KeepAliveEnabled"). fields を省略するとすべての変更で呼ばれる。
+// ハンドラーには対象フィールドのうち実際に変わったものだけが渡される
+func Subscribe(handler ChangeHandler, fields ...string) {
+	var set map[string]bool
+	if len(fields) > 0 {
+		set = make(map[string]bool, len(fields))
+		for _, f := range fields {
+			set[f] = true
+		}
+	}
+
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	subscribers = append(subscribers, subscription{fields: set, handler: handler})
+}
+
+// ReloadFromDatabase reloads env.Value from the settings database and notifies subscribers of the changed fields
+func ReloadFromDatabase() error {
+	before := Value
+	if err := loadFromDatabase(); err != nil {
+		return err
+	}
+
+	changed := ChangedFields(before, Value)
+	if len(changed) == 0 {
+		logger.Info("Settings reloaded, nothing changed")
+		return nil
+	}
+	logger.Info("Settings reloaded", zap.Strings("changed", changed))
+	notifySubscribers(changed)
+	return nil
+}
+
+// notifySubscribers calls every subscriber whose fields intersect changed.
+// ハンドラー内で Subscribe しても問題ないよう、ロックを外してから呼び出す
+func notifySubscribers(changed []string) {
+	subscribersMu.Lock()
+	subs := make([]subscription, len(subscribers))
+	copy(subs, subscribers)
+	subscribersMu.Unlock()
+
+	for _, s := range subs {
+		relevant := changed
+		if s.fields != nil {
+			relevant = nil
+			for _, f := range changed {
+				if s.fields[f] {
+					relevant = append(relevant, f)
+				}
+			}
+		}
+		if len(relevant) == 0 {
+			continue
+		}
+		s.handler(relevant)
+	}
+}
@@ -0,0 +1,271 @@
+package faxmanager
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// faxHistoryMax は履歴に残す件数の上限（古いものから削除する）
+const faxHistoryMax = 1000
+
+// ErrCursorNotFound is returned when the before cursor does not match a fax in the history
+var ErrCursorNotFound = errors.New("cursor fax not found")
+
+// InitDB creates the fax_history table and cleans up images left over from the previous run.
+// 再起動で削除タイマーが失われるため、保持期間を過ぎた画像はここで削除し、残りは改めて削除を予約する
+func InitDB() error {
+	db := localdb.GetDB()
+	if db == nil {
+		return errors.New("database not initialized")
+	}
+
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS fax_history (
+		seq INTEGER PRIMARY KEY AUTOINCREMENT,
+		id TEXT NOT NULL UNIQUE,
+		user_name TEXT NOT NULL DEFAULT '',
+		message TEXT NOT NULL DEFAULT '',
+		image_url TEXT NOT NULL DEFAULT '',
+		color_path TEXT NOT NULL DEFAULT '',
+		mono_path TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL,
+		featured INTEGER NOT NULL DEFAULT 0
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create fax_history table: %w", err)
+	}
+	// 既存のテーブルにカラムを追加（既に存在する場合のエラーは無視）
+	db.Exec(`ALTER TABLE fax_history ADD COLUMN featured INTEGER NOT NULL DEFAULT 0`)
+
+	if err := initStatsTable(db); err != nil {
+		return err
+	}
+
+	loadFeatured(db)
+	restoreRetention(db)
+
+	logger.Info("Fax history database initialized")
+	return nil
+}
+
+// recordHistory stores the fax in the history table (DB未初期化の場合は何もしない)
+func recordHistory(fax *Fax) {
+	db := localdb.GetDB()
+	if db == nil {
+		return
+	}
+
+	res, err := db.Exec(`INSERT INTO fax_history (id, user_name, message, image_url, color_path, mono_path, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		fax.ID, fax.UserName, fax.Message, fax.ImageURL, fax.ColorPath, fax.MonoPath, fax.Timestamp.UnixMilli())
+	if err != nil {
+		logger.Error("Failed to record fax history", zap.String("id", fax.ID), zap.Error(err))
+		return
+	}
+	if seq, err := res.LastInsertId(); err == nil {
+		fax.Seq = seq
+	}
+	recordStats(db, fax)
+
+	// featured の FAX は上限を超えても残す
+	if _, err := db.Exec(`DELETE FROM fax_history WHERE seq <= (SELECT MAX(seq) FROM fax_history) - ? AND featured = 0`, faxHistoryMax); err != nil {
+		logger.Warn("Failed to prune fax history", zap.Error(err))
+	}
+}
+
+// HistoryEntry is a past fax. 画像は保持期間（10分）を過ぎると削除されるので ImageAvailable で確認する
+type HistoryEntry struct {
+	Fax
+	ImageAvailable bool
+}
+
+// ListHistory returns up to limit faxes newest first. before is the ID of the last fax of the
+// previous page (空なら最新から)。次のページがない場合 next は空
+func ListHistory(limit int, before string) (entries []HistoryEntry, next string, err error) {
+	db := localdb.GetDB()
+	if db == nil {
+		return nil, "", errors.New("database not initialized")
+	}
+
+	var rows *sql.Rows
+	if before == "" {
+		rows, err = db.Query(historyColumns+` FROM fax_history ORDER BY seq DESC LIMIT ?`, limit+1)
+	} else {
+		var seq int64
+		if err := db.QueryRow(`SELECT seq FROM fax_history WHERE id = ?`, before).Scan(&seq); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, "", ErrCursorNotFound
+			}
+			return nil, "", err
+		}
+		rows, err = db.Query(historyColumns+` FROM fax_history WHERE seq < ? ORDER BY seq DESC LIMIT ?`, seq, limit+1)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	entries, err = scanHistory(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// 1件多く取得して次のページの有無を判定する
+	if len(entries) > limit {
+		entries = entries[:limit]
+		next = entries[limit-1].ID
+	}
+	return entries, next, nil
+}
+
+// HistorySince returns up to limit faxes recorded after seq, oldest first.
+// 再接続したオーバーレイに、切断中に届いた FAX を再送するために使う
+func HistorySince(seq int64, limit int) ([]HistoryEntry, error) {
+	db := localdb.GetDB()
+	if db == nil {
+		return nil, errors.New("database not initialized")
+	}
+
+	// 新しい方から limit 件を取って古い順に並べ直す
+	rows, err := db.Query(`SELECT * FROM (`+historyColumns+` FROM fax_history WHERE seq > ? ORDER BY seq DESC LIMIT ?)
+		ORDER BY seq ASC`, seq, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanHistory(rows)
+}
+
+// LatestHistorySeq returns the newest history seq (履歴がない場合は0)
+func LatestHistorySeq() int64 {
+	db := localdb.GetDB()
+	if db == nil {
+		return 0
+	}
+	var seq sql.NullInt64
+	if err := db.QueryRow(`SELECT MAX(seq) FROM fax_history`).Scan(&seq); err != nil {
+		return 0
+	}
+	return seq.Int64
+}
+
+const historyColumns = `SELECT seq, id, user_name, message, image_url, color_path, mono_path, created_at, featured`
+
+func scanHistory(rows *sql.Rows) ([]HistoryEntry, error) {
+	defer rows.Close()
+
+	var entries []HistoryEntry
+	for rows.Next() {
+		var e HistoryEntry
+		var createdAt int64
+		if err := rows.Scan(&e.Seq, &e.ID, &e.UserName, &e.Message, &e.ImageURL, &e.ColorPath, &e.MonoPath, &createdAt, &e.Featured); err != nil {
+			return nil, err
+		}
+		e.Timestamp = time.UnixMilli(createdAt)
+		e.ImageAvailable = fileExists(e.ColorPath)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// loadFromHistory restores a fax whose images still exist (e.g. after a restart) so it can be served by ID
+func loadFromHistory(id string) (*Fax, bool) {
+	fax, exists := historyRecord(id)
+	if !exists || !fileExists(fax.ColorPath) {
+		return nil, false
+	}
+	return fax, true
+}
+
+// historyRecord returns the history record of a fax, whether or not its images still exist
+func historyRecord(id string) (*Fax, bool) {
+	db := localdb.GetDB()
+	if db == nil {
+		return nil, false
+	}
+
+	fax := &Fax{}
+	var createdAt int64
+	err := db.QueryRow(historyColumns+` FROM fax_history WHERE id = ?`, id).
+		Scan(&fax.Seq, &fax.ID, &fax.UserName, &fax.Message, &fax.ImageURL, &fax.ColorPath, &fax.MonoPath, &createdAt, &fax.Featured)
+	if err != nil {
+		return nil, false
+	}
+	fax.Timestamp = time.UnixMilli(createdAt)
+	return fax, true
+}
+
+// loadFeatured restores the featured fax selected before the restart
+func loadFeatured(db *sql.DB) {
+	var id string
+	if err := db.QueryRow(`SELECT id FROM fax_history WHERE featured = 1 ORDER BY seq DESC LIMIT 1`).Scan(&id); err != nil {
+		return
+	}
+	mu.Lock()
+	featuredID = id
+	mu.Unlock()
+}
+
+// saveFeatured persists the featured fax in fax_history (空なら選択を解除)
+func saveFeatured(id string) {
+	db := localdb.GetDB()
+	if db == nil {
+		return
+	}
+	if _, err := db.Exec(`UPDATE fax_history SET featured = CASE WHEN id = ? THEN 1 ELSE 0 END WHERE featured = 1 OR id = ?`, id, id); err != nil {
+		logger.Warn("Failed to save featured fax", zap.String("id", id), zap.Error(err))
+	}
+}
+
+// restoreRetention deletes images older than the retention period and reschedules the rest.
+// featured の FAX の画像は選択が外れるまで残す
+func restoreRetention(db *sql.DB) {
+	rows, err := db.Query(`SELECT id, color_path, mono_path, created_at FROM fax_history WHERE created_at >= ? AND featured = 0`,
+		time.Now().Add(-24*time.Hour).UnixMilli())
+	if err != nil {
+		logger.Warn("Failed to read fax history for cleanup", zap.Error(err))
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, colorPath, monoPath string
+		var createdAt int64
+		if err := rows.Scan(&id, &colorPath, &monoPath, &createdAt); err != nil {
+			continue
+		}
+		if !fileExists(colorPath) && !fileExists(monoPath) {
+			continue
+		}
+
+		remaining := faxRetention - time.Since(time.UnixMilli(createdAt))
+		if remaining <= 0 {
+			removeImages(colorPath, monoPath)
+			continue
+		}
+		time.AfterFunc(remaining, func() {
+			if !isFeatured(id) {
+				removeImages(colorPath, monoPath)
+			}
+		})
+	}
+}
+
+func removeImages(paths ...string) {
+	for _, p := range paths {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			logger.Error("Failed to delete fax image", zap.String("path", p), zap.Error(err))
+		}
+	}
+}
+
+func fileExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -13,19 +13,46 @@ import (
 	"go.uber.org/zap"
 )
 
+// システムが送る FAX の送信者名（視聴者からの FAX と区別し、統計には数えない）
+const (
+	SenderClock       = "🕐 Clock"
+	SenderLeaderboard = "🏆 Leaderboard"
+	SenderImage       = "🖼 Image"
+)
+
+// systemSenders lists the sender names of faxes that do not come from viewers
+var systemSenders = []string{SenderClock, SenderLeaderboard, SenderImage}
+
+// IsSystemSender reports whether userName is a system sender rather than a viewer
+func IsSystemSender(userName string) bool {
+	for _, s := range systemSenders {
+		if userName == s {
+			return true
+		}
+	}
+	return false
+}
+
 type Fax struct {
 	ID        string
+	Seq       int64 // 履歴の通し番号（単調増加）。SSE のイベントIDに使う。DB未初期化の場合は0
 	UserName  string
 	Message   string
 	ImageURL  string
 	Timestamp time.Time
 	ColorPath string
 	MonoPath  string
+	Featured  bool // 「今日のFAX」として選ばれているか
+	expired   bool // 保持期限を過ぎたが featured のため削除を保留している
 }
 
+// faxRetention は FAX 画像を残しておく時間
+const faxRetention = 10 * time.Minute
+
 var (
 	faxStorage = make(map[string]*Fax)
 	mu         sync.RWMutex
+	featuredID string
 )
 
 // GenerateID creates a new nanoid
@@ -65,6 +92,9 @@ func SaveFax(userName string, message string, imageURL string, colorImg, monoImg
 	faxStorage[id] = fax
 	mu.Unlock()
 
+	// 履歴はDBに残す（画像は保持期間で削除される）
+	recordHistory(fax)
+
 	// Schedule deletion after 10 minutes
 	scheduleDeletion(id)
 
@@ -85,17 +115,127 @@ func GetFax(id string) (*Fax, bool) {
 	return fax, exists
 }
 
+// LookupFax returns a fax from memory or, after the retention period, from fax_history.
+// 履歴から返した FAX は画像が既に削除されている場合がある
+func LookupFax(id string) (*HistoryEntry, bool) {
+	fax, exists := GetFax(id)
+	if !exists {
+		fax, exists = historyRecord(id)
+	}
+	if !exists {
+		return nil, false
+	}
+	return &HistoryEntry{Fax: *fax, ImageAvailable: fileExists(fax.ColorPath)}, true
+}
+
 // scheduleDeletion sets up automatic deletion after 10 minutes
 func scheduleDeletion(id string) {
-	time.AfterFunc(10*time.Minute, func() {
+	time.AfterFunc(faxRetention, func() {
 		deleteFax(id)
 	})
 }
 
+// SetFeatured marks a fax as the featured "fax of the day", replacing the previous one.
+// featured の FAX は10分経っても画像が削除されず、選択が外れた時点で削除される。
+// 選択は fax_history に保存するので再起動後も維持され、保持期間を過ぎた FAX も履歴から選べる
+func SetFeatured(id string) (*Fax, error) {
+	fax, exists := GetFax(id)
+	if !exists {
+		fax, exists = historyRecord(id)
+	}
+	if !exists {
+		return nil, fmt.Errorf("fax not found")
+	}
+
+	mu.Lock()
+	prevID := featuredID
+	if prev, ok := faxStorage[prevID]; ok && prevID != id {
+		prev.Featured = false
+	}
+	fax.Featured = true
+	featuredID = id
+	mu.Unlock()
+
+	saveFeatured(id)
+
+	if prevID != "" && prevID != id {
+		releaseFeatured(prevID)
+	}
+	logger.Info("Fax featured", zap.String("id", id), zap.String("previous", prevID))
+	return fax, nil
+}
+
+// ClearFeatured removes the featured mark
+func ClearFeatured() {
+	mu.Lock()
+	prevID := featuredID
+	if prev, ok := faxStorage[prevID]; ok {
+		prev.Featured = false
+	}
+	featuredID = ""
+	mu.Unlock()
+
+	saveFeatured("")
+
+	if prevID != "" {
+		releaseFeatured(prevID)
+	}
+}
+
+// GetFeatured returns the featured fax, if any
+func GetFeatured() (*Fax, bool) {
+	mu.RLock()
+	id := featuredID
+	fax, exists := faxStorage[id]
+	mu.RUnlock()
+	if id == "" || exists {
+		return fax, exists
+	}
+
+	// メモリから消えた FAX は履歴から返す
+	fax, exists = historyRecord(id)
+	if exists {
+		fax.Featured = true
+	}
+	return fax, exists
+}
+
+// isFeatured reports whether id is the featured fax
+func isFeatured(id string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return featuredID == id
+}
+
+// releaseFeatured deletes the images of a previously featured fax whose retention period has already passed
+func releaseFeatured(id string) {
+	mu.RLock()
+	fax, exists := faxStorage[id]
+	expired := exists && fax.expired
+	mu.RUnlock()
+	if exists {
+		if expired {
+			deleteFax(id)
+		}
+		return
+	}
+
+	// 再起動前・保持期間後に選ばれた FAX はメモリにないので履歴から判断する
+	if fax, ok := historyRecord(id); ok && time.Since(fax.Timestamp) >= faxRetention {
+		removeImages(fax.ColorPath, fax.MonoPath)
+	}
+}
+
 // deleteFax removes fax from storage and deletes files
 func deleteFax(id string) {
 	mu.Lock()
 	fax, exists := faxStorage[id]
+	if exists && fax.Featured {
+		// featured の間は残しておく
+		fax.expired = true
+		mu.Unlock()
+		return
+	}
 	if exists {
 		delete(faxStorage, id)
 	}
@@ -119,6 +259,10 @@ func deleteFax(id string) {
 // GetImagePath returns the path for the requested image type
 func GetImagePath(id string, imageType string) (string, error) {
 	fax, exists := GetFax(id)
+	if !exists {
+		// 再起動前に保存され、まだ保持期間内の FAX
+		fax, exists = loadFromHistory(id)
+	}
 	if !exists {
 		return "", fmt.Errorf("fax not found")
 	}
@@ -0,0 +1,127 @@
+package faxmanager
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// faxStatsRetention は統計用の記録を残す期間（最も長い集計範囲の1か月に余裕を持たせる）
+const faxStatsRetention = 62 * 24 * time.Hour
+
+// initStatsTable creates fax_stats, which keeps one row per fax for aggregation.
+// fax_history は faxHistoryMax 件で削除されるため、件数は別のテーブルで数える
+func initStatsTable(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS fax_stats (
+		created_at INTEGER NOT NULL,
+		user_name TEXT NOT NULL DEFAULT ''
+	)`); err != nil {
+		return fmt.Errorf("failed to create fax_stats table: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_fax_stats_created_at ON fax_stats(created_at)`); err != nil {
+		return fmt.Errorf("failed to create fax_stats index: %w", err)
+	}
+
+	// 初回は残っている履歴から作る（時計などシステムの FAX は除く）
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM fax_stats`).Scan(&count); err == nil && count == 0 {
+		db.Exec(`INSERT INTO fax_stats (created_at, user_name) SELECT created_at, user_name FROM fax_history
+			WHERE user_name NOT IN (?, ?, ?)`, SenderClock, SenderLeaderboard, SenderImage)
+	}
+	return nil
+}
+
+// recordStats counts a viewer's fax in fax_stats and drops records older than faxStatsRetention.
+// 時計・リーダーボード・画像はシステムの FAX なので数えない
+func recordStats(db *sql.DB, fax *Fax) {
+	if IsSystemSender(fax.UserName) {
+		return
+	}
+	if _, err := db.Exec(`INSERT INTO fax_stats (created_at, user_name) VALUES (?, ?)`,
+		fax.Timestamp.UnixMilli(), fax.UserName); err != nil {
+		logger.Warn("Failed to record fax stats", zap.String("id", fax.ID), zap.Error(err))
+		return
+	}
+	if _, err := db.Exec(`DELETE FROM fax_stats WHERE created_at < ?`,
+		time.Now().Add(-faxStatsRetention).UnixMilli()); err != nil {
+		logger.Warn("Failed to prune fax stats", zap.Error(err))
+	}
+}
+
+// Contributor is a user and how many faxes they sent
+type Contributor struct {
+	UserName string `json:"username"`
+	Count    int    `json:"count"`
+}
+
+// DailyCount is the number of faxes on one day (loc の日付)
+type DailyCount struct {
+	Date  string `json:"date"` // 2006-01-02
+	Count int    `json:"count"`
+}
+
+// Stats summarizes the fax history since a point in time
+type Stats struct {
+	Since           time.Time     `json:"since"`
+	Total           int           `json:"total"`
+	UniqueUsers     int           `json:"unique_users"`
+	Daily           []DailyCount  `json:"daily"`
+	TopContributors []Contributor `json:"top_contributors"`
+}
+
+// HistoryStats aggregates faxes recorded at or after since.
+// fax_history の件数上限に関係なく数えるが、faxStatsRetention より古い FAX は含まない
+func HistoryStats(since time.Time, loc *time.Location, top int) (*Stats, error) {
+	db := localdb.GetDB()
+	if db == nil {
+		return nil, errors.New("database not initialized")
+	}
+
+	stats := &Stats{Since: since, Daily: []DailyCount{}, TopContributors: []Contributor{}}
+	sinceMilli := since.UnixMilli()
+
+	if err := db.QueryRow(`SELECT COUNT(*), COUNT(DISTINCT user_name) FROM fax_stats WHERE created_at >= ?`,
+		sinceMilli).Scan(&stats.Total, &stats.UniqueUsers); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT user_name, COUNT(*) AS n FROM fax_stats WHERE created_at >= ?
+		GROUP BY user_name ORDER BY n DESC, MAX(created_at) DESC LIMIT ?`, sinceMilli, top)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var c Contributor
+		if err := rows.Scan(&c.UserName, &c.Count); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		stats.TopContributors = append(stats.TopContributors, c)
+	}
+	rows.Close()
+
+	// 日付はタイムゾーンで変わるので SQL ではなくここで数える
+	rows, err = db.Query(`SELECT created_at FROM fax_stats WHERE created_at >= ? ORDER BY created_at`, sinceMilli)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var createdAt int64
+		if err := rows.Scan(&createdAt); err != nil {
+			return nil, err
+		}
+		date := time.UnixMilli(createdAt).In(loc).Format("2006-01-02")
+		if n := len(stats.Daily); n > 0 && stats.Daily[n-1].Date == date {
+			stats.Daily[n-1].Count++
+		} else {
+			stats.Daily = append(stats.Daily, DailyCount{Date: date, Count: 1})
+		}
+	}
+	return stats, rows.Err()
+}
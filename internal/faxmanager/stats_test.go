@@ -0,0 +1,109 @@
+package faxmanager
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
+)
+
+// useTestDB swaps the shared database for a temporary one with the fax tables created
+func useTestDB(t *testing.T) {
+	t.Helper()
+	prev := localdb.DBClient
+	localdb.DBClient = nil
+	db, err := localdb.SetupDB(filepath.Join(t.TempDir(), "local.db"))
+	if err != nil {
+		t.Fatalf("SetupDB: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		localdb.DBClient = prev
+	})
+	if err := InitDB(); err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+}
+
+func TestHistoryStatsCountsBeyondHistoryLimit(t *testing.T) {
+	useTestDB(t)
+
+	now := time.Now()
+	const total = faxHistoryMax + 50
+	for i := 0; i < total; i++ {
+		user := "alice"
+		if i%2 == 1 {
+			user = "bob"
+		}
+		recordHistory(&Fax{
+			ID:        fmt.Sprintf("fax-%d", i),
+			UserName:  user,
+			Timestamp: now.Add(-time.Duration(total-i) * time.Second),
+		})
+	}
+	// 統計の保持期間より古い FAX は数えない
+	recordHistory(&Fax{ID: "old", UserName: "carol", Timestamp: now.Add(-2 * faxStatsRetention)})
+
+	stats, err := HistoryStats(now.Add(-24*time.Hour), time.UTC, 10)
+	if err != nil {
+		t.Fatalf("HistoryStats: %v", err)
+	}
+	if stats.Total != total {
+		t.Errorf("total = %d, want %d", stats.Total, total)
+	}
+	if stats.UniqueUsers != 2 {
+		t.Errorf("unique users = %d, want 2", stats.UniqueUsers)
+	}
+	if len(stats.TopContributors) != 2 || stats.TopContributors[0].Count+stats.TopContributors[1].Count != total {
+		t.Errorf("top contributors = %+v, want alice and bob summing to %d", stats.TopContributors, total)
+	}
+	var daily int
+	for _, d := range stats.Daily {
+		daily += d.Count
+	}
+	if daily != total {
+		t.Errorf("daily counts sum to %d, want %d", daily, total)
+	}
+}
+
+func TestHistoryStatsExcludesSystemFaxes(t *testing.T) {
+	useTestDB(t)
+
+	now := time.Now()
+	senders := []string{SenderClock, "alice", SenderLeaderboard, SenderImage, "bob", SenderClock}
+	for i, user := range senders {
+		recordHistory(&Fax{ID: fmt.Sprintf("fax-%d", i), UserName: user, Timestamp: now.Add(-time.Duration(i) * time.Minute)})
+	}
+
+	check := func(t *testing.T) {
+		t.Helper()
+		stats, err := HistoryStats(now.Add(-time.Hour), time.UTC, 10)
+		if err != nil {
+			t.Fatalf("HistoryStats: %v", err)
+		}
+		if stats.Total != 2 || stats.UniqueUsers != 2 {
+			t.Errorf("total = %d, unique users = %d, want 2 and 2", stats.Total, stats.UniqueUsers)
+		}
+		for _, c := range stats.TopContributors {
+			if IsSystemSender(c.UserName) {
+				t.Errorf("top contributors include system sender %q", c.UserName)
+			}
+		}
+	}
+
+	t.Run("recorded", check)
+
+	// 統計テーブルを履歴から作り直す場合もシステムの FAX は数えない
+	t.Run("backfilled", func(t *testing.T) {
+		db := localdb.GetDB()
+		if _, err := db.Exec(`DELETE FROM fax_stats`); err != nil {
+			t.Fatalf("clear fax_stats: %v", err)
+		}
+		if err := initStatsTable(db); err != nil {
+			t.Fatalf("initStatsTable: %v", err)
+		}
+		check(t)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"sync"
 
+	"github.com/nantokaworks/twitch-overlay/internal/env"
 	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
 	"github.com/nantokaworks/twitch-overlay/internal/shared/paths"
 	"go.uber.org/zap"
@@ -83,6 +84,12 @@ func GetFont(defaultFontData []byte) ([]byte, error) {
 	
 	// カスタムフォントが設定されていない場合
 	if customFontPath == "" {
+		// ALLOW_SYSTEM_FONT が有効ならOSのフォントで代用する
+		if env.Value.AllowSystemFont {
+			if data, err := systemFont(); err == nil {
+				return data, nil
+			}
+		}
 		return nil, fmt.Errorf("no custom font configured: please upload a font file (TTF/OTF) via the settings page")
 	}
 	
@@ -179,6 +186,7 @@ func SaveCustomFont(filename string, data io.Reader, size int64) error {
 	// 更新
 	customFontPath = finalPath
 	fontCache = font
+	markFontValid()
 	
 	logger.Info("Custom font saved successfully", 
 		zap.String("filename", filename),
@@ -206,12 +214,35 @@ func DeleteCustomFont() error {
 	// リセット
 	customFontPath = ""
 	fontCache = nil
+	markFontValid()
 	
 	logger.Info("Custom font deleted successfully")
 	
 	return nil
 }
 
+// ValidateCurrentFont はフォントファイルを読み込み、パースできるか確認します
+func ValidateCurrentFont() error {
+	mu.RLock()
+	path := customFontPath
+	mu.RUnlock()
+
+	if path == "" {
+		return ErrNoCustomFont
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read font file: %w", err)
+	}
+
+	if _, err := opentype.Parse(data); err != nil {
+		return fmt.Errorf("failed to parse font: %w", err)
+	}
+
+	return nil
+}
+
 // GetCurrentFontInfo は現在のフォント情報を返します
 func GetCurrentFontInfo() map[string]interface{} {
 	mu.RLock()
@@ -231,6 +262,7 @@ func GetCurrentFontInfo() map[string]interface{} {
 			info["modifiedAt"] = stat.ModTime().Format("2006-01-02 15:04:05")
 		}
 	}
+	fontHealthInfo(info)
 	
 	return info
 }
@@ -0,0 +1,93 @@
+package fontmanager
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+	"golang.org/x/image/font/opentype"
+)
+
+// fontHealth は描画時にフォントのパースに失敗した状態を保持する
+var (
+	healthMu    sync.Mutex
+	fontErr     error
+	fontErrAt   time.Time
+	fallbackLog bool // キャッシュへのフォールバックをログ出力済みか
+)
+
+// ParseCurrentFont returns the current font parsed for rendering.
+// ファイルが壊れていてパースできない場合は、エラーを1回だけ記録してフォントを無効とマークし、
+// 起動時/アップロード時にパースしたキャッシュがあればそれで描画を続ける
+func ParseCurrentFont() (*opentype.Font, error) {
+	data, err := GetFont(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := opentype.Parse(data)
+	if err == nil {
+		markFontValid()
+		return f, nil
+	}
+
+	markFontInvalid(err)
+
+	mu.RLock()
+	cached := fontCache
+	mu.RUnlock()
+	if cached != nil {
+		healthMu.Lock()
+		if !fallbackLog {
+			logger.Warn("Rendering with cached font until the font file is re-uploaded")
+			fallbackLog = true
+		}
+		healthMu.Unlock()
+		return cached, nil
+	}
+	return nil, fmt.Errorf("font file is corrupted, please re-upload it from the settings page: %w", err)
+}
+
+// FontError returns the parse error recorded during rendering, or nil when the font is healthy
+func FontError() error {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	return fontErr
+}
+
+// markFontInvalid records a parse failure, logging only the first occurrence
+func markFontInvalid(err error) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	if fontErr == nil {
+		logger.Error("Font file could not be parsed; please re-upload the font", zap.Error(err))
+		fontErrAt = time.Now()
+	}
+	fontErr = err
+}
+
+// markFontValid clears the invalid state (e.g. after the file was fixed or re-uploaded)
+func markFontValid() {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	if fontErr != nil {
+		logger.Info("Font file is readable again")
+	}
+	fontErr = nil
+	fontErrAt = time.Time{}
+	fallbackLog = false
+}
+
+// fontHealthInfo adds the invalid-font warning to GetCurrentFontInfo
+func fontHealthInfo(info map[string]interface{}) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	info["invalid"] = fontErr != nil
+	if fontErr != nil {
+		info["error"] = fontErr.Error()
+		info["errorAt"] = fontErrAt.Format("2006-01-02 15:04:05")
+		info["warning"] = "フォントファイルが破損しています。設定ページから再アップロードしてください"
+	}
+}
@@ -0,0 +1,105 @@
+package fontmanager
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+	"golang.org/x/image/font/opentype"
+)
+
+var (
+	systemFontOnce sync.Once
+	systemFontData []byte
+	systemFontErr  error
+)
+
+// systemFont returns the system default font, looked up once and cached
+func systemFont() ([]byte, error) {
+	systemFontOnce.Do(func() {
+		systemFontData, systemFontErr = getSystemDefaultFont()
+	})
+	return systemFontData, systemFontErr
+}
+
+// SystemFontAvailable reports whether ALLOW_SYSTEM_FONT is enabled and a usable system font exists
+func SystemFontAvailable() bool {
+	if !env.Value.AllowSystemFont {
+		return false
+	}
+	_, err := systemFont()
+	return err == nil
+}
+
+// getSystemDefaultFont はOSのデフォルトフォントを取得します
+func getSystemDefaultFont() ([]byte, error) {
+	var fontPaths []string
+	var triedPaths []string
+
+	switch runtime.GOOS {
+	case "darwin": // macOS
+		fontPaths = []string{
+			// TTFファイルを優先
+			"/System/Library/Fonts/Supplemental/Arial.ttf",
+			"/System/Library/Fonts/Supplemental/Arial Unicode.ttf",
+			"/System/Library/Fonts/Supplemental/Courier New.ttf",
+			"/System/Library/Fonts/Supplemental/Georgia.ttf",
+			"/System/Library/Fonts/Supplemental/Times New Roman.ttf",
+			"/System/Library/Fonts/Supplemental/Verdana.ttf",
+			// 標準フォント
+			"/System/Library/Fonts/Avenir.ttc",
+			"/System/Library/Fonts/Helvetica.ttc",
+			"/System/Library/Fonts/Times.ttc",
+			"/System/Library/Fonts/Courier.ttc",
+			// 日本語フォント（TTCファイル）
+			"/System/Library/Fonts/Hiragino Sans GB.ttc",
+			"/System/Library/Fonts/ヒラギノ角ゴ ProN W3.ttc",
+		}
+	case "windows":
+		fontPaths = []string{
+			// TTFファイルを優先
+			"C:\\Windows\\Fonts\\arial.ttf",
+			"C:\\Windows\\Fonts\\times.ttf",
+			"C:\\Windows\\Fonts\\cour.ttf",
+			"C:\\Windows\\Fonts\\verdana.ttf",
+			// 日本語フォント
+			"C:\\Windows\\Fonts\\YuGothM.ttc",
+			"C:\\Windows\\Fonts\\msgothic.ttc",
+		}
+	default: // Linux and others
+		fontPaths = []string{
+			// TTFファイルを優先
+			"/usr/share/fonts/truetype/liberation/LiberationSans-Regular.ttf",
+			"/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
+			"/usr/share/fonts/truetype/ubuntu/Ubuntu-R.ttf",
+			"/usr/share/fonts/truetype/noto/NotoSans-Regular.ttf",
+			// 日本語フォント
+			"/usr/share/fonts/opentype/noto/NotoSansCJK-Regular.ttc",
+		}
+	}
+
+	// Try each font path
+	for _, path := range fontPaths {
+		triedPaths = append(triedPaths, path)
+		if data, err := os.ReadFile(path); err == nil {
+			// TTCなどパースできないファイルはスキップ
+			if _, err := opentype.Parse(data); err != nil {
+				continue
+			}
+			logger.Info("Using system font", zap.String("path", path))
+			return data, nil
+		}
+	}
+
+	// エラー時は試したパスを全て出力
+	logger.Error("No suitable font found on system",
+		zap.Strings("tried_paths", triedPaths),
+		zap.String("os", runtime.GOOS),
+		zap.String("solution", "Please upload a custom font via the settings page or install system fonts"))
+
+	return nil, fmt.Errorf("no suitable font found on system. Please either: 1) Upload a custom font via the settings page (/settings), or 2) Install system fonts (e.g., 'sudo apt-get install fonts-liberation fonts-dejavu' on Ubuntu/Debian)")
+}
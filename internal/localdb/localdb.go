@@ -55,6 +55,19 @@ func SetupDB(dbPath string) (*sql.DB, error) {
 	db.Exec(`ALTER TABLE settings ADD COLUMN is_required BOOLEAN NOT NULL DEFAULT false`)
 	db.Exec(`ALTER TABLE settings ADD COLUMN description TEXT`)
 
+	// settings_historyテーブルを追加（設定変更の履歴。シークレットはマスクして記録する）
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS settings_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		key TEXT NOT NULL,
+		old_value TEXT NOT NULL DEFAULT '',
+		new_value TEXT NOT NULL DEFAULT '',
+		changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return nil, err
+	}
+	db.Exec(`CREATE INDEX IF NOT EXISTS idx_settings_history_key ON settings_history(key, id)`)
+
 	// playback_stateテーブルを追加
 	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS playback_state (
 		id INTEGER PRIMARY KEY,
@@ -71,9 +84,54 @@ func SetupDB(dbPath string) (*sql.DB, error) {
 		return nil, err
 	}
 
+	// print_jobsテーブルを追加（印刷待ちのジョブ。再起動後に印刷キューへ戻す）
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS print_jobs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		mono_path TEXT NOT NULL,
+		color_path TEXT NOT NULL DEFAULT '',
+		printer_address TEXT NOT NULL DEFAULT '',
+		priority INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return nil, err
+	}
+
+	// app_stateテーブルを追加（再起動後も引き継ぐ内部状態。設定ではないので settings とは分ける）
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS app_state (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return nil, err
+	}
+
 	return db, nil
 }
 
+// GetState returns a persisted internal state value (存在しない場合は ok=false)
+func GetState(key string) (string, bool) {
+	if DBClient == nil {
+		return "", false
+	}
+	var value string
+	if err := DBClient.QueryRow(`SELECT value FROM app_state WHERE key = ?`, key).Scan(&value); err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// SetState persists an internal state value
+func SetState(key, value string) error {
+	if DBClient == nil {
+		return sql.ErrConnDone
+	}
+	_, err := DBClient.Exec(`INSERT INTO app_state (key, value, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = CURRENT_TIMESTAMP`, key, value)
+	return err
+}
+
 // GetDB は現在のデータベース接続を返します
 func GetDB() *sql.DB {
 	return DBClient
@@ -0,0 +1,105 @@
+package music
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// findTrackByHash returns the track whose file content has the given SHA-256 hash.
+// SaveTrack がロックを持ったまま呼ぶので m.mu は取らない
+func findTrackByHash(contentHash string) (*Track, error) {
+	db := localdb.GetDB()
+	if db == nil {
+		return nil, errors.New("database not initialized")
+	}
+
+	var track Track
+	var createdAt string
+	err := db.QueryRow(`SELECT id, filename, title, artist, album, duration, has_artwork, gain_db, created_at
+			  FROM tracks WHERE content_hash = ? LIMIT 1`, contentHash).Scan(
+		&track.ID,
+		&track.Filename,
+		&track.Title,
+		&track.Artist,
+		&track.Album,
+		&track.Duration,
+		&track.HasArtwork,
+		&track.GainDB,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	track.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return &track, nil
+}
+
+// backfillContentHashes computes content_hash for tracks uploaded before duplicate detection existed
+func backfillContentHashes() {
+	db := localdb.GetDB()
+	if db == nil {
+		return
+	}
+
+	rows, err := db.Query(`SELECT id, filename FROM tracks WHERE content_hash IS NULL OR content_hash = ''`)
+	if err != nil {
+		logger.Warn("Failed to list tracks for content hash backfill", zap.Error(err))
+		return
+	}
+	type pending struct{ id, filename string }
+	var tracks []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.filename); err == nil {
+			tracks = append(tracks, p)
+		}
+	}
+	rows.Close()
+
+	if len(tracks) == 0 {
+		return
+	}
+
+	updated := 0
+	for _, t := range tracks {
+		ext := strings.ToLower(filepath.Ext(t.filename))
+		hash, err := hashFile(filepath.Join(getTracksDir(), t.id+ext))
+		if err != nil {
+			logger.Warn("Failed to hash track file", zap.String("id", t.id), zap.Error(err))
+			continue
+		}
+		if _, err := db.Exec(`UPDATE tracks SET content_hash = ? WHERE id = ?`, hash, t.id); err != nil {
+			logger.Warn("Failed to save content hash", zap.String("id", t.id), zap.Error(err))
+			continue
+		}
+		updated++
+	}
+
+	logger.Info("Track content hashes backfilled", zap.Int("updated", updated), zap.Int("total", len(tracks)))
+}
+
+// hashFile returns the hex SHA-256 of a file's content
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
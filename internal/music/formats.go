@@ -0,0 +1,88 @@
+package music
+
+import (
+	"strings"
+
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+)
+
+const defaultMaxFileMB = 50
+
+// AudioFormat is an audio file type that can be uploaded and streamed
+type AudioFormat struct {
+	Ext         string // 拡張子（ドットなし、小文字）
+	ContentType string
+}
+
+// KnownFormats lists every format the player can handle.
+// 新しい形式を追加する場合はここに追加し、MUSIC_ALLOWED_FORMATS で有効化する
+var KnownFormats = []AudioFormat{
+	{Ext: "mp3", ContentType: "audio/mpeg"},
+	{Ext: "wav", ContentType: "audio/wav"},
+	{Ext: "m4a", ContentType: "audio/mp4"},
+	{Ext: "ogg", ContentType: "audio/ogg"},
+	{Ext: "flac", ContentType: "audio/flac"},
+}
+
+// MaxFileSize returns the upload size limit in bytes (MUSIC_MAX_FILE_MB)
+func MaxFileSize() int64 {
+	mb := env.Value.MusicMaxFileMB
+	if mb <= 0 {
+		mb = defaultMaxFileMB
+	}
+	return int64(mb) * 1024 * 1024
+}
+
+// LookupFormat returns the known format for a file extension (with or without the dot)
+func LookupFormat(ext string) (AudioFormat, bool) {
+	ext = strings.TrimPrefix(strings.ToLower(ext), ".")
+	for _, f := range KnownFormats {
+		if f.Ext == ext {
+			return f, true
+		}
+	}
+	return AudioFormat{}, false
+}
+
+// AllowedFormats returns the formats enabled by MUSIC_ALLOWED_FORMATS.
+// 未設定の場合は KnownFormats をすべて許可する
+func AllowedFormats() []AudioFormat {
+	if strings.TrimSpace(env.Value.MusicAllowedFormats) == "" {
+		return KnownFormats
+	}
+	var formats []AudioFormat
+	for _, ext := range strings.Split(env.Value.MusicAllowedFormats, ",") {
+		if f, ok := LookupFormat(strings.TrimSpace(ext)); ok {
+			formats = append(formats, f)
+		}
+	}
+	return formats
+}
+
+// IsAllowedFormat reports whether a file with the given extension may be uploaded
+func IsAllowedFormat(ext string) bool {
+	ext = strings.TrimPrefix(strings.ToLower(ext), ".")
+	for _, f := range AllowedFormats() {
+		if f.Ext == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedFormatNames returns the allowed formats for error messages (e.g. "MP3/WAV/M4A")
+func AllowedFormatNames() string {
+	var names []string
+	for _, f := range AllowedFormats() {
+		names = append(names, strings.ToUpper(f.Ext))
+	}
+	return strings.Join(names, "/")
+}
+
+// ContentTypeFor returns the Content-Type for a track file extension
+func ContentTypeFor(ext string) string {
+	if f, ok := LookupFormat(ext); ok {
+		return f.ContentType
+	}
+	return "audio/mpeg"
+}
@@ -0,0 +1,24 @@
+package music
+
+// MaxGainDB limits how much a track's volume is boosted or cut (±dB)
+const MaxGainDB = 12.0
+
+// ClampGain limits a gain value to ±MaxGainDB
+func ClampGain(gainDB float64) float64 {
+	if gainDB > MaxGainDB {
+		return MaxGainDB
+	}
+	if gainDB < -MaxGainDB {
+		return -MaxGainDB
+	}
+	return gainDB
+}
+
+// TrackGain returns the clamped gain for a track (0 dB if unknown)
+func (m *Manager) TrackGain(trackID string) float64 {
+	track, err := m.GetTrack(trackID)
+	if err != nil {
+		return 0
+	}
+	return ClampGain(track.GainDB)
+}
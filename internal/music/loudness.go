@@ -0,0 +1,239 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os/exec"
+	"regexp"
+	"strconv"
+
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// targetLoudness は音量を揃える基準（LUFS、配信サービスの一般的な基準に合わせる）
+const targetLoudness = -14.0
+
+// ebur128 フィルタの集計出力から統合ラウドネス（I）を取り出す
+var integratedLoudnessRe = regexp.MustCompile(`I:\s+(-?[0-9.]+|-inf)\s+LUFS`)
+
+// scheduleLoudnessAnalysis measures a freshly uploaded track's loudness in the background and stores gain_db.
+// 解析できない（ffmpeg がない・失敗した）場合は 0 dB のまま（アップロード自体は失敗させない）
+func scheduleLoudnessAnalysis(trackID, srcPath string) {
+	go func() {
+		loudness, err := analyzeLoudness(srcPath)
+		if errors.Is(err, ErrFFmpegUnavailable) {
+			logger.Debug("ffmpeg not found; skipping loudness analysis", zap.String("id", trackID))
+			return
+		}
+		if err != nil {
+			logger.Warn("Failed to analyze track loudness", zap.String("id", trackID), zap.Error(err))
+			return
+		}
+
+		gain := ClampGain(math.Round((targetLoudness-loudness)*10) / 10)
+		if err := saveTrackGain(trackID, gain); err != nil {
+			logger.Warn("Failed to save track gain", zap.String("id", trackID), zap.Error(err))
+			return
+		}
+		logger.Info("Track loudness analyzed",
+			zap.String("id", trackID),
+			zap.Float64("loudness_lufs", loudness),
+			zap.Float64("gain_db", gain))
+	}()
+}
+
+// analyzeLoudness returns the integrated loudness (LUFS) of a track.
+// WAV はその場でデコードして計算し、それ以外の形式（または未対応の WAV）は ffmpeg を使う
+func analyzeLoudness(src string) (float64, error) {
+	if canDecodeInProcess(src) {
+		loudness, err := measureLoudnessPCM(src)
+		if !errors.Is(err, errUnsupportedWAV) {
+			return loudness, err
+		}
+	}
+
+	ffmpeg := ffmpegPath()
+	if ffmpeg == "" {
+		return 0, ErrFFmpegUnavailable
+	}
+	return measureLoudness(ffmpeg, src)
+}
+
+// measureLoudnessPCM computes the integrated loudness (LUFS) of a WAV file per ITU-R BS.1770
+// (K 特性フィルタ、400ms ブロック・75% オーバーラップ、-70 LUFS の絶対ゲートと -10 LU の相対ゲート)
+func measureLoudnessPCM(src string) (float64, error) {
+	w, err := openWAV(src)
+	if err != nil {
+		return 0, err
+	}
+	defer w.Close()
+
+	filters := make([]kWeighting, w.channels)
+	weights := make([]float64, w.channels)
+	for ch := range filters {
+		filters[ch] = newKWeighting(float64(w.sampleRate))
+		weights[ch] = channelWeight(ch, w.channels)
+	}
+
+	// 100ms ごとの重み付き二乗和（4つで1ブロック）
+	subBlockLen := w.sampleRate / 10
+	var subBlocks []float64
+	var sum float64
+	n := 0
+	frame := make([]float64, w.channels)
+	for {
+		err := w.readFrame(frame)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		for ch, v := range frame {
+			y := filters[ch].process(v)
+			sum += weights[ch] * y * y
+		}
+		n++
+		if n == subBlockLen {
+			subBlocks = append(subBlocks, sum)
+			sum, n = 0, 0
+		}
+	}
+	return gatedLoudness(subBlocks, 4*subBlockLen)
+}
+
+// gatedLoudness applies the absolute and relative gates to 400ms blocks built from 100ms sub-blocks
+func gatedLoudness(subBlocks []float64, blockLen int) (float64, error) {
+	if len(subBlocks) < 4 {
+		return 0, errors.New("track is too short")
+	}
+	blocks := make([]float64, 0, len(subBlocks)-3)
+	for i := 0; i+4 <= len(subBlocks); i++ {
+		blocks = append(blocks, (subBlocks[i]+subBlocks[i+1]+subBlocks[i+2]+subBlocks[i+3])/float64(blockLen))
+	}
+
+	meanAbove := func(threshold float64) float64 {
+		var total float64
+		count := 0
+		for _, p := range blocks {
+			if p > threshold {
+				total += p
+				count++
+			}
+		}
+		if count == 0 {
+			return 0
+		}
+		return total / float64(count)
+	}
+
+	absolute := loudnessToPower(-70)
+	mean := meanAbove(absolute)
+	if mean == 0 {
+		return 0, errors.New("track is silent")
+	}
+	relative := mean * math.Pow(10, -10.0/10)
+	mean = meanAbove(math.Max(absolute, relative))
+	return -0.691 + 10*math.Log10(mean), nil
+}
+
+// loudnessToPower converts a block loudness (LUFS) back to its mean square power
+func loudnessToPower(lufs float64) float64 {
+	return math.Pow(10, (lufs+0.691)/10)
+}
+
+// channelWeight returns the BS.1770 weight of a channel (5.1 の LFE は除外し、サラウンドは +1.5 dB)
+func channelWeight(ch, channels int) float64 {
+	if channels == 6 {
+		switch ch {
+		case 3:
+			return 0
+		case 4, 5:
+			return 1.41
+		}
+	}
+	return 1
+}
+
+// biquad is a direct form II transposed second-order IIR filter
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	z1, z2             float64
+}
+
+func (f *biquad) process(x float64) float64 {
+	y := f.b0*x + f.z1
+	f.z1 = f.b1*x - f.a1*y + f.z2
+	f.z2 = f.b2*x - f.a2*y
+	return y
+}
+
+// kWeighting is the BS.1770 K-weighting filter (高域シェルフ + ハイパス)
+type kWeighting [2]biquad
+
+// newKWeighting derives the K-weighting coefficients for any sample rate (libebur128 と同じ式)
+func newKWeighting(sampleRate float64) kWeighting {
+	var k kWeighting
+
+	f0, gain, q := 1681.974450955533, 3.999843853973347, 0.7071752369554196
+	K := math.Tan(math.Pi * f0 / sampleRate)
+	vh := math.Pow(10, gain/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + K/q + K*K
+	k[0] = biquad{
+		b0: (vh + vb*K/q + K*K) / a0,
+		b1: 2 * (K*K - vh) / a0,
+		b2: (vh - vb*K/q + K*K) / a0,
+		a1: 2 * (K*K - 1) / a0,
+		a2: (1 - K/q + K*K) / a0,
+	}
+
+	f0, q = 38.13547087602444, 0.5003270373238773
+	K = math.Tan(math.Pi * f0 / sampleRate)
+	a0 = 1 + K/q + K*K
+	k[1] = biquad{
+		b0: 1,
+		b1: -2,
+		b2: 1,
+		a1: 2 * (K*K - 1) / a0,
+		a2: (1 - K/q + K*K) / a0,
+	}
+	return k
+}
+
+func (k *kWeighting) process(x float64) float64 {
+	return k[1].process(k[0].process(x))
+}
+
+// measureLoudness returns the integrated loudness (LUFS) of an audio file using ffmpeg's ebur128 filter
+func measureLoudness(ffmpeg, src string) (float64, error) {
+	cmd := exec.Command(ffmpeg, "-hide_banner", "-nostats", "-i", src, "-vn", "-af", "ebur128=framelog=quiet", "-f", "null", "-")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return 0, fmt.Errorf("ffmpeg: %w", err)
+	}
+
+	// 集計は最後に出力される
+	matches := integratedLoudnessRe.FindAllSubmatch(out, -1)
+	if len(matches) == 0 {
+		return 0, errors.New("integrated loudness not found in ffmpeg output")
+	}
+	value := string(matches[len(matches)-1][1])
+	if value == "-inf" {
+		return 0, errors.New("track is silent")
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+func saveTrackGain(trackID string, gainDB float64) error {
+	db := localdb.GetDB()
+	if db == nil {
+		return errors.New("database not initialized")
+	}
+	_, err := db.Exec(`UPDATE tracks SET gain_db = ? WHERE id = ?`, gainDB, trackID)
+	return err
+}
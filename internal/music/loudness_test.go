@@ -0,0 +1,96 @@
+package music
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestWAV writes 16-bit PCM samples (interleaved) as a WAV file
+func writeTestWAV(t *testing.T, sampleRate, channels int, samples []int16) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.wav")
+
+	dataSize := len(samples) * 2
+	buf := make([]byte, 0, 44+dataSize)
+	buf = append(buf, "RIFF"...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(36+dataSize))
+	buf = append(buf, "WAVEfmt "...)
+	buf = binary.LittleEndian.AppendUint32(buf, 16)
+	buf = binary.LittleEndian.AppendUint16(buf, wavFormatPCM)
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(channels))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(sampleRate))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(sampleRate*channels*2))
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(channels*2))
+	buf = binary.LittleEndian.AppendUint16(buf, 16)
+	buf = append(buf, "data"...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(dataSize))
+	for _, s := range samples {
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(s))
+	}
+
+	if err := os.WriteFile(path, buf, 0644); err != nil {
+		t.Fatalf("write WAV: %v", err)
+	}
+	return path
+}
+
+// sineSamples returns seconds of a sine wave at the given amplitude (0〜1) on every channel
+func sineSamples(sampleRate, channels int, freq, amplitude, seconds float64) []int16 {
+	frames := int(float64(sampleRate) * seconds)
+	samples := make([]int16, 0, frames*channels)
+	for i := 0; i < frames; i++ {
+		v := int16(math.Round(amplitude * math.MaxInt16 * math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate))))
+		for ch := 0; ch < channels; ch++ {
+			samples = append(samples, v)
+		}
+	}
+	return samples
+}
+
+func TestMeasureLoudnessPCM(t *testing.T) {
+	tests := []struct {
+		name       string
+		sampleRate int
+		channels   int
+		amplitude  float64
+		want       float64
+	}{
+		// BS.1770: 1kHz 付近の 0 dBFS の正弦波は1チャンネルあたり -3.01 LUFS
+		{"mono -20 dBFS at 48kHz", 48000, 1, 0.1, -23.01},
+		{"stereo -20 dBFS at 48kHz", 48000, 2, 0.1, -20.0},
+		{"mono -20 dBFS at 44.1kHz", 44100, 1, 0.1, -23.01},
+		{"mono -6 dBFS at 44.1kHz", 44100, 1, 0.5, -9.03},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestWAV(t, tt.sampleRate, tt.channels, sineSamples(tt.sampleRate, tt.channels, 997, tt.amplitude, 5))
+			got, err := measureLoudnessPCM(path)
+			if err != nil {
+				t.Fatalf("measureLoudnessPCM: %v", err)
+			}
+			if math.Abs(got-tt.want) > 0.1 {
+				t.Errorf("loudness = %.2f LUFS, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeasureLoudnessPCMErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []int16
+	}{
+		{"silent", make([]int16, 48000*2)},
+		{"too short", sineSamples(48000, 1, 997, 0.5, 0.3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := measureLoudnessPCM(writeTestWAV(t, 48000, 1, tt.samples)); err == nil {
+				t.Error("measureLoudnessPCM succeeded, want an error")
+			}
+		})
+	}
+}
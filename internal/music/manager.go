@@ -2,6 +2,7 @@ package music
 
 import (
 	"crypto/sha256"
+	"database/sql"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -22,7 +23,7 @@ var (
 	ErrInvalidFormat = errors.New("invalid audio format")
 	ErrFileTooLarge  = errors.New("file too large")
 	ErrNotFound      = errors.New("track not found")
-	MaxFileSize      = int64(50 * 1024 * 1024) // 50MB
+	ErrDuplicate     = errors.New("track already exists")
 )
 
 type Track struct {
@@ -33,6 +34,7 @@ type Track struct {
 	Album      string    `json:"album"`
 	Duration   int       `json:"duration"`
 	HasArtwork bool      `json:"has_artwork"`
+	GainDB     float64   `json:"gain_db"` // 音量を揃えるための補正値（dB）
 	CreatedAt  time.Time `json:"created_at"`
 }
 
@@ -75,12 +77,12 @@ func (m *Manager) SaveTrack(filename string, reader io.Reader, size int64) (*Tra
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if size > MaxFileSize {
+	if size > MaxFileSize() {
 		return nil, ErrFileTooLarge
 	}
 
 	ext := strings.ToLower(filepath.Ext(filename))
-	if ext != ".mp3" && ext != ".wav" && ext != ".m4a" && ext != ".ogg" {
+	if !IsAllowedFormat(ext) {
 		return nil, ErrInvalidFormat
 	}
 
@@ -102,12 +104,23 @@ func (m *Manager) SaveTrack(filename string, reader io.Reader, size int64) (*Tra
 	}
 	defer file.Close()
 
-	// Copy and save file
-	_, err = io.Copy(file, reader)
+	// Copy and save file（同時に内容のハッシュを計算して重複アップロードを検出する）
+	contentHasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, contentHasher), reader)
 	if err != nil {
+		os.Remove(trackPath)
 		return nil, fmt.Errorf("failed to write track file: %w", err)
 	}
 	file.Close()
+	contentHash := hex.EncodeToString(contentHasher.Sum(nil))
+
+	if existing, err := findTrackByHash(contentHash); err == nil {
+		os.Remove(trackPath)
+		logger.Info("Duplicate track upload rejected",
+			zap.String("filename", filename),
+			zap.String("existing_id", existing.ID))
+		return existing, ErrDuplicate
+	}
 	
 	// Extract metadata after file is written
 	metadata, err := ExtractMetadata(trackPath)
@@ -142,7 +155,7 @@ func (m *Manager) SaveTrack(filename string, reader io.Reader, size int64) (*Tra
 	}
 
 	// Save to database
-	if err := m.saveTrackToDB(track); err != nil {
+	if err := m.saveTrackToDB(track, contentHash); err != nil {
 		os.Remove(trackPath)
 		return nil, fmt.Errorf("failed to save track to database: %w", err)
 	}
@@ -152,6 +165,12 @@ func (m *Manager) SaveTrack(filename string, reader io.Reader, size int64) (*Tra
 		zap.String("title", track.Title),
 		zap.String("artist", track.Artist))
 
+	// 必要ならWeb再生用のMP3を作成（バックグラウンド）
+	scheduleTranscode(trackID, trackPath)
+
+	// 曲ごとの音量補正を計算（バックグラウンド、完了までは 0 dB）
+	scheduleLoudnessAnalysis(trackID, trackPath)
+
 	return track, nil
 }
 
@@ -165,7 +184,7 @@ func (m *Manager) GetTrack(trackID string) (*Track, error) {
 	}
 
 	var track Track
-	query := `SELECT id, filename, title, artist, album, duration, has_artwork, created_at 
+	query := `SELECT id, filename, title, artist, album, duration, has_artwork, gain_db, created_at 
 			  FROM tracks WHERE id = ?`
 	
 	var createdAt string
@@ -177,6 +196,7 @@ func (m *Manager) GetTrack(trackID string) (*Track, error) {
 		&track.Album,
 		&track.Duration,
 		&track.HasArtwork,
+		&track.GainDB,
 		&createdAt,
 	)
 	
@@ -188,6 +208,34 @@ func (m *Manager) GetTrack(trackID string) (*Track, error) {
 	return &track, nil
 }
 
+// UpdateTrackMetadata corrects a track's title, artist and album.
+// 空文字のフィールドは変更しない（誤って既存の値を消さないため）
+func (m *Manager) UpdateTrackMetadata(trackID, title, artist, album string) (*Track, error) {
+	m.mu.Lock()
+	db := localdb.GetDB()
+	if db == nil {
+		m.mu.Unlock()
+		return nil, errors.New("database not initialized")
+	}
+
+	result, err := db.Exec(`UPDATE tracks SET
+			  title = COALESCE(NULLIF(?, ''), title),
+			  artist = COALESCE(NULLIF(?, ''), artist),
+			  album = COALESCE(NULLIF(?, ''), album)
+			  WHERE id = ?`,
+		strings.TrimSpace(title), strings.TrimSpace(artist), strings.TrimSpace(album), trackID)
+	m.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update track metadata: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, ErrNotFound
+	}
+
+	logger.Info("Track metadata updated", zap.String("id", trackID))
+	return m.GetTrack(trackID)
+}
+
 func (m *Manager) GetAllTracks() ([]*Track, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -197,7 +245,7 @@ func (m *Manager) GetAllTracks() ([]*Track, error) {
 		return nil, errors.New("database not initialized")
 	}
 
-	query := `SELECT id, filename, title, artist, album, duration, has_artwork, created_at 
+	query := `SELECT id, filename, title, artist, album, duration, has_artwork, gain_db, created_at 
 			  FROM tracks ORDER BY created_at DESC`
 	
 	rows, err := db.Query(query)
@@ -219,6 +267,7 @@ func (m *Manager) GetAllTracks() ([]*Track, error) {
 			&track.Album,
 			&track.Duration,
 			&track.HasArtwork,
+			&track.GainDB,
 			&createdAt,
 		)
 		
@@ -238,30 +287,57 @@ func (m *Manager) DeleteTrack(trackID string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Get track info first
-	track, err := m.GetTrack(trackID)
-	if err != nil {
+	db := localdb.GetDB()
+	if db == nil {
+		return errors.New("database not initialized")
+	}
+
+	// Get track info first（m.mu を保持しているので GetTrack は使わない）
+	var filename string
+	if err := db.QueryRow("SELECT filename FROM tracks WHERE id = ?", trackID).Scan(&filename); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
 		return err
 	}
 
 	// Delete files
-	ext := strings.ToLower(filepath.Ext(track.Filename))
+	ext := strings.ToLower(filepath.Ext(filename))
 	trackPath := filepath.Join(getTracksDir(), trackID+ext)
 	artworkPath := filepath.Join(getArtworkDir(), trackID+".jpg")
 
 	os.Remove(trackPath)
+	os.Remove(webVersionPath(trackID))
 	os.Remove(artworkPath)
+	removeWaveformCache(trackID)
 
-	// Delete from database
-	db := localdb.GetDB()
-	if db == nil {
-		return errors.New("database not initialized")
+	tx, err := db.Begin()
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
-	_, err = db.Exec("DELETE FROM tracks WHERE id = ?", trackID)
+	// SQLite は foreign_keys が無効だと CASCADE が効かないため、
+	// プレイリストからの削除と位置の詰め直しを明示的に行う
+	playlistIDs, err := playlistsContainingTrack(tx, trackID)
 	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM playlist_tracks WHERE track_id = ?", trackID); err != nil {
+		return fmt.Errorf("failed to remove track from playlists: %w", err)
+	}
+	for _, playlistID := range playlistIDs {
+		if err := compactPlaylistPositions(tx, playlistID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("DELETE FROM tracks WHERE id = ?", trackID); err != nil {
 		return fmt.Errorf("failed to delete track from database: %w", err)
 	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
 
 	logger.Info("Track deleted", zap.String("id", trackID))
 	return nil
@@ -344,14 +420,14 @@ func (m *Manager) GetArtworkPath(trackID string) (string, error) {
 	return artworkPath, nil
 }
 
-func (m *Manager) saveTrackToDB(track *Track) error {
+func (m *Manager) saveTrackToDB(track *Track, contentHash string) error {
 	db := localdb.GetDB()
 	if db == nil {
 		return errors.New("database not initialized")
 	}
 
-	query := `INSERT INTO tracks (id, filename, title, artist, album, duration, has_artwork, created_at)
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO tracks (id, filename, title, artist, album, duration, has_artwork, gain_db, created_at, content_hash)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	
 	_, err := db.Exec(query,
 		track.ID,
@@ -361,7 +437,9 @@ func (m *Manager) saveTrackToDB(track *Track) error {
 		track.Album,
 		track.Duration,
 		track.HasArtwork,
+		track.GainDB,
 		track.CreatedAt.Format(time.RFC3339),
+		contentHash,
 	)
 	
 	return err
@@ -383,12 +461,22 @@ func InitMusicDB() error {
 		album TEXT,
 		duration INTEGER DEFAULT 0,
 		has_artwork BOOLEAN DEFAULT 0,
-		created_at TEXT NOT NULL
+		gain_db REAL DEFAULT 0,
+		created_at TEXT NOT NULL,
+		content_hash TEXT
 	)`
 
 	if _, err := db.Exec(tracksTable); err != nil {
 		return fmt.Errorf("failed to create tracks table: %w", err)
 	}
+	// 既存のtracksテーブルにカラムを追加（既に存在する場合のエラーは無視）
+	db.Exec(`ALTER TABLE tracks ADD COLUMN gain_db REAL DEFAULT 0`)
+	db.Exec(`ALTER TABLE tracks ADD COLUMN content_hash TEXT`)
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_tracks_content_hash ON tracks(content_hash)`); err != nil {
+		return fmt.Errorf("failed to create content_hash index: %w", err)
+	}
+	// 既存の曲のハッシュはバックグラウンドで計算する（曲数が多いと時間がかかるため）
+	go backfillContentHashes()
 
 	// Create playlists table
 	playlistsTable := `
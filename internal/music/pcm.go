@@ -0,0 +1,190 @@
+package music
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WAV の形式コード（fmt チャンクの audio format）
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatExtensible = 0xFFFE
+)
+
+var errUnsupportedWAV = errors.New("unsupported WAV encoding")
+
+// canDecodeInProcess reports whether the track can be decoded without ffmpeg.
+// 標準ライブラリだけでデコードできるのは非圧縮の WAV のみ（MP3/M4A/OGG/FLAC は ffmpeg が必要）
+func canDecodeInProcess(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".wav")
+}
+
+// wavReader streams PCM frames from a WAV file, normalized to [-1, 1]
+type wavReader struct {
+	f              *os.File
+	r              *bufio.Reader
+	sampleRate     int
+	channels       int
+	bytesPerSample int
+	float          bool
+	remaining      int64 // data チャンクの残りバイト数（-1 はファイル末尾まで）
+	buf            []byte
+}
+
+// openWAV opens a WAV file and positions the reader at the start of the sample data
+func openWAV(path string) (*wavReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	w := &wavReader{f: f, r: bufio.NewReaderSize(f, 64*1024)}
+	if err := w.readHeader(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *wavReader) readHeader() error {
+	var riff [12]byte
+	if _, err := io.ReadFull(w.r, riff[:]); err != nil {
+		return fmt.Errorf("read RIFF header: %w", err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return errors.New("not a RIFF/WAVE file")
+	}
+
+	haveFmt := false
+	for {
+		var hdr [8]byte
+		if _, err := io.ReadFull(w.r, hdr[:]); err != nil {
+			return fmt.Errorf("data chunk not found: %w", err)
+		}
+		id := string(hdr[0:4])
+		size := int64(binary.LittleEndian.Uint32(hdr[4:8]))
+
+		switch id {
+		case "fmt ":
+			body := make([]byte, size)
+			if _, err := io.ReadFull(w.r, body); err != nil {
+				return fmt.Errorf("read fmt chunk: %w", err)
+			}
+			if err := w.parseFormat(body); err != nil {
+				return err
+			}
+			haveFmt = true
+		case "data":
+			if !haveFmt {
+				return errors.New("data chunk before fmt chunk")
+			}
+			w.remaining = size
+			// ストリーミングで書き出された WAV はサイズが 0 / 0xFFFFFFFF のことがある
+			if size == 0 || size == math.MaxUint32 {
+				w.remaining = -1
+			}
+			return nil
+		default:
+			if _, err := w.r.Discard(int(size)); err != nil {
+				return fmt.Errorf("skip %q chunk: %w", id, err)
+			}
+		}
+		// チャンクは2バイト境界に揃えられている
+		if size%2 == 1 {
+			w.r.Discard(1)
+		}
+	}
+}
+
+func (w *wavReader) parseFormat(body []byte) error {
+	if len(body) < 16 {
+		return errors.New("fmt chunk too short")
+	}
+	format := binary.LittleEndian.Uint16(body[0:2])
+	w.channels = int(binary.LittleEndian.Uint16(body[2:4]))
+	w.sampleRate = int(binary.LittleEndian.Uint32(body[4:8]))
+	bits := int(binary.LittleEndian.Uint16(body[14:16]))
+
+	if format == wavFormatExtensible {
+		if len(body) < 26 {
+			return errors.New("extensible fmt chunk too short")
+		}
+		// SubFormat GUID の先頭2バイトが実際の形式コード
+		format = binary.LittleEndian.Uint16(body[24:26])
+	}
+
+	switch {
+	case format == wavFormatPCM && (bits == 8 || bits == 16 || bits == 24 || bits == 32):
+	case format == wavFormatFloat && (bits == 32 || bits == 64):
+		w.float = true
+	default:
+		return fmt.Errorf("%w: format %d, %d bits", errUnsupportedWAV, format, bits)
+	}
+	if w.channels <= 0 || w.sampleRate <= 0 {
+		return errors.New("invalid channel count or sample rate")
+	}
+	w.bytesPerSample = bits / 8
+	w.buf = make([]byte, w.bytesPerSample*w.channels)
+	return nil
+}
+
+// frameCount returns the number of frames in the data chunk (不明な場合は -1)
+func (w *wavReader) frameCount() int64 {
+	if w.remaining < 0 {
+		return -1
+	}
+	return w.remaining / int64(len(w.buf))
+}
+
+// readFrame reads one frame (全チャンネル分) into frame. 終端では io.EOF を返す
+func (w *wavReader) readFrame(frame []float64) error {
+	if w.remaining >= 0 && w.remaining < int64(len(w.buf)) {
+		return io.EOF
+	}
+	if _, err := io.ReadFull(w.r, w.buf); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return io.EOF
+		}
+		return err
+	}
+	if w.remaining >= 0 {
+		w.remaining -= int64(len(w.buf))
+	}
+
+	for ch := 0; ch < w.channels; ch++ {
+		b := w.buf[ch*w.bytesPerSample : (ch+1)*w.bytesPerSample]
+		frame[ch] = w.decodeSample(b)
+	}
+	return nil
+}
+
+func (w *wavReader) decodeSample(b []byte) float64 {
+	if w.float {
+		if len(b) == 8 {
+			return math.Float64frombits(binary.LittleEndian.Uint64(b))
+		}
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+	}
+	switch len(b) {
+	case 1:
+		return (float64(b[0]) - 128) / 128 // 8bit は符号なし
+	case 2:
+		return float64(int16(binary.LittleEndian.Uint16(b))) / (1 << 15)
+	case 3:
+		v := int32(b[0]) | int32(b[1])<<8 | int32(int8(b[2]))<<16
+		return float64(v) / (1 << 23)
+	default:
+		return float64(int32(binary.LittleEndian.Uint32(b))) / (1 << 31)
+	}
+}
+
+func (w *wavReader) Close() error {
+	return w.f.Close()
+}
@@ -233,16 +233,10 @@ func (m *Manager) RemoveTrackFromPlaylist(playlistID, trackID string) error {
 		return fmt.Errorf("failed to remove track from playlist: %w", err)
 	}
 
-	// 位置を再調整
-	_, err = db.Exec(`
-		UPDATE playlist_tracks 
-		SET position = position - 1 
-		WHERE playlist_id = ? AND position > (
-			SELECT position FROM playlist_tracks 
-			WHERE playlist_id = ? AND track_id = ?
-		)`,
-		playlistID, playlistID, trackID,
-	)
+	// 位置を再調整（削除した曲の後ろを詰める）
+	if err := compactPlaylistPositions(db, playlistID); err != nil {
+		logger.Warn("Failed to compact playlist positions", zap.String("playlist_id", playlistID), zap.Error(err))
+	}
 
 	logger.Info("Track removed from playlist",
 		zap.String("playlist_id", playlistID),
@@ -260,7 +254,7 @@ func (m *Manager) GetPlaylistTracks(playlistID string) ([]*PlaylistTrack, error)
 		return nil, errors.New("database not initialized")
 	}
 
-	query := `SELECT t.id, t.filename, t.title, t.artist, t.album, t.duration, t.has_artwork, t.created_at, pt.position
+	query := `SELECT t.id, t.filename, t.title, t.artist, t.album, t.duration, t.has_artwork, t.gain_db, t.created_at, pt.position
 			  FROM tracks t
 			  JOIN playlist_tracks pt ON t.id = pt.track_id
 			  WHERE pt.playlist_id = ?
@@ -286,6 +280,7 @@ func (m *Manager) GetPlaylistTracks(playlistID string) ([]*PlaylistTrack, error)
 			&track.Album,
 			&track.Duration,
 			&track.HasArtwork,
+			&track.GainDB,
 			&createdAt,
 			&track.Position,
 		)
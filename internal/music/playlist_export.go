@@ -0,0 +1,174 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// PlaylistExportVersion is the version of the playlist export format
+const PlaylistExportVersion = 1
+
+var ErrPlaylistExists = errors.New("playlist already exists")
+
+// PlaylistExport is a playlist backup that can be imported on another install.
+// 曲のIDは環境ごとに異なるので、内容のハッシュ（なければファイル名）で曲を照合する
+type PlaylistExport struct {
+	Version     int                   `json:"version"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	ExportedAt  time.Time             `json:"exported_at"`
+	Tracks      []PlaylistExportTrack `json:"tracks"`
+}
+
+// PlaylistExportTrack is a track entry in a playlist export (並び順どおり)
+type PlaylistExportTrack struct {
+	Position    int    `json:"position"`
+	ContentHash string `json:"content_hash,omitempty"`
+	Filename    string `json:"filename"`
+	Title       string `json:"title"`
+	Artist      string `json:"artist"`
+	Album       string `json:"album,omitempty"`
+	Duration    int    `json:"duration"`
+}
+
+// PlaylistImportResult reports how an imported playlist's tracks were matched
+type PlaylistImportResult struct {
+	Playlist *Playlist             `json:"playlist"`
+	Linked   int                   `json:"linked"`
+	Missing  []PlaylistExportTrack `json:"missing"` // この環境に見つからなかった曲
+}
+
+// ExportPlaylist returns the playlist and its ordered tracks as an export document
+func (m *Manager) ExportPlaylist(playlistID string) (*PlaylistExport, error) {
+	playlist, err := m.GetPlaylist(playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	db := localdb.GetDB()
+	if db == nil {
+		return nil, errors.New("database not initialized")
+	}
+
+	rows, err := db.Query(`SELECT t.id, t.filename, t.title, t.artist, COALESCE(t.album, ''), t.duration,
+			  COALESCE(t.content_hash, ''), pt.position
+			  FROM tracks t
+			  JOIN playlist_tracks pt ON t.id = pt.track_id
+			  WHERE pt.playlist_id = ?
+			  ORDER BY pt.position`, playlist.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	export := &PlaylistExport{
+		Version:     PlaylistExportVersion,
+		Name:        playlist.Name,
+		Description: playlist.Description,
+		ExportedAt:  time.Now(),
+		Tracks:      []PlaylistExportTrack{},
+	}
+	for rows.Next() {
+		var id string
+		var t PlaylistExportTrack
+		if err := rows.Scan(&id, &t.Filename, &t.Title, &t.Artist, &t.Album, &t.Duration, &t.ContentHash, &t.Position); err != nil {
+			return nil, err
+		}
+		// バックフィル前の曲はその場でハッシュを計算する
+		if t.ContentHash == "" {
+			ext := strings.ToLower(filepath.Ext(t.Filename))
+			if hash, err := hashFile(filepath.Join(getTracksDir(), id+ext)); err == nil {
+				t.ContentHash = hash
+			}
+		}
+		export.Tracks = append(export.Tracks, t)
+	}
+	return export, rows.Err()
+}
+
+// ImportPlaylist creates a playlist from an export document, linking tracks that exist in this library.
+// name が空の場合はエクスポート時の名前を使う。同名のプレイリストがある場合は ErrPlaylistExists
+func (m *Manager) ImportPlaylist(export *PlaylistExport, name string) (*PlaylistImportResult, error) {
+	if export.Version > PlaylistExportVersion {
+		return nil, fmt.Errorf("unsupported playlist export version: %d", export.Version)
+	}
+	if name == "" {
+		name = export.Name
+	}
+	if name == "" {
+		return nil, errors.New("playlist name is required")
+	}
+	if _, err := m.GetPlaylistByName(name); err == nil {
+		return nil, ErrPlaylistExists
+	}
+
+	playlist, err := m.CreatePlaylist(name, export.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PlaylistImportResult{Playlist: playlist, Missing: []PlaylistExportTrack{}}
+	linked := make(map[string]bool)
+	for _, t := range export.Tracks {
+		trackID := matchImportedTrack(t)
+		if trackID == "" {
+			result.Missing = append(result.Missing, t)
+			continue
+		}
+		if linked[trackID] {
+			// 同じ内容の曲が複数入っていた場合は1つにまとめる
+			continue
+		}
+		if err := m.AddTrackToPlaylist(playlist.ID, trackID, result.Linked+1); err != nil {
+			logger.Warn("Failed to add imported track to playlist",
+				zap.String("playlist_id", playlist.ID),
+				zap.String("track_id", trackID),
+				zap.Error(err))
+			result.Missing = append(result.Missing, t)
+			continue
+		}
+		linked[trackID] = true
+		result.Linked++
+	}
+	playlist.TrackCount = result.Linked
+
+	logger.Info("Playlist imported",
+		zap.String("id", playlist.ID),
+		zap.String("name", name),
+		zap.Int("linked", result.Linked),
+		zap.Int("missing", len(result.Missing)))
+
+	return result, nil
+}
+
+// matchImportedTrack finds the local track for an exported entry: content hash first, then filename
+func matchImportedTrack(t PlaylistExportTrack) string {
+	if t.ContentHash != "" {
+		if track, err := findTrackByHash(t.ContentHash); err == nil {
+			return track.ID
+		}
+	}
+	if t.Filename == "" {
+		return ""
+	}
+
+	db := localdb.GetDB()
+	if db == nil {
+		return ""
+	}
+	var id string
+	if err := db.QueryRow(`SELECT id FROM tracks WHERE filename = ? ORDER BY created_at LIMIT 1`, t.Filename).Scan(&id); err != nil {
+		return ""
+	}
+	return id
+}
@@ -0,0 +1,150 @@
+package music
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// queryer is satisfied by both *sql.DB and *sql.Tx
+type queryer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// RepairResult reports what RepairPlaylists fixed
+type RepairResult struct {
+	RemovedEntries     int `json:"removed_entries"`     // 削除済みの曲/プレイリストを指していた行
+	CompactedPlaylists int `json:"compacted_playlists"` // 位置を詰め直したプレイリスト
+}
+
+// playlistsContainingTrack returns the IDs of playlists that include trackID
+func playlistsContainingTrack(q queryer, trackID string) ([]string, error) {
+	rows, err := q.Query("SELECT playlist_id FROM playlist_tracks WHERE track_id = ?", trackID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query playlists: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// compactPlaylistPositions renumbers a playlist's positions to 1..n keeping the current order
+func compactPlaylistPositions(q queryer, playlistID string) error {
+	_, err := compactPositions(q, playlistID)
+	return err
+}
+
+// compactPositions is compactPlaylistPositions that also reports whether any position changed
+func compactPositions(q queryer, playlistID string) (bool, error) {
+	rows, err := q.Query(
+		"SELECT track_id, position FROM playlist_tracks WHERE playlist_id = ? ORDER BY position, track_id",
+		playlistID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to query playlist positions: %w", err)
+	}
+
+	type entry struct {
+		trackID  string
+		position int
+	}
+	var entries []entry
+	for rows.Next() {
+		var e entry
+		if err := rows.Scan(&e.trackID, &e.position); err != nil {
+			rows.Close()
+			return false, err
+		}
+		entries = append(entries, e)
+	}
+	rows.Close()
+
+	changed := false
+	for i, e := range entries {
+		if e.position == i+1 {
+			continue
+		}
+		if _, err := q.Exec(
+			"UPDATE playlist_tracks SET position = ? WHERE playlist_id = ? AND track_id = ?",
+			i+1, playlistID, e.trackID,
+		); err != nil {
+			return changed, fmt.Errorf("failed to update position: %w", err)
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// RepairPlaylists removes entries pointing at deleted tracks/playlists and
+// re-compacts the positions of every playlist.
+func (m *Manager) RepairPlaylists() (*RepairResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	db := localdb.GetDB()
+	if db == nil {
+		return nil, errors.New("database not initialized")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM playlist_tracks
+		WHERE track_id NOT IN (SELECT id FROM tracks)
+		   OR playlist_id NOT IN (SELECT id FROM playlists)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove orphaned playlist entries: %w", err)
+	}
+	removed, _ := res.RowsAffected()
+
+	rows, err := tx.Query("SELECT id FROM playlists")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query playlists: %w", err)
+	}
+	var playlistIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		playlistIDs = append(playlistIDs, id)
+	}
+	rows.Close()
+
+	result := &RepairResult{RemovedEntries: int(removed)}
+	for _, id := range playlistIDs {
+		changed, err := compactPositions(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			result.CompactedPlaylists++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Playlists repaired",
+		zap.Int("removed_entries", result.RemovedEntries),
+		zap.Int("compacted_playlists", result.CompactedPlaylists))
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package music
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// TrackSearch is a track search query. 空のフィールドは条件に含めない
+type TrackSearch struct {
+	Query  string // タイトル・アーティスト・アルバムのいずれかに含まれる
+	Artist string
+	Album  string
+	Limit  int
+	Offset int
+}
+
+// SearchTracks returns tracks matching the query (新しい順) and the total number of matches
+func (m *Manager) SearchTracks(s TrackSearch) ([]*Track, int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	db := localdb.GetDB()
+	if db == nil {
+		return nil, 0, errors.New("database not initialized")
+	}
+
+	var conds []string
+	var args []interface{}
+	if s.Query != "" {
+		conds = append(conds, `(title LIKE ? ESCAPE '\' OR artist LIKE ? ESCAPE '\' OR album LIKE ? ESCAPE '\')`)
+		q := likePattern(s.Query)
+		args = append(args, q, q, q)
+	}
+	if s.Artist != "" {
+		conds = append(conds, `artist LIKE ? ESCAPE '\'`)
+		args = append(args, likePattern(s.Artist))
+	}
+	if s.Album != "" {
+		conds = append(conds, `album LIKE ? ESCAPE '\'`)
+		args = append(args, likePattern(s.Album))
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM tracks`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT id, filename, title, artist, album, duration, has_artwork, gain_db, created_at
+			  FROM tracks` + where + ` ORDER BY created_at DESC LIMIT ? OFFSET ?`
+	rows, err := db.Query(query, append(args, s.Limit, s.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	tracks := []*Track{}
+	for rows.Next() {
+		var track Track
+		var createdAt string
+		err := rows.Scan(
+			&track.ID,
+			&track.Filename,
+			&track.Title,
+			&track.Artist,
+			&track.Album,
+			&track.Duration,
+			&track.HasArtwork,
+			&track.GainDB,
+			&createdAt,
+		)
+		if err != nil {
+			logger.Warn("Failed to scan track", zap.Error(err))
+			continue
+		}
+		track.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		tracks = append(tracks, &track)
+	}
+
+	return tracks, total, rows.Err()
+}
+
+// likePattern builds a "contains" LIKE pattern, escaping the LIKE wildcards in s
+func likePattern(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(s) + "%"
+}
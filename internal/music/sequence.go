@@ -0,0 +1,82 @@
+package music
+
+import "math/rand"
+
+// リピートモード
+const (
+	RepeatNone = "none" // 最後の曲で停止
+	RepeatAll  = "all"  // 最後の曲の次は先頭に戻る
+	RepeatOne  = "one"  // 同じ曲を繰り返す
+)
+
+// SequenceMode controls how the next/previous track is chosen
+type SequenceMode struct {
+	Shuffle bool   `json:"shuffle"`
+	Repeat  string `json:"repeat"`
+}
+
+// PlaylistTrackList returns the tracks of a playlist in playback order.
+// playlistID が空の場合はライブラリ全体（GetAllTracks の順）を返す
+func (m *Manager) PlaylistTrackList(playlistID string) ([]*Track, error) {
+	if playlistID == "" {
+		return m.GetAllTracks()
+	}
+
+	playlistTracks, err := m.GetPlaylistTracks(playlistID)
+	if err != nil {
+		return nil, err
+	}
+	tracks := make([]*Track, 0, len(playlistTracks))
+	for _, pt := range playlistTracks {
+		tracks = append(tracks, pt.Track)
+	}
+	return tracks, nil
+}
+
+// NeighborTrack returns the track step positions away from trackID (step: 1=次, -1=前).
+// trackID がリストにない場合は先頭の曲を返す。RepeatNone で端に達した場合は nil
+func NeighborTrack(tracks []*Track, trackID string, step int, mode SequenceMode) *Track {
+	n := len(tracks)
+	if n == 0 {
+		return nil
+	}
+
+	current := -1
+	for i, t := range tracks {
+		if t.ID == trackID {
+			current = i
+			break
+		}
+	}
+	if current < 0 {
+		return tracks[0]
+	}
+
+	if mode.Repeat == RepeatOne {
+		return tracks[current]
+	}
+
+	if mode.Shuffle {
+		if n == 1 {
+			if mode.Repeat == RepeatNone {
+				return nil
+			}
+			return tracks[0]
+		}
+		// 現在の曲以外からランダムに選ぶ
+		i := rand.Intn(n - 1)
+		if i >= current {
+			i++
+		}
+		return tracks[i]
+	}
+
+	next := current + step
+	if next < 0 || next >= n {
+		if mode.Repeat == RepeatNone {
+			return nil
+		}
+		next = (next%n + n) % n
+	}
+	return tracks[next]
+}
@@ -0,0 +1,77 @@
+package music
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// webVersionExt is the baseline format every browser can play
+const webVersionExt = ".web.mp3"
+
+// webVersionPath returns where the transcoded web version of a track is stored
+func webVersionPath(trackID string) string {
+	return filepath.Join(getTracksDir(), trackID+webVersionExt)
+}
+
+// ffmpegPath returns the ffmpeg binary used for transcoding, or "" when unavailable
+func ffmpegPath() string {
+	if p := strings.TrimSpace(env.Value.FfmpegPath); p != "" {
+		return p
+	}
+	p, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return ""
+	}
+	return p
+}
+
+// scheduleTranscode converts a freshly uploaded track to MP3 in the background (MUSIC_TRANSCODE).
+// 元のファイルはそのまま残し、変換が終わるまでは元のファイルを配信する
+func scheduleTranscode(trackID, srcPath string) {
+	if !env.Value.MusicTranscode {
+		return
+	}
+	if strings.EqualFold(filepath.Ext(srcPath), ".mp3") {
+		return // すでにMP3
+	}
+
+	ffmpeg := ffmpegPath()
+	if ffmpeg == "" {
+		logger.Warn("MUSIC_TRANSCODE is enabled but ffmpeg was not found; serving original file", zap.String("id", trackID))
+		return
+	}
+
+	go func() {
+		if err := transcodeToMP3(ffmpeg, srcPath, webVersionPath(trackID)); err != nil {
+			logger.Warn("Failed to transcode track", zap.String("id", trackID), zap.Error(err))
+			return
+		}
+		logger.Info("Track transcoded for web playback", zap.String("id", trackID))
+	}()
+}
+
+// transcodeToMP3 runs ffmpeg and atomically moves the result to dst
+func transcodeToMP3(ffmpeg, src, dst string) error {
+	tmp := dst + ".tmp"
+	cmd := exec.Command(ffmpeg, "-y", "-loglevel", "error", "-i", src, "-vn", "-codec:a", "libmp3lame", "-q:a", "2", "-f", "mp3", tmp)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("ffmpeg: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return os.Rename(tmp, dst)
+}
+
+// GetPlaybackPath returns the file to stream for a track: the transcoded web version if present, otherwise the original
+func (m *Manager) GetPlaybackPath(trackID string) (string, error) {
+	if _, err := os.Stat(webVersionPath(trackID)); err == nil {
+		return webVersionPath(trackID), nil
+	}
+	return m.GetTrackPath(trackID)
+}
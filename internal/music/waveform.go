@@ -0,0 +1,246 @@
+package music
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// 波形の解像度（バケット数）の範囲
+	DefaultWaveformBuckets = 200
+	MaxWaveformBuckets     = 2000
+	minWaveformBuckets     = 10
+
+	// waveformSampleRate は波形用にデコードするときのサンプルレート（ピークの表示には十分）
+	waveformSampleRate = 8000
+)
+
+var ErrFFmpegUnavailable = errors.New("ffmpeg not available")
+
+// Waveform is per-track peak data for drawing a waveform
+type Waveform struct {
+	TrackID  string    `json:"track_id"`
+	Buckets  int       `json:"buckets"`
+	Duration float64   `json:"duration"` // 秒
+	Peaks    []float64 `json:"peaks"`    // 各区間の最大振幅（0〜1、フルスケール基準）
+}
+
+// waveformCachePath returns where the waveform JSON is cached (アートワークと同じディレクトリ)
+func waveformCachePath(trackID string) string {
+	return filepath.Join(getArtworkDir(), trackID+".waveform.json")
+}
+
+// removeWaveformCache deletes all cached waveforms of a track (以前のバケット数ごとのキャッシュも含む)
+func removeWaveformCache(trackID string) {
+	matches, _ := filepath.Glob(filepath.Join(getArtworkDir(), trackID+".waveform*.json"))
+	for _, p := range matches {
+		os.Remove(p)
+	}
+}
+
+// WaveformJSON returns the waveform of a track as JSON.
+// 初回に MaxWaveformBuckets の解像度でピークを計算してキャッシュし、要求されたバケット数にはそこから間引く
+func (m *Manager) WaveformJSON(trackID string, buckets int) ([]byte, error) {
+	if buckets < minWaveformBuckets {
+		buckets = minWaveformBuckets
+	}
+	if buckets > MaxWaveformBuckets {
+		buckets = MaxWaveformBuckets
+	}
+
+	full, err := m.fullWaveform(trackID)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(Waveform{
+		TrackID:  trackID,
+		Buckets:  buckets,
+		Duration: full.Duration,
+		Peaks:    downsamplePeaks(full.Peaks, buckets),
+	})
+}
+
+// fullWaveform returns the cached full-resolution waveform, generating it on first use
+func (m *Manager) fullWaveform(trackID string) (*Waveform, error) {
+	cachePath := waveformCachePath(trackID)
+	if data, err := os.ReadFile(cachePath); err == nil {
+		var wf Waveform
+		if err := json.Unmarshal(data, &wf); err == nil && len(wf.Peaks) == MaxWaveformBuckets {
+			return &wf, nil
+		}
+	}
+
+	trackPath, err := m.GetTrackPath(trackID)
+	if err != nil {
+		return nil, err
+	}
+	amps, duration, err := decodeAmplitudes(trackPath)
+	if err != nil {
+		return nil, err
+	}
+
+	wf := &Waveform{
+		TrackID:  trackID,
+		Buckets:  MaxWaveformBuckets,
+		Duration: math.Round(duration*100) / 100,
+		Peaks:    bucketPeaks(amps, MaxWaveformBuckets),
+	}
+
+	// 同時にリクエストされても壊れたファイルを読まないよう、一時ファイルから置き換える
+	if data, err := json.Marshal(wf); err == nil && ensureDirs() == nil {
+		tmp := cachePath + ".tmp"
+		if err := os.WriteFile(tmp, data, 0644); err == nil {
+			os.Rename(tmp, cachePath)
+		}
+	}
+	return wf, nil
+}
+
+// decodeAmplitudes returns the mono absolute amplitudes of a track at about waveformSampleRate and its duration in seconds.
+// WAV はその場でデコードし、それ以外の形式（または未対応の WAV）は ffmpeg を使う
+func decodeAmplitudes(src string) ([]uint16, float64, error) {
+	if canDecodeInProcess(src) {
+		amps, duration, err := wavAmplitudes(src)
+		if !errors.Is(err, errUnsupportedWAV) {
+			return amps, duration, err
+		}
+	}
+
+	ffmpeg := ffmpegPath()
+	if ffmpeg == "" {
+		return nil, 0, ErrFFmpegUnavailable
+	}
+	amps, err := ffmpegAmplitudes(ffmpeg, src)
+	if err != nil {
+		return nil, 0, err
+	}
+	return amps, float64(len(amps)) / waveformSampleRate, nil
+}
+
+// wavAmplitudes decodes a WAV file, keeping the peak of every group of frames so the result is about waveformSampleRate
+func wavAmplitudes(src string) ([]uint16, float64, error) {
+	w, err := openWAV(src)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer w.Close()
+
+	group := w.sampleRate / waveformSampleRate
+	if group < 1 {
+		group = 1
+	}
+
+	var amps []uint16
+	if n := w.frameCount(); n > 0 {
+		amps = make([]uint16, 0, n/int64(group)+1)
+	}
+	frame := make([]float64, w.channels)
+	var peak float64
+	frames, n := 0, 0
+	for {
+		err := w.readFrame(frame)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		// チャンネルをまとめて一番大きい振幅を使う
+		for _, v := range frame {
+			peak = math.Max(peak, math.Abs(v))
+		}
+		frames++
+		n++
+		if n == group {
+			amps = append(amps, uint16(math.Min(peak, 1)*math.MaxInt16))
+			peak, n = 0, 0
+		}
+	}
+	if n > 0 {
+		amps = append(amps, uint16(math.Min(peak, 1)*math.MaxInt16))
+	}
+	if len(amps) == 0 {
+		return nil, 0, errors.New("no audio samples decoded")
+	}
+	return amps, float64(frames) / float64(w.sampleRate), nil
+}
+
+// ffmpegAmplitudes decodes the track to mono PCM with ffmpeg and returns the absolute value of each sample
+func ffmpegAmplitudes(ffmpeg, src string) ([]uint16, error) {
+	cmd := exec.Command(ffmpeg, "-hide_banner", "-loglevel", "error", "-i", src, "-vn",
+		"-ac", "1", "-ar", fmt.Sprint(waveformSampleRate), "-f", "s16le", "-")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("ffmpeg: %w", err)
+	}
+
+	// 曲の長さは事前にわからないので、まず全サンプルの絶対値を読み込む（8kHz・16bit なので1時間でも約60MB）
+	var amps []uint16
+	r := bufio.NewReader(stdout)
+	var buf [2]byte
+	for {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			break
+		}
+		v := int16(binary.LittleEndian.Uint16(buf[:]))
+		if v < 0 {
+			v = -(v + 1) // -32768 でオーバーフローしないように
+		}
+		amps = append(amps, uint16(v))
+	}
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("ffmpeg: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if len(amps) == 0 {
+		return nil, errors.New("no audio samples decoded")
+	}
+	return amps, nil
+}
+
+// bucketPeaks returns the peak amplitude (0〜1) of each of buckets equal ranges of amps
+func bucketPeaks(amps []uint16, buckets int) []float64 {
+	peaks := make([]float64, buckets)
+	for i := range peaks {
+		start := i * len(amps) / buckets
+		end := (i + 1) * len(amps) / buckets
+		var peak uint16
+		for _, a := range amps[start:end] {
+			if a > peak {
+				peak = a
+			}
+		}
+		peaks[i] = math.Round(float64(peak)/math.MaxInt16*1000) / 1000
+	}
+	return peaks
+}
+
+// downsamplePeaks reduces peaks to buckets values, keeping the maximum of each range
+func downsamplePeaks(peaks []float64, buckets int) []float64 {
+	if buckets >= len(peaks) {
+		return peaks
+	}
+	out := make([]float64, buckets)
+	for i := range out {
+		start := i * len(peaks) / buckets
+		end := (i + 1) * len(peaks) / buckets
+		for _, p := range peaks[start:end] {
+			out[i] = math.Max(out[i], p)
+		}
+	}
+	return out
+}
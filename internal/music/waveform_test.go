@@ -0,0 +1,49 @@
+package music
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDownsamplePeaks(t *testing.T) {
+	peaks := []float64{0.1, 0.5, 0.2, 0.9, 0.3, 0.4}
+	tests := []struct {
+		buckets int
+		want    []float64
+	}{
+		{6, peaks},
+		{10, peaks},
+		{3, []float64{0.5, 0.9, 0.4}},
+		{2, []float64{0.5, 0.9}},
+		{1, []float64{0.9}},
+	}
+	for _, tt := range tests {
+		if got := downsamplePeaks(peaks, tt.buckets); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("downsamplePeaks(%d) = %v, want %v", tt.buckets, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeAmplitudesWAVWithoutFFmpeg(t *testing.T) {
+	// 1秒目は -20 dBFS、2秒目は -6 dBFS のステレオ WAV
+	const sampleRate = 44100
+	samples := append(sineSamples(sampleRate, 2, 440, 0.1, 1), sineSamples(sampleRate, 2, 440, 0.5, 1)...)
+	path := writeTestWAV(t, sampleRate, 2, samples)
+
+	// WAV は ffmpeg なしでデコードできる（ffmpegPath は呼ばれない）
+	amps, duration, err := decodeAmplitudes(path)
+	if err != nil {
+		t.Fatalf("decodeAmplitudes: %v", err)
+	}
+	if math.Abs(duration-2) > 0.01 {
+		t.Errorf("duration = %.3f, want 2", duration)
+	}
+
+	peaks := bucketPeaks(amps, 2)
+	for i, want := range []float64{0.1, 0.5} {
+		if math.Abs(peaks[i]-want) > 0.01 {
+			t.Errorf("peak %d = %.3f, want %.1f", i, peaks[i], want)
+		}
+	}
+}
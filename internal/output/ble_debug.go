@@ -0,0 +1,179 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// BLEDebugMaxDuration は診断ログを有効にできる最大時間
+	BLEDebugMaxDuration = 30 * time.Minute
+	// bleDebugMaxBytes を超えたキャプチャは以降を捨てる（サポート用には十分な量）
+	bleDebugMaxBytes = 4 << 20
+)
+
+// bleDebugCapture temporarily turns on the catprinter client's verbose Debug.Log and
+// records the scan/connect output so it can be downloaded for support tickets.
+// go-catprinter のデバッグ出力は標準 log パッケージに出るため、有効な間だけ log の出力先に挟み込む
+type bleDebugCapture struct {
+	mu        sync.Mutex
+	active    bool
+	startedAt time.Time
+	until     time.Time
+	buf       bytes.Buffer
+	truncated bool
+	restore   io.Writer
+	timer     *time.Timer
+}
+
+var bleDebug = &bleDebugCapture{}
+
+// BLEDebugStatus describes the current diagnostic capture
+type BLEDebugStatus struct {
+	StartedAt *time.Time `json:"started_at,omitempty"` // 一度もキャプチャしていなければ nil
+	Until     *time.Time `json:"until,omitempty"`      // 有効な間のみ
+	Size      int        `json:"size"`
+	Truncated bool       `json:"truncated"`
+	Active    bool       `json:"active"`
+}
+
+// EnableBLEDebug enables verbose BLE logging for d and starts a fresh capture.
+// すでに有効な場合はキャプチャを残したまま期限だけ延長する
+func EnableBLEDebug(d time.Duration) BLEDebugStatus {
+	if d <= 0 || d > BLEDebugMaxDuration {
+		d = BLEDebugMaxDuration
+	}
+
+	b := bleDebug
+	b.mu.Lock()
+	starting := !b.active
+	if starting {
+		b.active = true
+		b.startedAt = time.Now()
+		b.buf.Reset()
+		b.truncated = false
+		b.restore = log.Writer()
+	}
+	restore := b.restore
+	b.until = time.Now().Add(d)
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.timer = time.AfterFunc(d, DisableBLEDebug)
+	b.mu.Unlock()
+
+	// log の内部ロック中に Write が呼ばれるため、SetOutput は b.mu の外で行う
+	if starting {
+		log.SetOutput(io.MultiWriter(restore, b))
+	}
+
+	// 既存のクライアントにも反映する（新規クライアントは SetupPrinter で設定）
+	if latestPrinter != nil {
+		latestPrinter.Debug.Log = true
+	}
+
+	recordBLEDebug("BLE debug capture enabled for %s", d)
+	logger.Info("BLE debug capture enabled", zap.Duration("duration", d))
+	return GetBLEDebugStatus()
+}
+
+// DisableBLEDebug stops the capture and restores normal logging. The captured log is kept for download.
+func DisableBLEDebug() {
+	recordBLEDebug("BLE debug capture disabled")
+
+	b := bleDebug
+	b.mu.Lock()
+	if !b.active {
+		b.mu.Unlock()
+		return
+	}
+	b.active = false
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	restore := b.restore
+	b.restore = nil
+	b.mu.Unlock()
+
+	log.SetOutput(restore)
+
+	if latestPrinter != nil {
+		latestPrinter.Debug.Log = false
+	}
+	logger.Info("BLE debug capture disabled")
+}
+
+// GetBLEDebugStatus returns the state of the diagnostic capture
+func GetBLEDebugStatus() BLEDebugStatus {
+	b := bleDebug
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := BLEDebugStatus{
+		Active:    b.active,
+		Size:      b.buf.Len(),
+		Truncated: b.truncated,
+	}
+	if !b.startedAt.IsZero() {
+		startedAt := b.startedAt
+		s.StartedAt = &startedAt
+	}
+	if b.active {
+		until := b.until
+		s.Until = &until
+	}
+	return s
+}
+
+// BLEDebugLog returns a copy of the captured log text
+func BLEDebugLog() []byte {
+	b := bleDebug
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([]byte, b.buf.Len())
+	copy(out, b.buf.Bytes())
+	return out
+}
+
+// bleDebugEnabled reports whether new printer clients should log verbosely
+func bleDebugEnabled() bool {
+	bleDebug.mu.Lock()
+	defer bleDebug.mu.Unlock()
+	return bleDebug.active
+}
+
+// recordBLEDebug adds an app-side event (scan/connect) to the capture while it is active
+func recordBLEDebug(format string, args ...interface{}) {
+	if !bleDebugEnabled() {
+		return
+	}
+	bleDebug.Write([]byte(fmt.Sprintf(format, args...) + "\n"))
+}
+
+// Write implements io.Writer for the standard logger; each write is one log line
+func (b *bleDebugCapture) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.active || b.truncated {
+		return len(p), nil
+	}
+
+	line := time.Now().Format("2006-01-02 15:04:05.000") + " " + string(p)
+	if len(line) > 0 && line[len(line)-1] != '\n' {
+		line += "\n"
+	}
+	if b.buf.Len()+len(line) > bleDebugMaxBytes {
+		b.buf.WriteString("... capture truncated (size limit reached)\n")
+		b.truncated = true
+		return len(p), nil
+	}
+	b.buf.WriteString(line)
+	return len(p), nil
+}
@@ -0,0 +1,82 @@
+package output
+
+import (
+	"sync"
+
+	"github.com/nantokaworks/twitch-overlay/internal/broadcast"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// PrinterCapabilities describes what the connected printer can do
+type PrinterCapabilities struct {
+	Detected bool   `json:"detected"`
+	Address  string `json:"address"`
+	Beep     bool   `json:"beep"`
+	Protocol string `json:"protocol"`
+}
+
+var (
+	capabilities          PrinterCapabilities
+	capabilitiesMutex     sync.RWMutex
+	colorFallbackWarnOnce sync.Once
+)
+
+// detectCapabilities records the printer after connecting.
+// catprinterプロトコル（GB/GT/MX系）はモノクロ印刷しかできず、接続時に機種も分からないため
+// カラー対応の有無は持たない（COLOR_PRINT は常にモノクロにフォールバックする）。
+// catprinter にはビープ命令もないので Beep は false（PRINT_BEEP は何もしない）
+func detectCapabilities(address string) {
+	capabilitiesMutex.Lock()
+	capabilities = PrinterCapabilities{
+		Detected: true,
+		Address:  address,
+		Beep:     false,
+		Protocol: "catprinter",
+	}
+	capabilitiesMutex.Unlock()
+
+	logger.Info("Printer capabilities detected",
+		zap.String("address", address),
+		zap.String("protocol", "catprinter"))
+}
+
+// GetPrinterCapabilities returns the capabilities detected on the last connection
+func GetPrinterCapabilities() PrinterCapabilities {
+	capabilitiesMutex.RLock()
+	defer capabilitiesMutex.RUnlock()
+	return capabilities
+}
+
+// warnColorFallback logs and broadcasts a one-time warning when color printing
+// was requested but the printer only supports monochrome
+func warnColorFallback() {
+	colorFallbackWarnOnce.Do(func() {
+		caps := GetPrinterCapabilities()
+		logger.Warn("Color printing requested but printer does not support color, falling back to monochrome",
+			zap.String("address", caps.Address))
+
+		broadcast.Send(map[string]interface{}{
+			"type": "printer_warning",
+			"data": map[string]interface{}{
+				"code":    "color_not_supported",
+				"message": "プリンターがカラー印刷に対応していないため、モノクロで印刷します",
+			},
+		})
+	})
+}
+
+var beepUnsupportedOnce sync.Once
+
+// printBeep sounds the printer's beeper after a successful print (PRINT_BEEP).
+// 現在対応している catprinter プロトコルにはビープ命令がないため、初回のみログに出して何もしない。
+// ビープ対応のプロトコルを追加した場合はここで命令を送る（printerLock を保持した状態で呼ぶこと）
+func printBeep() {
+	caps := GetPrinterCapabilities()
+	if !caps.Beep {
+		beepUnsupportedOnce.Do(func() {
+			logger.Info("PRINT_BEEP is enabled but the printer has no beep command, skipping",
+				zap.String("protocol", caps.Protocol))
+		})
+	}
+}
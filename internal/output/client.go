@@ -13,6 +13,7 @@ import (
 var latestPrinter *catprinter.Client
 var opts *catprinter.PrinterOptions
 var isConnected bool
+var connectedAddress string // 現在接続しているプリンターのアドレス（ルールごとの printer_address の切り替えに使う）
 var isReconnecting bool
 var hasInitialPrintBeenDone bool
 
@@ -29,6 +30,7 @@ func SetupPrinter() (*catprinter.Client, error) {
 			logger.Info("Disconnecting existing connection")
 			latestPrinter.Disconnect()
 			isConnected = false
+			connectedAddress = ""
 			// 再接続中はステータスを変更しない（接続中を維持）
 			// status.SetPrinterConnected(false) を呼ばない
 		}
@@ -52,6 +54,10 @@ func SetupPrinter() (*catprinter.Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	if bleDebugEnabled() {
+		instance.Debug.Log = true
+		recordBLEDebug("Created new printer client (reconnecting=%v)", isReconnecting)
+	}
 	latestPrinter = instance
 	return instance, nil
 }
@@ -61,11 +67,20 @@ func ConnectPrinter(c *catprinter.Client, address string) error {
 		return nil
 	}
 	
-	// Skip if already connected (and not reconnecting)
-	if isConnected && !isReconnecting {
+	// Skip if already connected to the same printer (and not reconnecting)
+	if isConnected && !isReconnecting && connectedAddress == address {
 		return nil
 	}
 
+	// 別のプリンターに接続中なら切断してから接続し直す
+	if isConnected && connectedAddress != address {
+		logger.Info("Switching printer", zap.String("from", connectedAddress), zap.String("to", address))
+		recordBLEDebug("Disconnecting from %s to switch to %s", connectedAddress, address)
+		c.Disconnect()
+		isConnected = false
+		connectedAddress = ""
+	}
+
 	// DRY-RUNモードでも実際のプリンターに接続
 	if env.Value.DryRunMode {
 		logger.Info("Connecting to printer in DRY-RUN mode", zap.String("address", address))
@@ -73,8 +88,10 @@ func ConnectPrinter(c *catprinter.Client, address string) error {
 		logger.Info("Connecting to printer", zap.String("address", address))
 	}
 
+	recordBLEDebug("Connecting to %s", address)
 	err := c.Connect(address)
 	if err != nil {
+		recordBLEDebug("Connect to %s failed: %v", address, err)
 		// 接続失敗時、再接続中でなければステータスを更新
 		if !isReconnecting {
 			status.SetPrinterConnected(false)
@@ -83,7 +100,9 @@ func ConnectPrinter(c *catprinter.Client, address string) error {
 	}
 	
 	logger.Info("Successfully connected to printer", zap.String("address", address))
+	recordBLEDebug("Connected to %s", address)
 	isConnected = true
+	connectedAddress = address
 	
 	// 再接続が完了したらフラグをクリア
 	isReconnecting = false
@@ -91,6 +110,9 @@ func ConnectPrinter(c *catprinter.Client, address string) error {
 	// 常にステータスを更新（再接続完了時も含む）
 	status.SetPrinterConnected(true)
 
+	// 接続したプリンターの機能を検出
+	detectCapabilities(address)
+
 	return nil
 }
 
@@ -111,6 +133,7 @@ func Stop() {
 		if isConnected {
 			latestPrinter.Disconnect()
 			isConnected = false
+			connectedAddress = ""
 			status.SetPrinterConnected(false)
 		}
 		// Stop()を呼ぶとBLEデバイスも解放される
@@ -0,0 +1,139 @@
+package output
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/fontmanager"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/numfmt"
+	"github.com/nantokaworks/twitch-overlay/internal/twitchapi"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// GenerateTimeImageCondensed creates a compact clock image for CLOCK_CONDENSED.
+// アバターを省略し、LEADERBOARD_SIZE 位までを1行ずつ表示して紙の消費を抑える（モノクロ/カラー共通の画像）
+func GenerateTimeImageCondensed(timeStr string, monthLeaders []*twitchapi.BitsLeaderboardEntry, missingScope bool) (image.Image, error) {
+	// フォントマネージャーからパース済みのフォントを取得（カスタムフォント必須）
+	f, err := fontmanager.ParseCurrentFont()
+	if err != nil {
+		return nil, fontError(err)
+	}
+
+	headerFace, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    float64(scaledFontSize(36)),
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create header font face: %w", err)
+	}
+	defer headerFace.Close()
+
+	rowFace, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    float64(scaledFontSize(24)),
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create row font face: %w", err)
+	}
+	defer rowFace.Close()
+
+	const (
+		padding    = 10
+		margin     = 10 // 左右の余白
+		rowSpacing = 4
+	)
+	places := twitchapi.LeaderboardSize()
+	headerH := scaledFontSize(36)
+	rowH := scaledFontSize(24)
+
+	rows := places
+	if len(monthLeaders) == 0 {
+		rows = 1 // 「まだ誰もいません」（または取得不可の理由）の1行のみ
+	}
+	height := padding + headerH + 8 + 2 + 8 + rows*(rowH+rowSpacing) + padding + 2
+
+	img := image.NewRGBA(image.Rect(0, 0, PaperWidth(), height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+
+	// 日付と時刻を1行で表示
+	d := &font.Drawer{Dst: img, Src: image.Black, Face: headerFace}
+	header := time.Now().Format("2006/01/02") + " " + timeStr
+	drawCenteredText(d, header, padding)
+
+	yPos := padding + headerH + 8
+	drawHorizontalLine(img, yPos, margin, margin, 2, color.Black)
+	yPos += 2 + 8
+
+	d.Face = rowFace
+	widths := newRuneWidthCache(rowFace)
+	ascent := rowFace.Metrics().Ascent
+
+	if len(monthLeaders) == 0 {
+		d.Src = image.NewUniform(color.Gray{150})
+		if missingScope {
+			drawCenteredText(d, "ランキング取得不可（要再認証）", yPos)
+		} else {
+			drawCenteredText(d, leaderboardPeriodLabel(twitchapi.LeaderboardPeriod())+"のCheer: まだ誰もいません", yPos)
+		}
+	} else {
+		for i := 0; i < places; i++ {
+			placeStr := fmt.Sprintf("%d位 ", i+1)
+			bitsStr := "--- Bits"
+			name := "---"
+			d.Src = image.NewUniform(color.Gray{200})
+			if i < len(monthLeaders) {
+				bitsStr = numfmt.Int(monthLeaders[i].Score, env.Value.Locale) + " Bits"
+				name = monthLeaders[i].UserName
+				d.Src = image.Black
+			}
+
+			// 右端にBits、左に順位と名前（入りきらない名前は省略）
+			bitsW := widths.measure(bitsStr)
+			nameMax := PaperWidth() - margin*2 - bitsW - widths.measure(placeStr) - 8
+			line := placeStr + truncateToWidth(name, widths, nameMax)
+
+			d.Dot = fixed.Point26_6{X: fixed.I(margin), Y: fixed.I(yPos) + ascent}
+			d.DrawString(line)
+			d.Dot = fixed.Point26_6{X: fixed.I(PaperWidth() - margin - bitsW), Y: fixed.I(yPos) + ascent}
+			d.DrawString(bitsStr)
+
+			yPos += rowH + rowSpacing
+		}
+	}
+
+	// Draw bottom separator (dashed)
+	lineY := height - 4
+	for x := margin; x < PaperWidth()-margin; x += 4 {
+		for y := 0; y < 2; y++ {
+			img.Set(x, lineY+y, color.Black)
+		}
+	}
+
+	return img, nil
+}
+
+// truncateToWidth shortens s with "…" so that it fits in maxWidth pixels
+func truncateToWidth(s string, widths *runeWidthCache, maxWidth int) string {
+	if widths.measure(s) <= maxWidth {
+		return s
+	}
+	limit := fixed.I(maxWidth) - widths.advance('…')
+	var total fixed.Int26_6
+	i := 0
+	for _, g := range graphemes(s) {
+		total += widths.advanceString(g)
+		if total > limit {
+			return s[:i] + "…"
+		}
+		i += len(g)
+	}
+	return s
+}
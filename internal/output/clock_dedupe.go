@@ -0,0 +1,82 @@
+package output
+
+import (
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/clockschedule"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// clockCatchUp は処理が詰まって予定時刻を過ぎてしまった場合に、何分遅れまで時計を印刷するか
+	clockCatchUp = 1 * time.Minute
+	// clockSkewTolerance を超えて壁時計と経過時間がずれた場合はシステム時刻が飛んだとみなす
+	clockSkewTolerance = 30 * time.Second
+)
+
+// clockDeduper decides when the clock is due according to CLOCK_SCHEDULE.
+// 書式化した時刻文字列ではなく、最後に印刷した予定時刻を Unix 分（unix/60）で覚えておくので、
+// 予定時刻をまたいで処理が遅れても印刷漏れ・二重印刷が起きない
+type clockDeduper struct {
+	lastPrintedMinute int64     // 最後に印刷した予定時刻（Unix 分）
+	lastTick          time.Time // 前回の判定時刻（モノトニック時刻付き）
+
+	// monotonicSince returns the monotonic time elapsed between two ticks (nil なら now.Sub(last)。テストで差し替える)
+	monotonicSince func(now, last time.Time) time.Duration
+}
+
+// due reports whether the clock should be printed at now, and for which scheduled minute.
+// システム時刻が飛んだ直後の判定では印刷しない（時刻合わせで過去/未来の予定時刻を印刷しないため）
+func (c *clockDeduper) due(now time.Time, schedule *clockschedule.Schedule) (time.Time, bool) {
+	jumped := c.clockJumped(now)
+	c.lastTick = now
+
+	slot, ok := lastScheduledMinute(now, schedule)
+	if !ok || slot.Unix()/60 <= c.lastPrintedMinute {
+		return time.Time{}, false
+	}
+
+	// この予定時刻は印刷済みとして扱う（時刻が飛んだ場合はスキップしたまま次の予定時刻を待つ）
+	c.lastPrintedMinute = slot.Unix() / 60
+	if jumped {
+		logger.Warn("Clock: skipping print because the system clock jumped", zap.Time("slot", slot))
+		return time.Time{}, false
+	}
+	return slot, true
+}
+
+// lastScheduledMinute returns the latest scheduled minute within clockCatchUp before now
+func lastScheduledMinute(now time.Time, schedule *clockschedule.Schedule) (time.Time, bool) {
+	// Truncate は UTC 基準なので、30分ずれのタイムゾーンでも正しい時刻になるよう組み立てる
+	minute := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), 0, 0, now.Location())
+	for ; now.Sub(minute) <= clockCatchUp; minute = minute.Add(-time.Minute) {
+		if schedule.Matches(minute) {
+			return minute, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// clockJumped compares the wall-clock and monotonic elapsed time since the previous tick
+func (c *clockDeduper) clockJumped(now time.Time) bool {
+	if c.lastTick.IsZero() {
+		return false
+	}
+	elapsed := now.Sub(c.lastTick) // モノトニック時刻での経過
+	if c.monotonicSince != nil {
+		elapsed = c.monotonicSince(now, c.lastTick)
+	}
+	wallElapsed := now.Round(0).Sub(c.lastTick.Round(0)) // 壁時計での経過
+	skew := wallElapsed - elapsed
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew <= clockSkewTolerance {
+		return false
+	}
+	logger.Warn("Clock: system clock jumped",
+		zap.Duration("elapsed", elapsed),
+		zap.Duration("wall_elapsed", wallElapsed))
+	return true
+}
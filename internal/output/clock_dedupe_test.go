@@ -0,0 +1,113 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/clockschedule"
+)
+
+// clockTick is one call to clockDeduper.due
+type clockTick struct {
+	wall      string        // 壁時計（15:04:05）
+	monotonic time.Duration // 前回の判定からのモノトニック経過時間
+	wantPrint bool
+	wantSlot  string // 印刷する予定時刻（15:04）
+}
+
+func runClockTicks(t *testing.T, ticks []clockTick) {
+	t.Helper()
+	schedule, err := clockschedule.Parse("60")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	var monotonic time.Duration
+	c := clockDeduper{
+		monotonicSince: func(now, last time.Time) time.Duration { return monotonic },
+	}
+	for i, tick := range ticks {
+		wall, err := time.Parse("15:04:05", tick.wall)
+		if err != nil {
+			t.Fatalf("tick %d: invalid wall time %q", i, tick.wall)
+		}
+		now := time.Date(2025, 1, 6, wall.Hour(), wall.Minute(), wall.Second(), 0, time.UTC)
+		monotonic = tick.monotonic
+
+		slot, ok := c.due(now, schedule)
+		if ok != tick.wantPrint {
+			t.Fatalf("tick %d (%s): due = %v, want %v", i, tick.wall, ok, tick.wantPrint)
+		}
+		if ok && slot.Format("15:04") != tick.wantSlot {
+			t.Fatalf("tick %d (%s): slot = %s, want %s", i, tick.wall, slot.Format("15:04"), tick.wantSlot)
+		}
+	}
+}
+
+func TestClockDeduperDue(t *testing.T) {
+	tests := []struct {
+		name  string
+		ticks []clockTick
+	}{
+		{
+			name: "prints each scheduled minute once",
+			ticks: []clockTick{
+				{wall: "09:59:59", monotonic: 0},
+				{wall: "10:00:00", monotonic: time.Second, wantPrint: true, wantSlot: "10:00"},
+				{wall: "10:00:01", monotonic: time.Second},
+				{wall: "10:00:59", monotonic: 58 * time.Second},
+				{wall: "10:01:00", monotonic: time.Second},
+				{wall: "11:00:00", monotonic: 59 * time.Minute, wantPrint: true, wantSlot: "11:00"},
+			},
+		},
+		{
+			name: "catches up when the tick is late",
+			ticks: []clockTick{
+				{wall: "09:59:58", monotonic: 0},
+				{wall: "10:00:50", monotonic: 52 * time.Second, wantPrint: true, wantSlot: "10:00"},
+				{wall: "10:01:00", monotonic: 10 * time.Second},
+			},
+		},
+		{
+			name: "gives up outside the catch-up window",
+			ticks: []clockTick{
+				{wall: "09:59:58", monotonic: 0},
+				{wall: "10:01:30", monotonic: 92 * time.Second},
+				{wall: "10:01:31", monotonic: time.Second},
+			},
+		},
+		{
+			name: "skips the slot reached by a forward jump",
+			ticks: []clockTick{
+				{wall: "09:58:00", monotonic: 0},
+				{wall: "10:00:05", monotonic: time.Second},
+				{wall: "10:00:06", monotonic: time.Second},
+				{wall: "11:00:00", monotonic: 3594 * time.Second, wantPrint: true, wantSlot: "11:00"},
+			},
+		},
+		{
+			name: "small skew within tolerance still prints",
+			ticks: []clockTick{
+				{wall: "09:59:50", monotonic: 0},
+				{wall: "10:00:10", monotonic: 5 * time.Second, wantPrint: true, wantSlot: "10:00"},
+			},
+		},
+		{
+			name: "does not reprint after a backward jump",
+			ticks: []clockTick{
+				{wall: "10:59:59", monotonic: 0},
+				{wall: "11:00:00", monotonic: time.Second, wantPrint: true, wantSlot: "11:00"},
+				{wall: "10:00:00", monotonic: time.Second},
+				{wall: "10:00:01", monotonic: time.Second},
+				{wall: "11:00:00", monotonic: 3599 * time.Second},
+				{wall: "12:00:00", monotonic: time.Hour, wantPrint: true, wantSlot: "12:00"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runClockTicks(t, tt.ticks)
+		})
+	}
+}
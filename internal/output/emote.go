@@ -0,0 +1,230 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"os"
+	"sync"
+
+	twitch "github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/nantokaworks/twitch-overlay/internal/emoteresolver"
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// matteAlphaThreshold 未満の半透明ピクセルは紙（白）として扱う
+	matteAlphaThreshold = 0x80
+	// paperWhiteThreshold 以上の明るさのピクセルは印字しない
+	paperWhiteThreshold = 0xF0
+)
+
+// prepareEmote applies EMOTE_TRANSPARENCY to an emote before it is drawn on white paper.
+//   - "over":    そのまま合成（従来の動作）
+//   - "flatten": 白背景に合成し、半透明の縁はマットで切り落とす（暗い縁のにじみ対策）
+//   - "paper":   flatten に加えて、ほぼ白のピクセルを紙色として印字しない
+func prepareEmote(src image.Image) image.Image {
+	mode := env.Value.EmoteTransparency
+	if mode != "flatten" && mode != "paper" {
+		return src
+	}
+
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(src.At(x, y)).(color.NRGBA)
+
+			out := color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+			if c.A >= matteAlphaThreshold {
+				out = color.RGBA{c.R, c.G, c.B, 0xFF}
+			}
+			if mode == "paper" && out.R >= paperWhiteThreshold && out.G >= paperWhiteThreshold && out.B >= paperWhiteThreshold {
+				out = color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+			}
+			dst.SetRGBA(x-b.Min.X, y-b.Min.Y, out)
+		}
+	}
+	return dst
+}
+
+// emoteImageURL returns the image URL of an emote fragment.
+// BTTV/FFZ/7TV のエモートは emoteresolver が Id に画像URLを入れている
+func emoteImageURL(e *twitch.ChatMessageFragmentEmote) string {
+	if emoteresolver.IsThirdParty(e) {
+		return e.Id
+	}
+	return fmt.Sprintf("https://static-cdn.jtvnw.net/emoticons/v2/%s/static/light/3.0", e.Id)
+}
+
+// resolveThirdPartyEmotes replaces BTTV/FFZ/7TV emote codes in text with emote fragments (THIRD_PARTY_EMOTES)
+func resolveThirdPartyEmotes(text string) []twitch.ChatMessageFragment {
+	if !env.Value.ThirdPartyEmotes {
+		return []twitch.ChatMessageFragment{{Text: text}}
+	}
+	return emoteresolver.ReplaceText(text)
+}
+
+// emoteDownloadWorkers is the number of concurrent downloads while rendering one message
+const emoteDownloadWorkers = 4
+
+// prefetchImages downloads urls concurrently with a bounded worker pool.
+// 取得に失敗したURLはマップに含まれない（呼び出し側は文字/QRのみで描画を続ける）
+func prefetchImages(urls []string) map[string]image.Image {
+	images := make(map[string]image.Image, len(urls))
+	if len(urls) == 0 {
+		return images
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan string)
+	workers := emoteDownloadWorkers
+	if len(urls) < workers {
+		workers = len(urls)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for url := range jobs {
+				img, err := downloadEmote(url)
+				if err != nil {
+					logger.Debug("Failed to download image", zap.String("url", url), zap.Error(err))
+					continue
+				}
+				mu.Lock()
+				images[url] = img
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, url := range urls {
+		jobs <- url
+	}
+	close(jobs)
+	wg.Wait()
+	return images
+}
+
+// isGIF reports whether data starts with the GIF signature
+func isGIF(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("GIF87a")) || bytes.HasPrefix(data, []byte("GIF89a"))
+}
+
+// decodeGIFFirstFrame decodes an (animated) GIF and returns frame 0 composited on white.
+// 透過部分をそのままグレースケール化すると黒い四角になるため、不透明な画像にして返す
+func decodeGIFFirstFrame(data []byte) (image.Image, error) {
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(g.Image) == 0 {
+		return nil, fmt.Errorf("gif has no frames")
+	}
+
+	frame := g.Image[0]
+	w, h := g.Config.Width, g.Config.Height
+	if w <= 0 || h <= 0 {
+		w, h = frame.Bounds().Max.X, frame.Bounds().Max.Y
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+	// フレームは論理画面上の位置（Bounds）に描画する
+	draw.Draw(dst, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+	return dst, nil
+}
+
+// emoteFetch is an in-flight download shared by all callers requesting the same URL
+type emoteFetch struct {
+	wg          sync.WaitGroup
+	data        []byte
+	contentType string
+	err         error
+}
+
+var (
+	emoteFetchMu sync.Mutex
+	emoteFetches = make(map[string]*emoteFetch)
+
+	// キャッシュ書き込みの同時実行数制限（EMOTE_CACHE_MAX_WRITERS）
+	cacheWriteMu     sync.Mutex
+	cacheWriteCond   = sync.NewCond(&cacheWriteMu)
+	cacheWriteActive int
+)
+
+// fetchEmote downloads url and stores it in cacheFile.
+// 同じURLを同時に要求された場合はダウンロードを1回だけ行い、結果を共有する
+func fetchEmote(url, cacheFile string) ([]byte, string, error) {
+	emoteFetchMu.Lock()
+	if f, ok := emoteFetches[url]; ok {
+		emoteFetchMu.Unlock()
+		f.wg.Wait()
+		return f.data, f.contentType, f.err
+	}
+	f := &emoteFetch{}
+	f.wg.Add(1)
+	emoteFetches[url] = f
+	emoteFetchMu.Unlock()
+
+	defer func() {
+		emoteFetchMu.Lock()
+		delete(emoteFetches, url)
+		emoteFetchMu.Unlock()
+		f.wg.Done()
+	}()
+
+	data, ct, err := fetchImageBytes(url)
+	if err != nil {
+		f.err = err
+		return nil, "", err
+	}
+	f.data = data
+	f.contentType = ct
+
+	// キャッシュに保存（失敗しても処理継続）
+	if err := writeEmoteCache(cacheFile, data); err != nil {
+		logger.Debug("Failed to write emote cache", zap.String("file", cacheFile), zap.Error(err))
+	}
+
+	return f.data, f.contentType, nil
+}
+
+// writeEmoteCache writes data to path while limiting the number of concurrent writers.
+// 一時ファイルに書いてからリネームするので、読み込み側が書きかけのファイルを見ることはない
+func writeEmoteCache(path string, data []byte) error {
+	limit := env.Value.EmoteCacheMaxWriters
+	if limit < 1 {
+		limit = 1
+	}
+
+	cacheWriteMu.Lock()
+	for cacheWriteActive >= limit {
+		cacheWriteCond.Wait()
+	}
+	cacheWriteActive++
+	cacheWriteMu.Unlock()
+
+	defer func() {
+		cacheWriteMu.Lock()
+		cacheWriteActive--
+		cacheWriteMu.Unlock()
+		cacheWriteCond.Signal()
+	}()
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	touchEmoteCache(path)
+	return nil
+}
@@ -0,0 +1,156 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// emoteCacheDir is where downloaded emotes are cached (SHA1 of the URL as file name)
+const emoteCacheDir = ".cache"
+
+var (
+	emoteCacheMu sync.Mutex
+	// emoteLastUsed はキャッシュファイルの最終利用時刻（LRU 判定用）。
+	// 再起動後など記録がないファイルは更新時刻を使う
+	emoteLastUsed = make(map[string]time.Time)
+
+	emoteCacheHits   atomic.Int64
+	emoteCacheMisses atomic.Int64
+)
+
+// EmoteCacheStats returns the emote cache hits and misses since startup
+func EmoteCacheStats() (hits, misses int64) {
+	return emoteCacheHits.Load(), emoteCacheMisses.Load()
+}
+
+// readEmoteCache returns the cached emote at path.
+// EMOTE_CACHE_TTL_HOURS より古いエントリはミス扱いにして再ダウンロードさせる
+func readEmoteCache(path string) ([]byte, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		emoteCacheMisses.Add(1)
+		return nil, false
+	}
+	if ttl := emoteCacheTTL(); ttl > 0 && time.Since(info.ModTime()) > ttl {
+		emoteCacheMisses.Add(1)
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		emoteCacheMisses.Add(1)
+		return nil, false
+	}
+	emoteCacheHits.Add(1)
+
+	emoteCacheMu.Lock()
+	emoteLastUsed[path] = time.Now()
+	emoteCacheMu.Unlock()
+	return data, true
+}
+
+// touchEmoteCache records a fresh write and evicts old entries when over EMOTE_CACHE_MAX_MB
+func touchEmoteCache(path string) {
+	emoteCacheMu.Lock()
+	defer emoteCacheMu.Unlock()
+
+	emoteLastUsed[path] = time.Now()
+
+	maxBytes := int64(env.Value.EmoteCacheMaxMB) * 1024 * 1024
+	if maxBytes <= 0 {
+		return // 0 は無制限
+	}
+
+	entries, err := os.ReadDir(emoteCacheDir)
+	if err != nil {
+		return
+	}
+
+	type cacheFile struct {
+		path     string
+		size     int64
+		lastUsed time.Time
+	}
+	var files []cacheFile
+	var total int64
+	for _, e := range entries {
+		if e.IsDir() || strings.HasSuffix(e.Name(), ".tmp") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		p := filepath.Join(emoteCacheDir, e.Name())
+		lastUsed, ok := emoteLastUsed[p]
+		if !ok {
+			lastUsed = info.ModTime()
+		}
+		files = append(files, cacheFile{path: p, size: info.Size(), lastUsed: lastUsed})
+		total += info.Size()
+	}
+	if total <= maxBytes {
+		return
+	}
+
+	// 最後に使われたのが古い順に削除
+	sort.Slice(files, func(i, j int) bool { return files[i].lastUsed.Before(files[j].lastUsed) })
+	evicted := 0
+	for _, f := range files {
+		if total <= maxBytes {
+			break
+		}
+		if f.path == path {
+			continue // 今書いたばかりのファイルは残す
+		}
+		if err := os.Remove(f.path); err != nil {
+			continue
+		}
+		delete(emoteLastUsed, f.path)
+		total -= f.size
+		evicted++
+	}
+	logger.Debug("Evicted emote cache entries", zap.Int("evicted", evicted), zap.Int64("total_bytes", total))
+}
+
+// ClearEmoteCache removes every cached emote and returns how many files were deleted
+func ClearEmoteCache() (int, error) {
+	emoteCacheMu.Lock()
+	defer emoteCacheMu.Unlock()
+
+	entries, err := os.ReadDir(emoteCacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	removed := 0
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if err := os.Remove(filepath.Join(emoteCacheDir, e.Name())); err == nil {
+			removed++
+		}
+	}
+	emoteLastUsed = make(map[string]time.Time)
+
+	logger.Info("Emote cache cleared", zap.Int("removed", removed))
+	return removed, nil
+}
+
+// emoteCacheTTL returns EMOTE_CACHE_TTL_HOURS as a duration (0 = no expiry)
+func emoteCacheTTL() time.Duration {
+	return time.Duration(env.Value.EmoteCacheTTLHours) * time.Hour
+}
@@ -0,0 +1,66 @@
+package output
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/broadcast"
+)
+
+// FaxCounters counts what happened to faxes since startup, for the health badge in the UI
+type FaxCounters struct {
+	Printed int64 `json:"printed"` // 実際に印刷できた
+	Dropped int64 `json:"dropped"` // 印刷キューが満杯で捨てた
+	Blocked int64 `json:"blocked"` // 設定（イベント種別の無効化・CHEER_MIN_BITSなど）で印刷しなかった
+	Failed  int64 `json:"failed"`  // プリンターの準備・接続・印刷に失敗した
+
+	LastFailure   string     `json:"last_failure,omitempty"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+}
+
+var (
+	faxCountersMu sync.Mutex
+	faxCounters   FaxCounters
+)
+
+// GetFaxCounters returns a snapshot of the fax counters
+func GetFaxCounters() FaxCounters {
+	faxCountersMu.Lock()
+	defer faxCountersMu.Unlock()
+	return faxCounters
+}
+
+// RecordFaxBlocked counts a fax that was not printed because of the settings
+func RecordFaxBlocked() {
+	updateFaxCounters(func(c *FaxCounters) { c.Blocked++ })
+}
+
+func recordFaxPrinted() {
+	updateFaxCounters(func(c *FaxCounters) { c.Printed++ })
+}
+
+func recordFaxDropped() {
+	updateFaxCounters(func(c *FaxCounters) { c.Dropped++ })
+}
+
+func recordFaxFailed(reason string) {
+	updateFaxCounters(func(c *FaxCounters) {
+		now := time.Now()
+		c.Failed++
+		c.LastFailure = reason
+		c.LastFailureAt = &now
+	})
+}
+
+// updateFaxCounters applies fn and broadcasts the new totals so the UI badge stays current
+func updateFaxCounters(fn func(c *FaxCounters)) {
+	faxCountersMu.Lock()
+	fn(&faxCounters)
+	snapshot := faxCounters
+	faxCountersMu.Unlock()
+
+	broadcast.Send(map[string]interface{}{
+		"type": "fax_counters",
+		"data": snapshot,
+	})
+}
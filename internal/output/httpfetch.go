@@ -0,0 +1,65 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// imageFetchAttempts はエモート/アバター取得の最大試行回数
+	imageFetchAttempts = 3
+	// imageFetchBackoff は1回目のリトライまでの待ち時間（以降は倍々）
+	imageFetchBackoff = 500 * time.Millisecond
+)
+
+// imageHTTPClient is shared by emote and avatar downloads so a stalled CDN cannot block rendering forever
+var imageHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// fetchImageBytes GETs url and returns the body and Content-Type.
+// ネットワークエラーと 5xx は少し待ってリトライし、それ以外のエラー（404 など）は即座に返す
+func fetchImageBytes(url string) ([]byte, string, error) {
+	backoff := imageFetchBackoff
+	var lastErr error
+	for attempt := 1; attempt <= imageFetchAttempts; attempt++ {
+		data, ct, retryable, err := fetchImageOnce(url)
+		if err == nil {
+			return data, ct, nil
+		}
+		lastErr = err
+		if !retryable || attempt == imageFetchAttempts {
+			break
+		}
+		logger.Debug("Retrying image download",
+			zap.String("url", url),
+			zap.Int("attempt", attempt),
+			zap.Error(err))
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	return nil, "", lastErr
+}
+
+// fetchImageOnce performs a single GET and reports whether a failure is worth retrying
+func fetchImageOnce(url string) ([]byte, string, bool, error) {
+	resp, err := imageHTTPClient.Get(url)
+	if err != nil {
+		return nil, "", true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// エラーページをキャッシュしたりデコードしたりしないよう失敗扱いにする
+		return nil, "", resp.StatusCode >= 500, fmt.Errorf("%s: status %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", true, err
+	}
+	return data, resp.Header.Get("Content-Type"), false, nil
+}
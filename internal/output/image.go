@@ -5,6 +5,7 @@ import (
 	"crypto/sha1"
 	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
@@ -12,19 +13,18 @@ import (
 	"image/gif"
 	"image/jpeg"
 	"image/png"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
-	"runtime"
 	"strings"
 	"time"
 
 	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/nantokaworks/twitch-overlay/internal/broadcast"
 	"github.com/nantokaworks/twitch-overlay/internal/env"
 	"github.com/nantokaworks/twitch-overlay/internal/fontmanager"
 	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/numfmt"
 	"github.com/nantokaworks/twitch-overlay/internal/twitchapi"
 	"github.com/skip2/go-qrcode"
 	"go.uber.org/zap"
@@ -32,85 +32,101 @@ import (
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/opentype"
 	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/webp"
 )
 
-// getSystemDefaultFont はOSのデフォルトフォントを取得します
-func getSystemDefaultFont() ([]byte, error) {
-	var fontPaths []string
-	var triedPaths []string
-
-	switch runtime.GOOS {
-	case "darwin": // macOS
-		fontPaths = []string{
-			// TTFファイルを優先
-			"/System/Library/Fonts/Supplemental/Arial.ttf",
-			"/System/Library/Fonts/Supplemental/Arial Unicode.ttf",
-			"/System/Library/Fonts/Supplemental/Courier New.ttf",
-			"/System/Library/Fonts/Supplemental/Georgia.ttf",
-			"/System/Library/Fonts/Supplemental/Times New Roman.ttf",
-			"/System/Library/Fonts/Supplemental/Verdana.ttf",
-			// 標準フォント
-			"/System/Library/Fonts/Avenir.ttc",
-			"/System/Library/Fonts/Helvetica.ttc",
-			"/System/Library/Fonts/Times.ttc",
-			"/System/Library/Fonts/Courier.ttc",
-			// 日本語フォント（TTCファイル）
-			"/System/Library/Fonts/Hiragino Sans GB.ttc",
-			"/System/Library/Fonts/ヒラギノ角ゴ ProN W3.ttc",
-		}
-	case "windows":
-		fontPaths = []string{
-			// TTFファイルを優先
-			"C:\\Windows\\Fonts\\arial.ttf",
-			"C:\\Windows\\Fonts\\times.ttf",
-			"C:\\Windows\\Fonts\\cour.ttf",
-			"C:\\Windows\\Fonts\\verdana.ttf",
-			// 日本語フォント
-			"C:\\Windows\\Fonts\\YuGothM.ttc",
-			"C:\\Windows\\Fonts\\msgothic.ttc",
-		}
-	default: // Linux and others
-		fontPaths = []string{
-			// TTFファイルを優先
-			"/usr/share/fonts/truetype/liberation/LiberationSans-Regular.ttf",
-			"/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
-			"/usr/share/fonts/truetype/ubuntu/Ubuntu-R.ttf",
-			"/usr/share/fonts/truetype/noto/NotoSans-Regular.ttf",
-			// 日本語フォント
-			"/usr/share/fonts/opentype/noto/NotoSansCJK-Regular.ttc",
-		}
+// DefaultPaperWidth is the dot width of 58mm printers
+const DefaultPaperWidth = 384
+
+// PAPER_WIDTH の許容範囲（範囲外の値は DefaultPaperWidth として扱う）
+const minPaperWidth = 200
+const maxPaperWidth = 1200
+
+// PaperWidth returns the printable width in pixels (PAPER_WIDTH, 80mm機なら576)
+func PaperWidth() int {
+	if w := env.Value.PaperWidth; w >= minPaperWidth && w <= maxPaperWidth {
+		return w
 	}
+	return DefaultPaperWidth
+}
 
-	// Try each font path
-	for _, path := range fontPaths {
-		triedPaths = append(triedPaths, path)
-		if data, err := os.ReadFile(path); err == nil {
-			logger.Info("Using system font", zap.String("path", path))
-			return data, nil
-		}
+// 下端の線のテキスト下からのマージン（px）
+const UnderlineMargin = 10
+
+// 下端の線の既定値（UNDERLINE_* が未設定・範囲外の場合に使う）
+const (
+	defaultUnderlineHeight     = 4
+	defaultUnderlineDashLength = 8
+	defaultUnderlineDashGap    = 4
+)
+
+// underlineStyle returns UNDERLINE_STYLE: "solid", "dashed" or "none"
+func underlineStyle() string {
+	switch env.Value.UnderlineStyle {
+	case "solid", "none":
+		return env.Value.UnderlineStyle
+	default:
+		return "dashed"
+	}
+}
+
+// underlineHeight returns the underline thickness in pixels (UNDERLINE_THICKNESS)
+func underlineHeight() int {
+	if h := env.Value.UnderlineThickness; h >= 1 && h <= 32 {
+		return h
 	}
+	return defaultUnderlineHeight
+}
 
-	// エラー時は試したパスを全て出力
-	logger.Error("No suitable font found on system",
-		zap.Strings("tried_paths", triedPaths),
-		zap.String("os", runtime.GOOS),
-		zap.String("solution", "Please upload a custom font via the settings page or install system fonts"))
+// underlineDash returns the dash and gap lengths in pixels (UNDERLINE_DASH_LENGTH / UNDERLINE_DASH_GAP)
+func underlineDash() (length, gap int) {
+	length, gap = defaultUnderlineDashLength, defaultUnderlineDashGap
+	if l := env.Value.UnderlineDashLength; l >= 1 && l <= 100 {
+		length = l
+	}
+	if g := env.Value.UnderlineDashGap; g >= 1 && g <= 100 {
+		gap = g
+	}
+	return length, gap
+}
 
-	return nil, fmt.Errorf("no suitable font found on system. Please either: 1) Upload a custom font via the settings page (/settings), or 2) Install system fonts (e.g., 'sudo apt-get install fonts-liberation fonts-dejavu' on Ubuntu/Debian)")
+// underlineSpace returns the vertical space reserved below the text for the underline (none なら 0)
+func underlineSpace() int {
+	if underlineStyle() == "none" {
+		return 0
+	}
+	return UnderlineMargin + underlineHeight()
 }
 
-const PaperWidth = 384
+// drawUnderline draws the bottom line at y according to UNDERLINE_STYLE
+func drawUnderline(img *image.RGBA, y int) {
+	style := underlineStyle()
+	if style == "none" {
+		return
+	}
+	height := underlineHeight()
+	width := img.Bounds().Dx()
+	if style == "solid" {
+		fillRect(img, 0, y, width, height)
+		return
+	}
+	dashLength, dashGap := underlineDash()
+	for x0 := 0; x0 < width; x0 += dashLength + dashGap {
+		end := x0 + dashLength
+		if end > width {
+			end = width
+		}
+		fillRect(img, x0, y, end-x0, height)
+	}
+}
 
-// 下端の線の太さ（px）とテキスト下からのマージン（px）
-const UnderlineHeight = 4
-const UnderlineMargin = 10
+// DefaultFontSize is the base FAX font size in points (FONT_SIZE の既定値)
+const DefaultFontSize = 32
 
-// 破線設定
-const UnderlineDashed = true  // true=破線, false=実線
-const UnderlineDashLength = 8 // 線分の長さ(px)
-const UnderlineDashGap = 4    // 線分間の間隔(px)
+// FONT_SIZE の許容範囲
+const minFontSize = 12
+const maxFontSize = 96
 
-const fontSize = 32
 const avatarSize = 100
 
 // Common drawing functions
@@ -136,7 +152,7 @@ func rotateImage180(src image.Image) image.Image {
 // drawHorizontalLine draws a horizontal line with optional margins
 func drawHorizontalLine(img *image.RGBA, y, leftMargin, rightMargin, thickness int, c color.Color) {
 	for lineY := 0; lineY < thickness; lineY++ {
-		for x := leftMargin; x < PaperWidth-rightMargin; x++ {
+		for x := leftMargin; x < PaperWidth()-rightMargin; x++ {
 			img.Set(x, y+lineY, c)
 		}
 	}
@@ -147,30 +163,35 @@ func drawCenteredText(d *font.Drawer, text string, yPos int) {
 	bounds, _ := d.BoundString(text)
 	textWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 	d.Dot = fixed.Point26_6{
-		X: fixed.I((PaperWidth - textWidth) / 2),
+		X: fixed.I((PaperWidth() - textWidth) / 2),
 		Y: fixed.I(yPos) + d.Face.Metrics().Ascent,
 	}
 	d.DrawString(text)
 }
 
 // wrapFragments はテキスト/Emote/URL混合フラグメントを maxWidth で折り返し、行単位で返す
-func wrapFragments(frags []twitch.ChatMessageFragment, face font.Face, maxWidth, lineHeight int) [][]twitch.ChatMessageFragment {
+func wrapFragments(frags []twitch.ChatMessageFragment, widths *runeWidthCache, maxWidth, lineHeight int) [][]twitch.ChatMessageFragment {
 	var lines [][]twitch.ChatMessageFragment
 	var curr []twitch.ChatMessageFragment
 	currW := 0
 	urlRe := regexp.MustCompile(`https?://\S+`)
+	// text_only / text_qr の場合は URL も通常の文字と同じく折り返す
+	mode := printURLMode()
+	urlAsText := mode == urlModeTextOnly || mode == urlModeTextQR
 
-	// 1文字 or Emote or URL 単位に展開
+	// 1文字（書記素クラスタ） or Emote or URL 単位に展開
+	// 文字単位で折り返すため、スペースのない長い単語も maxWidth で強制的に改行される
 	var list []twitch.ChatMessageFragment
 	for _, f := range frags {
 		f.Text = strings.ReplaceAll(f.Text, "\n", "")
 		if f.Emote != nil {
 			list = append(list, f)
-		} else if urlRe.MatchString(f.Text) {
+		} else if !urlAsText && urlRe.MatchString(f.Text) {
 			list = append(list, f)
 		} else {
-			for _, r := range f.Text {
-				list = append(list, twitch.ChatMessageFragment{Text: string(r)})
+			// 書記素クラスタ単位に展開し、結合文字やZWJ絵文字・国旗を行頭で分離させない
+			for _, g := range graphemes(f.Text) {
+				list = append(list, twitch.ChatMessageFragment{Text: g})
 			}
 		}
 	}
@@ -190,7 +211,7 @@ func wrapFragments(frags []twitch.ChatMessageFragment, face font.Face, maxWidth,
 		if f.Emote != nil {
 			w = lineHeight
 		} else {
-			w = int((&font.Drawer{Face: face}).MeasureString(f.Text) >> 6)
+			w = widths.measure(f.Text)
 		}
 		if currW+w > maxWidth && len(curr) > 0 {
 			lines = append(lines, curr)
@@ -208,44 +229,101 @@ func wrapFragments(frags []twitch.ChatMessageFragment, face font.Face, maxWidth,
 
 // generateQR はテキストを QR に変換して image.Image を返す
 func generateQR(text string, size int) (image.Image, error) {
-	pngBytes, err := qrcode.Encode(text, qrcode.Medium, size)
+	pngBytes, err := qrcode.Encode(text, qrRecoveryLevel(), size)
 	if err != nil {
 		return nil, err
 	}
 	return png.Decode(bytes.NewReader(pngBytes))
 }
 
+// PRINT_URL_MODE の値
+const (
+	urlModeImageQR  = "image_qr"  // URL先の画像＋QR（従来の動作）
+	urlModeQROnly   = "qr_only"   // QRのみ
+	urlModeTextOnly = "text_only" // 通常のテキストとして折り返す
+	urlModeTextQR   = "text_qr"   // テキストとして折り返し、末尾に番号付きの小さなQRを並べる
+)
+
+// printURLMode returns how URLs in chat messages are printed (PRINT_URL_MODE).
+// 旧設定の QR_ONLY=true は qr_only として扱う
+func printURLMode() string {
+	switch env.Value.PrintURLMode {
+	case urlModeQROnly, urlModeTextOnly, urlModeTextQR:
+		return env.Value.PrintURLMode
+	}
+	if env.Value.QrOnly {
+		return urlModeQROnly
+	}
+	return urlModeImageQR
+}
+
+// qrRecoveryLevel maps QR_RECOVERY_LEVEL to the qrcode error-correction level
+func qrRecoveryLevel() qrcode.RecoveryLevel {
+	switch env.Value.QrRecoveryLevel {
+	case "low":
+		return qrcode.Low
+	case "high":
+		return qrcode.High
+	case "highest":
+		return qrcode.Highest
+	default:
+		return qrcode.Medium
+	}
+}
+
+// qrSize returns the printed QR code size: width capped by QR_MAX_SIZE (0 = no cap)
+func qrSize(width int) int {
+	if limit := env.Value.QrMaxSize; limit > 0 && limit < width {
+		return limit
+	}
+	return width
+}
+
+// drawQR draws a QR code for text horizontally centered at top and returns its height (失敗時は0)
+func drawQR(img *image.RGBA, text string, top int) int {
+	width := img.Bounds().Dx()
+	qrImg, err := generateQR(text, qrSize(width))
+	if err != nil {
+		return 0
+	}
+	b := qrImg.Bounds()
+	x := (width - b.Dx()) / 2
+	if x < 0 {
+		x = 0
+	}
+	draw.Draw(img, image.Rect(x, top, x+b.Dx(), top+b.Dy()), qrImg, b.Min, draw.Over)
+	return b.Dy()
+}
+
 // downloadEmote は URL から emote 画像を取得し、MIME タイプで PNG/JPEG/GIF を判別してデコード
 func downloadEmote(url string) (image.Image, error) {
 	// キャッシュディレクトリ準備
-	cacheDir := ".cache"
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+	if err := os.MkdirAll(emoteCacheDir, 0755); err != nil {
 		return nil, err
 	}
 	// URLハッシュでファイル名生成
 	h := sha1.Sum([]byte(url))
-	cacheFile := filepath.Join(cacheDir, hex.EncodeToString(h[:]))
-	// キャッシュから読み込み
-	if data, err := os.ReadFile(cacheFile); err == nil {
+	cacheFile := filepath.Join(emoteCacheDir, hex.EncodeToString(h[:]))
+	// キャッシュから読み込み（TTL切れはミス扱い）
+	if data, ok := readEmoteCache(cacheFile); ok {
+		if isGIF(data) {
+			return decodeGIFFirstFrame(data)
+		}
 		img, _, err := image.Decode(bytes.NewReader(data))
 		return img, err
 	}
 
-	// ネットワークから取得
-	resp, err := http.Get(url)
+	// ネットワークから取得（同じURLの同時ダウンロードは1回にまとめる）
+	data, ct, err := fetchEmote(url, cacheFile)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	// アニメーションGIFは Content-Type に関係なく1フレーム目を白背景に合成して使う
+	if isGIF(data) {
+		return decodeGIFFirstFrame(data)
 	}
-	// キャッシュに保存（失敗しても処理継続）
-	_ = os.WriteFile(cacheFile, data, 0644)
 
-	ct := resp.Header.Get("Content-Type")
 	switch {
 	case strings.Contains(ct, "png"):
 		return png.Decode(bytes.NewReader(data))
@@ -253,6 +331,8 @@ func downloadEmote(url string) (image.Image, error) {
 		return gif.Decode(bytes.NewReader(data))
 	case strings.Contains(ct, "jpeg"), strings.Contains(ct, "jpg"):
 		return jpeg.Decode(bytes.NewReader(data))
+	case strings.Contains(ct, "webp"):
+		return webp.Decode(bytes.NewReader(data))
 	default:
 		// フォールバック：PNG→GIF→JPEG
 		if img, err := png.Decode(bytes.NewReader(data)); err == nil {
@@ -274,11 +354,13 @@ func resizeToHeight(src image.Image, targetH int) image.Image {
 	return dst
 }
 
-// resizeToWidth は元画像を幅 PaperWidth にアスペクト比維持でリサイズ
-func resizeToWidth(src image.Image) image.Image {
+// resizeToWidth は元画像を幅 width にアスペクト比維持でリサイズ
+// 透過部分は紙の色（白）にする（透明のままだと輝度0として黒く印刷される）
+func resizeToWidth(src image.Image, width int) image.Image {
 	b := src.Bounds()
-	h := b.Dy() * PaperWidth / b.Dx()
-	dst := image.NewRGBA(image.Rect(0, 0, PaperWidth, h))
+	h := b.Dy() * width / b.Dx()
+	dst := image.NewRGBA(image.Rect(0, 0, width, h))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
 	xdraw.ApproxBiLinear.Scale(dst, dst.Bounds(), src, b, xdraw.Over, nil)
 	return dst
 }
@@ -296,23 +378,59 @@ func rotate90(src image.Image) image.Image {
 	return dst
 }
 
+// fontError converts a fontmanager.ParseCurrentFont error into the message shown to the user.
+// 破損はフォントマネージャー側で1回だけログ出力するので、ここではログに出さない
+func fontError(err error) error {
+	if fontmanager.FontError() != nil {
+		return fmt.Errorf("フォントファイルが破損しています。設定ページ(/settings)から再アップロードしてください: %w", err)
+	}
+	logger.Error("Failed to get font", zap.Error(err))
+	return fmt.Errorf("フォントがアップロードされていません。設定ページ(/settings)からフォントファイル(TTF/OTF)をアップロードしてください")
+}
+
+// baseFontSize returns the FAX font size in points (FONT_SIZE)
+func baseFontSize() int {
+	if size := env.Value.FontSize; size >= minFontSize && size <= maxFontSize {
+		return size
+	}
+	return DefaultFontSize
+}
+
+// scaledFontSize scales a size designed for DefaultFontSize to the configured FONT_SIZE.
+// 時計の各フォントやレイアウトの高さはこの値を基準に比例させる
+func scaledFontSize(size int) int {
+	return size * baseFontSize() / DefaultFontSize
+}
+
+// singleCharFontSize returns the font size used to print a single-character message.
+// 描画幅 width いっぱいまで拡大するが、幅の狭いグリフが巨大なブロックにならないよう SINGLE_CHAR_MAX_SCALE 倍で頭打ちにする
+func singleCharFontSize(origW, width, fontSize int) float64 {
+	scale := float64(width) / float64(origW)
+	maxScale := float64(env.Value.SingleCharMaxScale)
+	if maxScale < 1 {
+		maxScale = 1
+	}
+	if scale > maxScale {
+		scale = maxScale
+	}
+	return float64(fontSize) * scale
+}
+
 // MessageToImage creates an image from the message with optional color support
 func MessageToImage(userName string, msg []twitch.ChatMessageFragment, useColor bool) (image.Image, error) {
-	// フォントマネージャーからフォントデータを取得（カスタムフォント必須）
-	fontData, err := fontmanager.GetFont(nil)
-	if err != nil {
-		logger.Error("Failed to get font", zap.Error(err))
-		return nil, fmt.Errorf("フォントがアップロードされていません。設定ページ(/settings)からフォントファイル(TTF/OTF)をアップロードしてください")
-	}
+	return messageToImage(userName, msg, useColor, baseFontSize())
+}
 
-	// 新しいフォントを作成（拡大文字）
-	f, err := opentype.Parse(fontData)
+// messageToImage renders the message at the given font size (プレビューで FONT_SIZE の変更を試すため)
+func messageToImage(userName string, msg []twitch.ChatMessageFragment, useColor bool, fontSize int) (image.Image, error) {
+	// フォントマネージャーからパース済みのフォントを取得（カスタムフォント必須）
+	f, err := fontmanager.ParseCurrentFont()
 	if err != nil {
-		return nil, err
+		return nil, fontError(err)
 	}
 
 	face, err := opentype.NewFace(f, &opentype.FaceOptions{
-		Size:    fontSize,
+		Size:    float64(fontSize),
 		DPI:     72,
 		Hinting: font.HintingFull,
 	})
@@ -325,9 +443,16 @@ func MessageToImage(userName string, msg []twitch.ChatMessageFragment, useColor
 	descent := int(face.Metrics().Descent >> 6)
 	lineHeight := int(face.Metrics().Height >> 6)
 
+	// テーマの枠の内側の幅で描画する（後から縮小すると文字がぼやける）
+	theme := currentFaxTheme()
+	width := faxContentWidth(theme)
+
 	// メッセージ改行削除＋URL分割
 	var processed []twitch.ChatMessageFragment
 	urlRe := regexp.MustCompile(`https?://\S+`)
+	// text_qr の場合は URL の後ろに [n] を付け、末尾に同じ番号の QR を並べる
+	var footerURLs []string
+	footerIndex := make(map[string]int)
 	for _, frag := range msg {
 		if frag.Emote != nil {
 			processed = append(processed, frag)
@@ -338,33 +463,70 @@ func MessageToImage(userName string, msg []twitch.ChatMessageFragment, useColor
 		prev := 0
 		for _, idx := range idxs {
 			if idx[0] > prev {
-				processed = append(processed, twitch.ChatMessageFragment{Text: text[prev:idx[0]]})
+				processed = append(processed, resolveThirdPartyEmotes(text[prev:idx[0]])...)
+			}
+			u := text[idx[0]:idx[1]]
+			processed = append(processed, twitch.ChatMessageFragment{Text: u})
+			if printURLMode() == urlModeTextQR {
+				n, ok := footerIndex[u]
+				if !ok {
+					footerURLs = append(footerURLs, u)
+					n = len(footerURLs)
+					footerIndex[u] = n
+				}
+				processed = append(processed, twitch.ChatMessageFragment{Text: fmt.Sprintf("[%d]", n)})
 			}
-			processed = append(processed, twitch.ChatMessageFragment{Text: text[idx[0]:idx[1]]})
 			prev = idx[1]
 		}
 		if prev < len(text) {
-			processed = append(processed, twitch.ChatMessageFragment{Text: text[prev:]})
+			processed = append(processed, resolveThirdPartyEmotes(text[prev:])...)
 		}
 	}
 
 	// 折り返し
-	lines := wrapFragments(processed, face, PaperWidth, lineHeight)
+	widths := newRuneWidthCache(face)
+	lines := wrapFragments(processed, widths, width, lineHeight)
+
+	// ユーザー名もスペースのない長い名前が紙幅をはみ出さないよう折り返す
+	userLines := wrapText(userName, widths, width)
+	if len(userLines) == 0 {
+		userLines = []string{""}
+	}
+
+	// エモートとURL画像は描画前にまとめて並列ダウンロードしておく（出現順で重複排除）
+	var imageURLs []string
+	seenURLs := make(map[string]bool)
+	for _, line := range lines {
+		for _, frag := range line {
+			url := ""
+			if frag.Emote != nil {
+				url = emoteImageURL(frag.Emote)
+			} else if urlRe.MatchString(frag.Text) && printURLMode() == urlModeImageQR {
+				// qr_only / text_only の場合はURL先の画像を取得しない
+				url = frag.Text
+			}
+			if url != "" && !seenURLs[url] {
+				seenURLs[url] = true
+				imageURLs = append(imageURLs, url)
+			}
+		}
+	}
+	images := prefetchImages(imageURLs)
 
 	// 動的な高さ計算
-	currH := ascent + descent
+	currH := ascent + descent + (len(userLines)-1)*lineHeight
 	for _, line := range lines {
 		// URL-only 行
 		if len(line) == 1 && urlRe.MatchString(line[0].Text) {
-			img0, err := downloadEmote(line[0].Text)
-			if err != nil {
-				currH += PaperWidth
+			img0, ok := images[line[0].Text]
+			if !ok {
+				currH += qrSize(width)
 			} else {
 				if img0.Bounds().Dx() > img0.Bounds().Dy() {
 					img0 = rotate90(img0)
 				}
-				h := img0.Bounds().Dy() * PaperWidth / img0.Bounds().Dx()
-				currH += h + PaperWidth
+				h := img0.Bounds().Dy() * width / img0.Bounds().Dx()
+				currH += h + qrSize(width)
 			}
 			continue
 		}
@@ -380,21 +542,20 @@ func MessageToImage(userName string, msg []twitch.ChatMessageFragment, useColor
 			}
 		}
 		if len(lines) == 1 && !hasNonEmptyText && len(emoteFrags) > 0 && len(emoteFrags) <= 8 {
-			cellW := PaperWidth / len(emoteFrags)
+			cellW := width / len(emoteFrags)
 			currH += cellW
 			continue
 		}
 
 		// single-character text-only line
-		if len(lines) == 1 && len(line) == 1 &&
+		if env.Value.SingleCharScale && len(lines) == 1 && len(line) == 1 &&
 			line[0].Emote == nil &&
 			!urlRe.MatchString(line[0].Text) &&
 			len([]rune(strings.TrimSpace(line[0].Text))) == 1 {
 			text := strings.TrimSpace(line[0].Text)
-			origW := int((&font.Drawer{Face: face}).MeasureString(text) >> 6)
+			origW := widths.measure(text)
 			if origW > 0 {
-				scale := float64(PaperWidth) / float64(origW)
-				newSize := float64(fontSize) * scale
+				newSize := singleCharFontSize(origW, width, fontSize)
 				face2, err := opentype.NewFace(f, &opentype.FaceOptions{
 					Size:    newSize,
 					DPI:     72,
@@ -408,23 +569,27 @@ func MessageToImage(userName string, msg []twitch.ChatMessageFragment, useColor
 		}
 		currH += lineHeight
 	}
-	imgHeight := currH + UnderlineMargin + UnderlineHeight
+	footerTop := currH
+	currH += urlFooterHeight(len(footerURLs), lineHeight, width)
+	imgHeight := currH + underlineSpace()
 
 	// 画像生成 - カラー版
-	img := image.NewRGBA(image.Rect(0, 0, PaperWidth, imgHeight))
+	img := image.NewRGBA(image.Rect(0, 0, width, imgHeight))
 	// 白背景
 	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
 
 	// Drawer準備
 	d := &font.Drawer{Dst: img, Src: image.Black, Face: face}
 
-	// 1行目: userName
-	d.Dot = fixed.Point26_6{X: fixed.I(0), Y: fixed.I(ascent)}
-	d.DrawString(userName)
+	// 1行目: userName（折り返した場合は複数行）
+	for i, userLine := range userLines {
+		d.Dot = fixed.Point26_6{X: fixed.I(0), Y: fixed.I(i*lineHeight + ascent)}
+		d.DrawString(userLine)
+	}
 
-	// 2行目以降: 折返し後の行を描画
+	// ユーザー名の次の行から: 折返し後の行を描画
 	for i, line := range lines {
-		y := (i+1)*lineHeight + ascent
+		y := (i+len(userLines))*lineHeight + ascent
 
 		// 全て Emote の場合の特別処理
 		var emoteFrags []twitch.ChatMessageFragment
@@ -437,24 +602,21 @@ func MessageToImage(userName string, msg []twitch.ChatMessageFragment, useColor
 				break
 			}
 		}
-		if !hasNonEmptyText && len(emoteFrags) > 0 && len(emoteFrags) <= 8 {
-			cellW := PaperWidth / len(emoteFrags)
+		// 高さ計算と同じ条件（1行のみの場合）で判定する
+		if len(lines) == 1 && !hasNonEmptyText && len(emoteFrags) > 0 && len(emoteFrags) <= 8 {
+			cellW := width / len(emoteFrags)
 			for j, frag := range emoteFrags {
-				url := fmt.Sprintf(
-					"https://static-cdn.jtvnw.net/emoticons/v2/%s/static/light/3.0",
-					frag.Emote.Id,
-				)
-				eimg, err := downloadEmote(url)
-				if err != nil {
+				eimg, ok := images[emoteImageURL(frag.Emote)]
+				if !ok {
 					continue
 				}
 				// 正方形(cellW×cellW)にリサイズ
 				dst := image.NewRGBA(image.Rect(0, 0, cellW, cellW))
 				xdraw.ApproxBiLinear.Scale(dst, dst.Bounds(), eimg, eimg.Bounds(), xdraw.Over, nil)
 				// カラーモードでない場合はグレースケール変換
-				var drawImg image.Image = dst
+				drawImg := prepareEmote(dst)
 				if !useColor {
-					drawImg = convertToGrayscaleWithDithering(dst)
+					drawImg = convertToGrayscaleWithDithering(drawImg)
 				}
 				draw.Draw(img,
 					image.Rect(j*cellW, y-ascent, j*cellW+cellW, y-ascent+cellW),
@@ -464,15 +626,15 @@ func MessageToImage(userName string, msg []twitch.ChatMessageFragment, useColor
 		}
 
 		// single-character text-only line
-		if len(line) == 1 &&
+		if env.Value.SingleCharScale && len(lines) == 1 && len(line) == 1 &&
 			line[0].Emote == nil &&
 			!urlRe.MatchString(line[0].Text) &&
 			len([]rune(strings.TrimSpace(line[0].Text))) == 1 {
 			text := strings.TrimSpace(line[0].Text)
-			origW := int(d.MeasureString(text) >> 6)
+			// 計測は描画用の d を使わない（Dot の状態に依存させない）
+			origW := widths.measure(text)
 			if origW > 0 {
-				scale := float64(PaperWidth) / float64(origW)
-				newSize := float64(fontSize) * scale
+				newSize := singleCharFontSize(origW, width, fontSize)
 				face2, err := opentype.NewFace(f, &opentype.FaceOptions{
 					Size:    newSize,
 					DPI:     72,
@@ -481,15 +643,15 @@ func MessageToImage(userName string, msg []twitch.ChatMessageFragment, useColor
 				if err == nil {
 					ascent2 := int(face2.Metrics().Ascent >> 6)
 					d2 := &font.Drawer{Dst: img, Src: image.Black, Face: face2}
-					w2 := int(d2.MeasureString(text) >> 6)
-					x2 := (PaperWidth - w2) / 2
+					w2 := int(font.MeasureString(face2, text) >> 6)
+					x2 := (width - w2) / 2
 					d2.Dot = fixed.Point26_6{
 						X: fixed.I(x2),
 						Y: fixed.I(y - ascent + ascent2),
 					}
 					d2.DrawString(text)
 				} else {
-					x := (PaperWidth - origW) / 2
+					x := (width - origW) / 2
 					d.Dot = fixed.Point26_6{X: fixed.I(x), Y: fixed.I(y)}
 					d.DrawString(text)
 				}
@@ -501,53 +663,39 @@ func MessageToImage(userName string, msg []twitch.ChatMessageFragment, useColor
 		for _, frag := range line {
 			// URL-only 行：画像＋QR
 			if frag.Emote == nil && urlRe.MatchString(frag.Text) {
-				img0, err := downloadEmote(frag.Text)
-				if err == nil {
+				if img0, ok := images[frag.Text]; ok {
 					if img0.Bounds().Dx() > img0.Bounds().Dy() {
 						img0 = rotate90(img0)
 					}
-					img0 = resizeToWidth(img0)
+					img0 = resizeToWidth(img0, width)
 					// カラーモードでない場合はグレースケール変換
 					var drawImg image.Image = img0
 					if !useColor {
 						drawImg = convertToGrayscaleWithDithering(img0)
 					}
 					draw.Draw(img,
-						image.Rect(0, y-ascent, PaperWidth, y-ascent+drawImg.Bounds().Dy()),
+						image.Rect(0, y-ascent, width, y-ascent+drawImg.Bounds().Dy()),
 						drawImg, image.Point{}, draw.Over)
 					// QR
-					qrImg, err := generateQR(frag.Text, PaperWidth)
-					if err == nil {
-						draw.Draw(img,
-							image.Rect(0, y-ascent+img0.Bounds().Dy(), PaperWidth, y-ascent+img0.Bounds().Dy()+PaperWidth),
-							qrImg, image.Point{}, draw.Over)
-					}
-					x = PaperWidth
+					drawQR(img, frag.Text, y-ascent+img0.Bounds().Dy())
+					x = width
 					continue
 				}
-				// 画像取得失敗→QR のみ
-				qrImg, err := generateQR(frag.Text, PaperWidth)
-				if err != nil {
+				// 画像取得失敗（または QR_ONLY）→QR のみ
+				if drawQR(img, frag.Text, y-ascent) == 0 {
 					continue
 				}
-				draw.Draw(img,
-					image.Rect(0, y-ascent, PaperWidth, y-ascent+PaperWidth),
-					qrImg, image.Point{}, draw.Over)
-				x = PaperWidth
+				x = width
 				continue
 			}
 
 			// Emote
 			if frag.Emote != nil {
-				url := fmt.Sprintf(
-					"https://static-cdn.jtvnw.net/emoticons/v2/%s/static/light/3.0",
-					frag.Emote.Id,
-				)
-				eimg, err := downloadEmote(url)
-				if err != nil {
+				eimg, ok := images[emoteImageURL(frag.Emote)]
+				if !ok {
 					continue
 				}
-				eimg = resizeToHeight(eimg, lineHeight)
+				eimg = prepareEmote(resizeToHeight(eimg, lineHeight))
 				// カラーモードでない場合はグレースケール変換
 				var drawEmote image.Image = eimg
 				if !useColor {
@@ -563,37 +711,27 @@ func MessageToImage(userName string, msg []twitch.ChatMessageFragment, useColor
 			// 通常テキスト
 			d.Dot = fixed.Point26_6{X: fixed.I(x), Y: fixed.I(y)}
 			d.DrawString(frag.Text)
-			x += int(d.MeasureString(frag.Text) >> 6)
+			x += widths.measure(frag.Text)
 		}
 	}
 
-	// 下線描画
-	underlineY := currH + UnderlineMargin
-	if UnderlineDashed {
-		for x0 := 0; x0 < PaperWidth; x0 += UnderlineDashLength + UnderlineDashGap {
-			end := x0 + UnderlineDashLength
-			if end > PaperWidth {
-				end = PaperWidth
-			}
-			for y := 0; y < UnderlineHeight; y++ {
-				for x := x0; x < end; x++ {
-					img.Set(x, underlineY+y, color.Black)
-				}
-			}
-		}
-	} else {
-		for y := 0; y < UnderlineHeight; y++ {
-			for x := 0; x < PaperWidth; x++ {
-				img.Set(x, underlineY+y, color.Black)
-			}
-		}
+	if len(footerURLs) > 0 {
+		drawURLFooter(img, d, footerURLs, footerTop, lineHeight, ascent)
 	}
 
-	return img, nil
+	// 下線描画
+	drawUnderline(img, currH+UnderlineMargin)
+
+	return applyFaxTheme(img, theme), nil
 }
 
 // convertToGrayscaleWithDithering converts a color image to grayscale with optional dithering
 func convertToGrayscaleWithDithering(src image.Image) image.Image {
+	return convertToGrayscaleWithOptions(src, currentPreviewOptions())
+}
+
+// convertToGrayscaleWithOptions converts a color image to grayscale using the given dither/black point
+func convertToGrayscaleWithOptions(src image.Image, opt PreviewOptions) image.Image {
 	bounds := src.Bounds()
 	gray := image.NewGray(bounds)
 
@@ -608,10 +746,10 @@ func convertToGrayscaleWithDithering(src image.Image) image.Image {
 	}
 
 	// Use BLACK_POINT setting for threshold (0.0 to 1.0, default 0.5)
-	threshold := uint8(env.Value.BlackPoint * 255)
+	threshold := uint8(opt.BlackPoint * 255)
 
 	// Second pass: Apply dithering or simple threshold based on DITHER setting
-	if env.Value.Dither {
+	if opt.Dither {
 		// Apply Floyd-Steinberg dithering for better print quality
 		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
 			for x := bounds.Min.X; x < bounds.Max.X; x++ {
@@ -673,15 +811,14 @@ func clamp(v int) int {
 
 // downloadAndResizeAvatarGray downloads, resizes and converts an avatar image to grayscale
 func downloadAndResizeAvatarGray(url string, size int) (image.Image, error) {
-	// Download image
-	resp, err := http.Get(url)
+	// Download image (タイムアウト・リトライ付き)
+	data, _, err := fetchImageBytes(url)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
 	// Decode image
-	img, _, err := image.Decode(resp.Body)
+	img, _, err := image.Decode(bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
@@ -702,35 +839,27 @@ func GenerateTimeImageWithStats(timeStr string) (image.Image, error) {
 // GenerateTimeImageWithStatsOptions creates a monochrome image with time and Twitch channel statistics with options
 func GenerateTimeImageWithStatsOptions(timeStr string, forceEmptyLeaderboard bool) (image.Image, error) {
 	// Get bits leaders
-	monthLeaders := getBitsLeaders(forceEmptyLeaderboard)
-
-	// Debug output
-	fmt.Printf("=== GenerateTimeImageWithStats Debug ===\n")
-	fmt.Printf("Time: %s\n", timeStr)
-	fmt.Printf("Monthly leaders count: %d\n", len(monthLeaders))
-	fmt.Printf("=====================================\n")
+	monthLeaders, missingScope := getBitsLeaders(forceEmptyLeaderboard)
 
-	// Also use logger to ensure output
-	logger.Info("GenerateTimeImageWithStats Debug",
+	logger.Debug("GenerateTimeImageWithStats",
 		zap.String("time", timeStr),
 		zap.Int("monthlyLeaders", len(monthLeaders)))
 
-	// フォントマネージャーからフォントデータを取得（カスタムフォント必須）
-	fontData, err := fontmanager.GetFont(nil)
+	// フォントマネージャーからパース済みのフォントを取得（カスタムフォント必須）
+	f, err := fontmanager.ParseCurrentFont()
 	if err != nil {
-		logger.Error("Failed to get font", zap.Error(err))
-		return nil, fmt.Errorf("フォントがアップロードされていません。設定ページ(/settings)からフォントファイル(TTF/OTF)をアップロードしてください")
+		return nil, fontError(err)
 	}
 
-	// Load font
-	f, err := opentype.Parse(fontData)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse font: %w", err)
-	}
+	// フォントサイズ（FONT_SIZE に比例、既定 48/36/24/18）
+	timeSize := scaledFontSize(48)
+	statsSize := scaledFontSize(36)
+	smallSize := scaledFontSize(24)
+	xsmallSize := scaledFontSize(18)
 
 	// Large font for time
 	timeFace, err := opentype.NewFace(f, &opentype.FaceOptions{
-		Size:    48,
+		Size:    float64(timeSize),
 		DPI:     72,
 		Hinting: font.HintingFull,
 	})
@@ -741,7 +870,7 @@ func GenerateTimeImageWithStatsOptions(timeStr string, forceEmptyLeaderboard boo
 
 	// Medium font for stats
 	statsFace, err := opentype.NewFace(f, &opentype.FaceOptions{
-		Size:    36,
+		Size:    float64(statsSize),
 		DPI:     72,
 		Hinting: font.HintingFull,
 	})
@@ -752,7 +881,7 @@ func GenerateTimeImageWithStatsOptions(timeStr string, forceEmptyLeaderboard boo
 
 	// Small font for Bits count
 	smallFace, err := opentype.NewFace(f, &opentype.FaceOptions{
-		Size:    24,
+		Size:    float64(smallSize),
 		DPI:     72,
 		Hinting: font.HintingFull,
 	})
@@ -763,7 +892,7 @@ func GenerateTimeImageWithStatsOptions(timeStr string, forceEmptyLeaderboard boo
 
 	// Extra small font for long messages
 	xsmallFace, err := opentype.NewFace(f, &opentype.FaceOptions{
-		Size:    18,
+		Size:    float64(xsmallSize),
 		DPI:     72,
 		Hinting: font.HintingFull,
 	})
@@ -774,32 +903,17 @@ func GenerateTimeImageWithStatsOptions(timeStr string, forceEmptyLeaderboard boo
 
 	// Calculate image height (matching color version)
 	padding := 20
-	lineSpacing := 10
-	baseHeight := padding*2 + 48 + 36 + 10 + 20
+	baseHeight := padding*2 + timeSize + statsSize + 10 + 20
 
 	// Add height for bits leaders
-	extraHeight := 0
-	// Always add height for leaderboard section header
-	// Separator + title
-	extraHeight += 20 + 24 + 10
-
-	if len(monthLeaders) == 0 {
-		// Empty leaderboard - just add space for the message
-		extraHeight += 50 + 36 + 50 + 18 + 25 + 18 + 30 // Space + "まだ誰もいません" + 空行 + "最初のCheerを..." + 間隔 + "さいふ" + margin
-	} else {
-		// Normal leaderboard - show 5 places
-		// First place with avatar
-		extraHeight += 128 + 10 + 36 + 36 + lineSpacing
-		// 2nd-5th place without avatar (smaller font) - always 4 entries
-		for i := 1; i < 5; i++ {
-			extraHeight += 24 + 24 + lineSpacing
-		}
-	}
+	faces := leaderboardFaces{statsFace, smallFace, xsmallFace, statsSize, smallSize, xsmallSize}
+	places := twitchapi.LeaderboardSize()
+	extraHeight := monthlyLeadersHeight(len(monthLeaders), places, faces)
 
 	height := baseHeight + extraHeight
 
 	// Create image with white background
-	img := image.NewRGBA(image.Rect(0, 0, PaperWidth, height))
+	img := image.NewRGBA(image.Rect(0, 0, PaperWidth(), height))
 	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
 
 	// Draw top separator
@@ -816,7 +930,7 @@ func GenerateTimeImageWithStatsOptions(timeStr string, forceEmptyLeaderboard boo
 	drawCenteredText(d, timeStr, padding)
 
 	// Draw date
-	yPos := padding + 48 + 10
+	yPos := padding + timeSize + 10
 	now := time.Now()
 	dateStr := now.Format("2006/01/02")
 	d.Face = statsFace
@@ -826,108 +940,11 @@ func GenerateTimeImageWithStatsOptions(timeStr string, forceEmptyLeaderboard boo
 	yPos = baseHeight - 20
 
 	// Always draw monthly bits leaders section
-	// Draw separator line with margins
-	yPos += 10
-	drawHorizontalLine(img, yPos, 20, 20, 2, color.Black)
-	yPos += 15 // Space after separator
-
-	// Section title
-	d.Face = smallFace
-	titleStr := "今月のトップCheer"
-	drawCenteredText(d, titleStr, yPos)
-	yPos += 24 + 10 // Title height + space
-
-	// Check if no leaders exist
-	if len(monthLeaders) == 0 {
-		// Show gentle message for empty leaderboard
-		yPos += 50 // Add some space
-		d.Face = statsFace
-		d.Src = image.NewUniform(color.Gray{150})
-		drawCenteredText(d, "まだ誰もいません", yPos)
-
-		yPos += 50 // Add empty line
-		d.Face = xsmallFace
-		drawCenteredText(d, "最初のCheerをお待ちしています！", yPos)
-
-		yPos += 25
-		drawCenteredText(d, "収益の一部は「さいふ」に補填されます", yPos)
-	} else {
-		// Draw 5 places (with or without data)
-		for i := 0; i < 5; i++ {
-			if i == 0 {
-				// First place with avatar
-				avatarLocalSize := 128
-				avatarDrawn := false
-
-				if i < len(monthLeaders) && monthLeaders[i].AvatarURL != "" {
-					avatarImg, err := downloadAndResizeAvatarGray(monthLeaders[i].AvatarURL, avatarLocalSize)
-					if err == nil {
-						avatarX := (PaperWidth - avatarLocalSize) / 2
-						draw.Draw(img, image.Rect(avatarX, yPos, avatarX+avatarLocalSize, yPos+avatarLocalSize),
-							avatarImg, image.Point{}, draw.Over)
-						yPos += avatarLocalSize
-						avatarDrawn = true
-					}
-				}
-
-				// Leader name or placeholder
-				d.Face = statsFace
-				if !avatarDrawn {
-					yPos += avatarLocalSize // Add space for missing avatar
-				}
-				yPos += 10
-
-				if i < len(monthLeaders) {
-					d.Src = image.Black
-					drawCenteredText(d, monthLeaders[i].UserName, yPos)
-				} else {
-					d.Src = image.NewUniform(color.Gray{200})
-					drawCenteredText(d, "---", yPos)
-				}
-
-				// Bits count
-				yPos += 36
-				if i < len(monthLeaders) {
-					bitsStr := fmt.Sprintf("%d Bits", monthLeaders[i].Score)
-					d.Src = image.Black
-					drawCenteredText(d, bitsStr, yPos)
-				} else {
-					d.Src = image.NewUniform(color.Gray{200})
-					drawCenteredText(d, "--- Bits", yPos)
-				}
-				yPos += 36 + 10 // Bits height + line spacing
-			} else {
-				// 2nd-5th place
-				d.Face = smallFace
-
-				if i < len(monthLeaders) {
-					d.Src = image.NewUniform(color.Gray{128})
-					placeStr := fmt.Sprintf("%d位 %s", i+1, monthLeaders[i].UserName)
-					drawCenteredText(d, placeStr, yPos)
-				} else {
-					d.Src = image.NewUniform(color.Gray{200})
-					placeStr := fmt.Sprintf("%d位 ---", i+1)
-					drawCenteredText(d, placeStr, yPos)
-				}
-
-				// Bits count
-				yPos += 24
-				if i < len(monthLeaders) {
-					bitsStr := fmt.Sprintf("%d Bits", monthLeaders[i].Score)
-					d.Src = image.NewUniform(color.Gray{128})
-					drawCenteredText(d, bitsStr, yPos)
-				} else {
-					d.Src = image.NewUniform(color.Gray{200})
-					drawCenteredText(d, "--- Bits", yPos)
-				}
-				yPos += 24 + 10 // Bits height + line spacing
-			}
-		}
-	}
+	drawMonthlyLeaders(img, d, yPos, monthLeaders, missingScope, places, faces)
 
 	// Draw bottom separator (dashed)
 	lineY := height - 10
-	for x := 10; x < PaperWidth-10; x += 4 {
+	for x := 10; x < PaperWidth()-10; x += 4 {
 		for y := 0; y < 2; y++ {
 			img.Set(x, lineY+y, color.Black)
 		}
@@ -936,20 +953,25 @@ func GenerateTimeImageWithStatsOptions(timeStr string, forceEmptyLeaderboard boo
 	return img, nil
 }
 
-// getBitsLeaders gets the top bits cheerers for month only
-func getBitsLeaders(forceEmpty bool) (monthLeaders []*twitchapi.BitsLeaderboardEntry) {
+// getBitsLeaders gets the top bits cheerers for month only.
+// missingScope is true when the leaderboard could not be read because the token lacks bits:read
+func getBitsLeaders(forceEmpty bool) (monthLeaders []*twitchapi.BitsLeaderboardEntry, missingScope bool) {
 	// Check if we should return empty leaderboard for testing
 	if forceEmpty {
-		fmt.Printf("Clock: Empty leaderboard test mode enabled\n")
-		return nil
+		logger.Debug("Clock: Empty leaderboard test mode enabled")
+		return nil, false
 	}
 
-	// Get monthly leaders from API
-	monthLeaders, apiResponse, err := twitchapi.GetBitsLeaderboard("month")
+	// Get leaders of the configured period (LEADERBOARD_PERIOD) from API
+	period := twitchapi.LeaderboardPeriod()
+	monthLeaders, apiResponse, err := twitchapi.GetBitsLeaderboard(period)
 	if err != nil {
-		fmt.Printf("Failed to get monthly bits leaders: %v\n", err)
-		monthLeaders = nil
-		return nil
+		logger.Debug("Failed to get bits leaders", zap.String("period", period), zap.Error(err))
+		if errors.Is(err, twitchapi.ErrMissingScope) {
+			warnLeaderboardScope(err)
+			return nil, true
+		}
+		return nil, false
 	}
 
 	// APIレスポンスがある場合は、date_rangeを使って期間を表示
@@ -964,7 +986,7 @@ func getBitsLeaders(forceEmpty bool) (monthLeaders []*twitchapi.BitsLeaderboardE
 				if err != nil {
 					// タイムゾーンのロードに失敗した場合はUTCを使用
 					loc = time.UTC
-					fmt.Printf("Warning: Failed to load timezone %s, using UTC\n", env.Value.TimeZone)
+					logger.Warn("Failed to load timezone, using UTC", zap.String("timezone", env.Value.TimeZone))
 				}
 
 				// 日付をローカルタイムゾーンに変換して表示
@@ -972,13 +994,7 @@ func getBitsLeaders(forceEmpty bool) (monthLeaders []*twitchapi.BitsLeaderboardE
 				endLocal := endedAt.In(loc)
 				tzName, _ := startLocal.Zone()
 
-				fmt.Printf("  期間: %s 〜 %s (%s)\n",
-					startLocal.Format("2006年1月2日 15:04"),
-					endLocal.Format("2006年1月2日 15:04"),
-					tzName)
-
-				// logger にも出力
-				logger.Info("Bits leaderboard date range",
+				logger.Debug("Bits leaderboard date range",
 					zap.String("start", startLocal.Format("2006-01-02 15:04:05")),
 					zap.String("end", endLocal.Format("2006-01-02 15:04:05")),
 					zap.String("timezone", tzName))
@@ -986,29 +1002,50 @@ func getBitsLeaders(forceEmpty bool) (monthLeaders []*twitchapi.BitsLeaderboardE
 		}
 	}
 
-	if len(monthLeaders) == 0 {
-		fmt.Printf("No monthly bits leaders found\n")
-	} else {
-		fmt.Printf("Clock: Fetched %d monthly bits leaders:\n", len(monthLeaders))
-		for i, leader := range monthLeaders {
-			fmt.Printf("  #%d: %s with %d bits (avatar: %v)\n", i+1, leader.UserName, leader.Score, leader.AvatarURL != "")
-		}
+	logger.Debug("Clock: Fetched bits leaders", zap.String("period", period), zap.Int("count", len(monthLeaders)))
+	for i, leader := range monthLeaders {
+		logger.Debug("Clock: Bits leader",
+			zap.Int("rank", i+1),
+			zap.String("user", leader.UserName),
+			zap.Int("score", leader.Score),
+			zap.Bool("has_avatar", leader.AvatarURL != ""))
 	}
 
-	return monthLeaders
+	return monthLeaders, false
+}
+
+// warnLeaderboardScope logs the missing scope and asks the frontend to prompt for re-authorization
+func warnLeaderboardScope(err error) {
+	logger.Warn("Bits leaderboard unavailable: token lacks bits:read scope, please re-authorize", zap.Error(err))
+	broadcast.Send(map[string]interface{}{
+		"type": "auth_warning",
+		"data": map[string]interface{}{
+			"code":     "missing_scope",
+			"scope":    "bits:read",
+			"auth_url": "/auth",
+			"message":  "Bitsランキングの取得に必要な権限(bits:read)がありません。Twitchの再認証を行ってください",
+		},
+	})
+}
+
+// emptyLeaderboardTexts returns the headline and two notes shown when no leaders are drawn
+func emptyLeaderboardTexts(missingScope bool) (headline, note1, note2 string) {
+	if missingScope {
+		return "ランキング取得不可", "権限(bits:read)がありません", "設定画面からTwitchを再認証してください"
+	}
+	return "まだ誰もいません", "最初のCheerをお待ちしています！", "収益の一部は「さいふ」に補填されます"
 }
 
 // downloadAndResizeAvatarColor downloads and resizes an avatar image in color
 func downloadAndResizeAvatarColor(url string, size int) (image.Image, error) {
-	// Download image
-	resp, err := http.Get(url)
+	// Download image (タイムアウト・リトライ付き)
+	data, _, err := fetchImageBytes(url)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
 	// Decode image
-	img, _, err := image.Decode(resp.Body)
+	img, _, err := image.Decode(bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
@@ -1022,22 +1059,15 @@ func downloadAndResizeAvatarColor(url string, size int) (image.Image, error) {
 
 // GenerateTimeImageSimple creates a simple monochrome image with date and time
 func GenerateTimeImageSimple(timeStr string) (image.Image, error) {
-	// フォントマネージャーからフォントデータを取得（カスタムフォント必須）
-	fontData, err := fontmanager.GetFont(nil)
-	if err != nil {
-		logger.Error("Failed to get font", zap.Error(err))
-		return nil, fmt.Errorf("フォントがアップロードされていません。設定ページ(/settings)からフォントファイル(TTF/OTF)をアップロードしてください")
-	}
-
-	// Load font
-	parsedFont, err := opentype.Parse(fontData)
+	// フォントマネージャーからパース済みのフォントを取得（カスタムフォント必須）
+	parsedFont, err := fontmanager.ParseCurrentFont()
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse font: %w", err)
+		return nil, fontError(err)
 	}
 
 	// Create font face for date/time (smaller than stats version)
 	timeFace, err := opentype.NewFace(parsedFont, &opentype.FaceOptions{
-		Size: 60,
+		Size: float64(scaledFontSize(60)),
 		DPI:  72,
 	})
 	if err != nil {
@@ -1045,7 +1075,7 @@ func GenerateTimeImageSimple(timeStr string) (image.Image, error) {
 	}
 
 	// Calculate image height (enough for date and time)
-	img := image.NewGray(image.Rect(0, 0, PaperWidth, 200))
+	img := image.NewGray(image.Rect(0, 0, PaperWidth(), scaledFontSize(200)))
 
 	// Fill with white
 	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
@@ -1066,8 +1096,8 @@ func GenerateTimeImageSimple(timeStr string) (image.Image, error) {
 	bounds, _ := d.BoundString(dateStr)
 	dateWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 	d.Dot = fixed.Point26_6{
-		X: fixed.I((PaperWidth - dateWidth) / 2),
-		Y: fixed.I(60),
+		X: fixed.I((PaperWidth() - dateWidth) / 2),
+		Y: fixed.I(scaledFontSize(60)),
 	}
 	d.DrawString(dateStr)
 
@@ -1075,8 +1105,8 @@ func GenerateTimeImageSimple(timeStr string) (image.Image, error) {
 	bounds, _ = d.BoundString(timeStr)
 	timeWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 	d.Dot = fixed.Point26_6{
-		X: fixed.I((PaperWidth - timeWidth) / 2),
-		Y: fixed.I(130),
+		X: fixed.I((PaperWidth() - timeWidth) / 2),
+		Y: fixed.I(scaledFontSize(130)),
 	}
 	d.DrawString(timeStr)
 
@@ -1091,30 +1121,27 @@ func GenerateTimeImageWithStatsColor(timeStr string) (image.Image, error) {
 // GenerateTimeImageWithStatsColorOptions creates a color image with time and Twitch channel statistics with options
 func GenerateTimeImageWithStatsColorOptions(timeStr string, forceEmptyLeaderboard bool) (image.Image, error) {
 	// Get bits leaders
-	monthLeaders := getBitsLeaders(forceEmptyLeaderboard)
+	monthLeaders, missingScope := getBitsLeaders(forceEmptyLeaderboard)
 
-	// Debug output
-	fmt.Printf("=== GenerateTimeImageWithStatsColor Debug ===\n")
-	fmt.Printf("Time: %s\n", timeStr)
-	fmt.Printf("Monthly leaders count: %d\n", len(monthLeaders))
-	fmt.Printf("==========================================\n")
+	logger.Debug("GenerateTimeImageWithStatsColor",
+		zap.String("time", timeStr),
+		zap.Int("monthlyLeaders", len(monthLeaders)))
 
-	// フォントマネージャーからフォントデータを取得（カスタムフォント必須）
-	fontData, err := fontmanager.GetFont(nil)
+	// フォントマネージャーからパース済みのフォントを取得（カスタムフォント必須）
+	f, err := fontmanager.ParseCurrentFont()
 	if err != nil {
-		logger.Error("Failed to get font", zap.Error(err))
-		return nil, fmt.Errorf("フォントがアップロードされていません。設定ページ(/settings)からフォントファイル(TTF/OTF)をアップロードしてください")
+		return nil, fontError(err)
 	}
 
-	// Load font
-	f, err := opentype.Parse(fontData)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse font: %w", err)
-	}
+	// フォントサイズ（FONT_SIZE に比例、既定 48/36/24/18）
+	timeSize := scaledFontSize(48)
+	statsSize := scaledFontSize(36)
+	smallSize := scaledFontSize(24)
+	xsmallSize := scaledFontSize(18)
 
 	// Large font for time
 	timeFace, err := opentype.NewFace(f, &opentype.FaceOptions{
-		Size:    48,
+		Size:    float64(timeSize),
 		DPI:     72,
 		Hinting: font.HintingFull,
 	})
@@ -1125,7 +1152,7 @@ func GenerateTimeImageWithStatsColorOptions(timeStr string, forceEmptyLeaderboar
 
 	// Medium font for stats
 	statsFace, err := opentype.NewFace(f, &opentype.FaceOptions{
-		Size:    36,
+		Size:    float64(statsSize),
 		DPI:     72,
 		Hinting: font.HintingFull,
 	})
@@ -1137,29 +1164,30 @@ func GenerateTimeImageWithStatsColorOptions(timeStr string, forceEmptyLeaderboar
 	// Calculate image height based on content
 	padding := 20
 	lineSpacing := 10
-	baseHeight := padding*2 + 48 + 36 + 10 + 20
+	baseHeight := padding*2 + timeSize + statsSize + 10 + 20
 
 	// Add height for bits leaders
+	places := twitchapi.LeaderboardSize()
 	extraHeight := 0
 	// Always add height for leaderboard section header
 	// Separator + title
-	extraHeight += 20 + 24 + 10
+	extraHeight += 20 + smallSize + 10
 
 	if len(monthLeaders) == 0 {
 		// Empty leaderboard - just add space for the message
-		extraHeight += 50 + 36 + 50 + 18 + 25 + 18 + 30 // Space + "まだ誰もいません" + 空行 + "最初のCheerを..." + 間隔 + "さいふ" + margin
+		extraHeight += 50 + statsSize + 50 + xsmallSize + (xsmallSize + 7) + xsmallSize + 30 // Space + "まだ誰もいません" + 空行 + "最初のCheerを..." + 間隔 + "さいふ" + margin
 	} else {
-		// Normal leaderboard - show 5 places
+		// Normal leaderboard - show LEADERBOARD_SIZE places
 		// First place with avatar
-		extraHeight += 128 + 10 + 36 + 36 + lineSpacing
-		// 2nd-5th place without avatar (smaller font) - always 4 entries
-		for i := 1; i < 5; i++ {
-			extraHeight += 24 + 24 + lineSpacing
+		extraHeight += 128 + 10 + statsSize + statsSize + lineSpacing
+		// 2nd and later places (smaller font) - always places-1 entries
+		for i := 1; i < places; i++ {
+			extraHeight += smallSize + smallSize + lineSpacing
 		}
 	}
 
 	imgHeight := baseHeight + extraHeight
-	img := image.NewRGBA(image.Rect(0, 0, PaperWidth, imgHeight))
+	img := image.NewRGBA(image.Rect(0, 0, PaperWidth(), imgHeight))
 
 	// Fill with white background
 	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
@@ -1174,7 +1202,7 @@ func GenerateTimeImageWithStatsColorOptions(timeStr string, forceEmptyLeaderboar
 	bounds, _ := d.BoundString(timeStr)
 	timeWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 	d.Dot = fixed.Point26_6{
-		X: fixed.I((PaperWidth - timeWidth) / 2),
+		X: fixed.I((PaperWidth() - timeWidth) / 2),
 		Y: fixed.I(padding) + timeFace.Metrics().Ascent,
 	}
 	d.DrawString(timeStr)
@@ -1186,20 +1214,20 @@ func GenerateTimeImageWithStatsColorOptions(timeStr string, forceEmptyLeaderboar
 	bounds, _ = d.BoundString(dateStr)
 	dateWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 	d.Dot = fixed.Point26_6{
-		X: fixed.I((PaperWidth - dateWidth) / 2),
-		Y: fixed.I(padding+48+10) + statsFace.Metrics().Ascent,
+		X: fixed.I((PaperWidth() - dateWidth) / 2),
+		Y: fixed.I(padding+timeSize+10) + statsFace.Metrics().Ascent,
 	}
 	d.DrawString(dateStr)
 
 	// Always draw bits leaders section
-	yPos := padding + 48 + 10 + 36 + 10 // padding + time + space + date + space
+	yPos := padding + timeSize + 10 + statsSize + 10 // padding + time + space + date + space
 	// Draw separator line in black
 	yPos += 10
 	drawHorizontalLine(img, yPos, 20, 20, 2, color.Black)
 
 	// Small font for leader sections
 	smallFace, err := opentype.NewFace(f, &opentype.FaceOptions{
-		Size:    24,
+		Size:    float64(smallSize),
 		DPI:     72,
 		Hinting: font.HintingFull,
 	})
@@ -1208,7 +1236,7 @@ func GenerateTimeImageWithStatsColorOptions(timeStr string, forceEmptyLeaderboar
 
 		// Extra small font for long messages
 		xsmallFace, err := opentype.NewFace(f, &opentype.FaceOptions{
-			Size:    18,
+			Size:    float64(xsmallSize),
 			DPI:     72,
 			Hinting: font.HintingFull,
 		})
@@ -1220,28 +1248,28 @@ func GenerateTimeImageWithStatsColorOptions(timeStr string, forceEmptyLeaderboar
 
 		// Monthly leaders
 		yPos += 15 // Space after separator
-		titleText := "今月のトップCheer"
+		titleText := leaderboardTitle(twitchapi.LeaderboardPeriod())
 		d.Src = image.Black
 		bounds, _ = d.BoundString(titleText)
 		titleWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 		d.Dot = fixed.Point26_6{
-			X: fixed.I((PaperWidth - titleWidth) / 2),
+			X: fixed.I((PaperWidth() - titleWidth) / 2),
 			Y: fixed.I(yPos) + smallFace.Metrics().Ascent,
 		}
 		d.DrawString(titleText)
-		yPos += 24 + 10 // Title height + space
+		yPos += smallSize + 10 // Title height + space
 
 		// Check if no leaders exist
 		if len(monthLeaders) == 0 {
-			// Show gentle message for empty leaderboard
+			// Show gentle message for empty leaderboard (or why it is unavailable)
+			messageText, waitText, saifuText := emptyLeaderboardTexts(missingScope)
 			yPos += 50 // Add some space
 			d.Face = statsFace
 			d.Src = image.NewUniform(color.RGBA{150, 150, 150, 255})
-			messageText := "まだ誰もいません"
 			bounds, _ = d.BoundString(messageText)
 			messageWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 			d.Dot = fixed.Point26_6{
-				X: fixed.I((PaperWidth - messageWidth) / 2),
+				X: fixed.I((PaperWidth() - messageWidth) / 2),
 				Y: fixed.I(yPos) + statsFace.Metrics().Ascent,
 			}
 			d.DrawString(messageText)
@@ -1252,27 +1280,25 @@ func GenerateTimeImageWithStatsColorOptions(timeStr string, forceEmptyLeaderboar
 			} else {
 				d.Face = smallFace
 			}
-			waitText := "最初のCheerをお待ちしています！"
 			bounds, _ = d.BoundString(waitText)
 			waitWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 			d.Dot = fixed.Point26_6{
-				X: fixed.I((PaperWidth - waitWidth) / 2),
+				X: fixed.I((PaperWidth() - waitWidth) / 2),
 				Y: fixed.I(yPos) + d.Face.Metrics().Ascent,
 			}
 			d.DrawString(waitText)
 
-			yPos += 25
-			saifuText := "収益の一部は「さいふ」に補填されます"
+			yPos += xsmallSize + 7
 			bounds, _ = d.BoundString(saifuText)
 			saifuWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 			d.Dot = fixed.Point26_6{
-				X: fixed.I((PaperWidth - saifuWidth) / 2),
+				X: fixed.I((PaperWidth() - saifuWidth) / 2),
 				Y: fixed.I(yPos) + d.Face.Metrics().Ascent,
 			}
 			d.DrawString(saifuText)
 		} else {
-			// Draw 5 places (with or without data)
-			for i := 0; i < 5; i++ {
+			// Draw LEADERBOARD_SIZE places (with or without data)
+			for i := 0; i < places; i++ {
 
 				if i == 0 {
 					// First place - with avatar and larger font
@@ -1282,7 +1308,7 @@ func GenerateTimeImageWithStatsColorOptions(timeStr string, forceEmptyLeaderboar
 					if i < len(monthLeaders) && monthLeaders[i].AvatarURL != "" {
 						avatarImg, err := downloadAndResizeAvatarColor(monthLeaders[i].AvatarURL, avatarSize)
 						if err == nil {
-							avatarX := (PaperWidth - avatarSize) / 2
+							avatarX := (PaperWidth() - avatarSize) / 2
 							draw.Draw(img, image.Rect(avatarX, yPos, avatarX+avatarSize, yPos+avatarSize),
 								avatarImg, image.Point{}, draw.Over)
 							yPos += avatarSize
@@ -1303,7 +1329,7 @@ func GenerateTimeImageWithStatsColorOptions(timeStr string, forceEmptyLeaderboar
 						bounds, _ = d.BoundString(leaderText)
 						leaderWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 						d.Dot = fixed.Point26_6{
-							X: fixed.I((PaperWidth - leaderWidth) / 2),
+							X: fixed.I((PaperWidth() - leaderWidth) / 2),
 							Y: fixed.I(yPos) + statsFace.Metrics().Ascent,
 						}
 						d.DrawString(leaderText)
@@ -1313,21 +1339,21 @@ func GenerateTimeImageWithStatsColorOptions(timeStr string, forceEmptyLeaderboar
 						bounds, _ = d.BoundString(leaderText)
 						leaderWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 						d.Dot = fixed.Point26_6{
-							X: fixed.I((PaperWidth - leaderWidth) / 2),
+							X: fixed.I((PaperWidth() - leaderWidth) / 2),
 							Y: fixed.I(yPos) + statsFace.Metrics().Ascent,
 						}
 						d.DrawString(leaderText)
 					}
 
 					// Bits count
-					yPos += 36
+					yPos += statsSize
 					if i < len(monthLeaders) {
-						bitsText := fmt.Sprintf("%d Bits", monthLeaders[i].Score)
+						bitsText := numfmt.Int(monthLeaders[i].Score, env.Value.Locale) + " Bits"
 						d.Src = image.Black
 						bounds, _ = d.BoundString(bitsText)
 						bitsWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 						d.Dot = fixed.Point26_6{
-							X: fixed.I((PaperWidth - bitsWidth) / 2),
+							X: fixed.I((PaperWidth() - bitsWidth) / 2),
 							Y: fixed.I(yPos) + statsFace.Metrics().Ascent,
 						}
 						d.DrawString(bitsText)
@@ -1337,23 +1363,31 @@ func GenerateTimeImageWithStatsColorOptions(timeStr string, forceEmptyLeaderboar
 						bounds, _ = d.BoundString(bitsText)
 						bitsWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 						d.Dot = fixed.Point26_6{
-							X: fixed.I((PaperWidth - bitsWidth) / 2),
+							X: fixed.I((PaperWidth() - bitsWidth) / 2),
 							Y: fixed.I(yPos) + statsFace.Metrics().Ascent,
 						}
 						d.DrawString(bitsText)
 					}
-					yPos += 36 + lineSpacing
+					yPos += statsSize + lineSpacing
 				} else {
-					// 2nd-5th place - smaller font, no avatar
+					// 2nd and later places - smaller font, small avatar on the left
 					d.Face = smallFace
 
+					if i < len(monthLeaders) && monthLeaders[i].AvatarURL != "" {
+						placeAvatarSize := smallSize * 2
+						if avatarImg, err := downloadAndResizeAvatarColor(monthLeaders[i].AvatarURL, placeAvatarSize); err == nil {
+							draw.Draw(img, image.Rect(10, yPos, 10+placeAvatarSize, yPos+placeAvatarSize),
+								avatarImg, image.Point{}, draw.Over)
+						}
+					}
+
 					if i < len(monthLeaders) {
 						d.Src = image.NewUniform(color.RGBA{100, 100, 100, 255})
 						placeText := fmt.Sprintf("%d位 %s", i+1, monthLeaders[i].UserName)
 						bounds, _ = d.BoundString(placeText)
 						placeWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 						d.Dot = fixed.Point26_6{
-							X: fixed.I((PaperWidth - placeWidth) / 2),
+							X: fixed.I((PaperWidth() - placeWidth) / 2),
 							Y: fixed.I(yPos) + smallFace.Metrics().Ascent,
 						}
 						d.DrawString(placeText)
@@ -1363,21 +1397,21 @@ func GenerateTimeImageWithStatsColorOptions(timeStr string, forceEmptyLeaderboar
 						bounds, _ = d.BoundString(placeText)
 						placeWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 						d.Dot = fixed.Point26_6{
-							X: fixed.I((PaperWidth - placeWidth) / 2),
+							X: fixed.I((PaperWidth() - placeWidth) / 2),
 							Y: fixed.I(yPos) + smallFace.Metrics().Ascent,
 						}
 						d.DrawString(placeText)
 					}
 
 					// Bits count
-					yPos += 24
+					yPos += smallSize
 					if i < len(monthLeaders) {
-						bitsText := fmt.Sprintf("%d Bits", monthLeaders[i].Score)
+						bitsText := numfmt.Int(monthLeaders[i].Score, env.Value.Locale) + " Bits"
 						d.Src = image.NewUniform(color.RGBA{100, 100, 100, 255})
 						bounds, _ = d.BoundString(bitsText)
 						bitsWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 						d.Dot = fixed.Point26_6{
-							X: fixed.I((PaperWidth - bitsWidth) / 2),
+							X: fixed.I((PaperWidth() - bitsWidth) / 2),
 							Y: fixed.I(yPos) + smallFace.Metrics().Ascent,
 						}
 						d.DrawString(bitsText)
@@ -1387,12 +1421,12 @@ func GenerateTimeImageWithStatsColorOptions(timeStr string, forceEmptyLeaderboar
 						bounds, _ = d.BoundString(bitsText)
 						bitsWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 						d.Dot = fixed.Point26_6{
-							X: fixed.I((PaperWidth - bitsWidth) / 2),
+							X: fixed.I((PaperWidth() - bitsWidth) / 2),
 							Y: fixed.I(yPos) + smallFace.Metrics().Ascent,
 						}
 						d.DrawString(bitsText)
 					}
-					yPos += 24 + lineSpacing
+					yPos += smallSize + lineSpacing
 				}
 			}
 		}
@@ -1400,7 +1434,7 @@ func GenerateTimeImageWithStatsColorOptions(timeStr string, forceEmptyLeaderboar
 
 	// Draw decorative line
 	lineY := imgHeight - 10
-	for x := 10; x < PaperWidth-10; x += 4 {
+	for x := 10; x < PaperWidth()-10; x += 4 {
 		for y := 0; y < 2; y++ {
 			img.Set(x, lineY+y, color.Black)
 		}
@@ -1428,39 +1462,92 @@ func GeneratePreviewImage(userName string, msg []twitch.ChatMessageFragment) (st
 	return "data:image/png;base64," + encoded, nil
 }
 
+// PreviewOptions are the print settings that affect how a fax is rendered and binarized
+type PreviewOptions struct {
+	Dither     bool
+	BlackPoint float32
+	FontSize   int
+}
+
+// currentPreviewOptions returns the options from the current settings
+func currentPreviewOptions() PreviewOptions {
+	return PreviewOptions{
+		Dither:     env.Value.Dither,
+		BlackPoint: env.Value.BlackPoint,
+		FontSize:   baseFontSize(),
+	}
+}
+
+// GeneratePreviewDiff renders the same message with the current settings (before)
+// and with the proposed options (after) without persisting anything
+func GeneratePreviewDiff(userName string, msg []twitch.ChatMessageFragment, after PreviewOptions) (beforeURL, afterURL string, err error) {
+	before := currentPreviewOptions()
+	if after.FontSize < minFontSize || after.FontSize > maxFontSize {
+		after.FontSize = before.FontSize
+	}
+
+	// フォントサイズが同じならカラー画像は1回だけ生成し、2値化の設定だけを変えて比較する
+	img, err := messageToImage(userName, msg, true, before.FontSize)
+	if err != nil {
+		return "", "", err
+	}
+	afterImg := img
+	if after.FontSize != before.FontSize {
+		if afterImg, err = messageToImage(userName, msg, true, after.FontSize); err != nil {
+			return "", "", err
+		}
+	}
+
+	beforeURL, err = encodeDataURL(convertToGrayscaleWithOptions(img, before))
+	if err != nil {
+		return "", "", err
+	}
+	afterURL, err = encodeDataURL(convertToGrayscaleWithOptions(afterImg, after))
+	if err != nil {
+		return "", "", err
+	}
+	return beforeURL, afterURL, nil
+}
+
+// encodeDataURL encodes img as a PNG data URL
+func encodeDataURL(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
 // wrapText wraps a single text string to fit within maxWidth
-func wrapText(text string, face font.Face, maxWidth int) []string {
+func wrapText(text string, widths *runeWidthCache, maxWidth int) []string {
 	if text == "" {
 		return []string{}
 	}
 
-	d := &font.Drawer{Face: face}
-
-	// まず全体の幅をチェック
-	bounds, _ := d.BoundString(text)
-	textWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
-
-	// 幅に収まる場合はそのまま返す
-	if textWidth <= maxWidth {
+	// まず全体の幅をチェックし、幅に収まる場合はそのまま返す
+	if widths.measure(text) <= maxWidth {
 		return []string{text}
 	}
 
-	// 文字単位で分割して折り返し（実際の文字列幅で判定）
+	// 書記素クラスタ単位で分割して折り返し（キャッシュした文字幅を積算して判定）
+	// 結合文字やZWJシーケンスはクラスタごと同じ行に残る
 	var lines []string
 	var currentLine string
+	var lineWidth fixed.Int26_6
+	limit := fixed.I(maxWidth)
 
-	for _, r := range text {
-		testLine := currentLine + string(r)
-		bounds, _ := d.BoundString(testLine)
-		lineWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
+	for _, g := range graphemes(text) {
+		w := widths.advanceString(g)
 
-		if lineWidth > maxWidth && currentLine != "" {
+		if lineWidth+w > limit && currentLine != "" {
 			// 現在の行を確定して新しい行を開始
 			lines = append(lines, currentLine)
-			currentLine = string(r)
+			currentLine = g
+			lineWidth = w
 		} else {
 			// 文字を現在の行に追加
-			currentLine = testLine
+			currentLine += g
+			lineWidth += w
 		}
 	}
 
@@ -1474,22 +1561,15 @@ func wrapText(text string, face font.Face, maxWidth int) []string {
 
 // MessageToImageWithTitle creates an image with title and details layout
 func MessageToImageWithTitle(title, userName, extra, details string, useColor bool) (image.Image, error) {
-	// フォントマネージャーからフォントデータを取得（カスタムフォント必須）
-	fontData, err := fontmanager.GetFont(nil)
+	// フォントマネージャーからパース済みのフォントを取得（カスタムフォント必須）
+	f, err := fontmanager.ParseCurrentFont()
 	if err != nil {
-		logger.Error("Failed to get font", zap.Error(err))
-		return nil, fmt.Errorf("フォントがアップロードされていません。設定ページ(/settings)からフォントファイル(TTF/OTF)をアップロードしてください")
+		return nil, fontError(err)
 	}
 
-	// フォントを作成
-	f, err := opentype.Parse(fontData)
-	if err != nil {
-		return nil, err
-	}
-
-	// 統一フォント（32px）
+	// 統一フォント（FONT_SIZE、既定32px）
 	face, err := opentype.NewFace(f, &opentype.FaceOptions{
-		Size:    32,
+		Size:    float64(baseFontSize()),
 		DPI:     72,
 		Hinting: font.HintingFull,
 	})
@@ -1503,21 +1583,26 @@ func MessageToImageWithTitle(title, userName, extra, details string, useColor bo
 	lineHeight := int(face.Metrics().Height >> 6)
 	spacing := 15
 
+	// テーマの枠の内側の幅で描画する（後から縮小すると文字がぼやける）
+	theme := currentFaxTheme()
+	width := faxContentWidth(theme)
+
 	// 各テキストを改行処理（余裕を持たせて幅を少し小さくする）
-	textWidth := PaperWidth - 20
+	textWidth := width - 20
+	widths := newRuneWidthCache(face)
 	var titleLines, userLines, extraLines, detailLines []string
 
 	if title != "" {
-		titleLines = wrapText(title, face, textWidth)
+		titleLines = wrapText(title, widths, textWidth)
 	}
 	if userName != "" {
-		userLines = wrapText(userName, face, textWidth)
+		userLines = wrapText(userName, widths, textWidth)
 	}
 	if extra != "" {
-		extraLines = wrapText(extra, face, textWidth)
+		extraLines = wrapText(extra, widths, textWidth)
 	}
 	if details != "" {
-		detailLines = wrapText(details, face, textWidth)
+		detailLines = wrapText(details, widths, textWidth)
 	}
 
 	// 動的な高さ計算
@@ -1548,7 +1633,7 @@ func MessageToImageWithTitle(title, userName, extra, details string, useColor bo
 		}
 		imgHeight += len(detailLines) * lineHeight
 	}
-	imgHeight += UnderlineMargin + UnderlineHeight + 20 // 下端の余白
+	imgHeight += underlineSpace() + 20 // 下端の余白
 
 	// 背景色を決定
 	var bgColor color.Color
@@ -1559,7 +1644,7 @@ func MessageToImageWithTitle(title, userName, extra, details string, useColor bo
 	}
 
 	// 画像を作成
-	img := image.NewRGBA(image.Rect(0, 0, PaperWidth, imgHeight))
+	img := image.NewRGBA(image.Rect(0, 0, width, imgHeight))
 	draw.Draw(img, img.Bounds(), &image.Uniform{bgColor}, image.Point{}, draw.Src)
 
 	// ドロワーを作成
@@ -1577,7 +1662,7 @@ func MessageToImageWithTitle(title, userName, extra, details string, useColor bo
 		lineWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 
 		d.Dot = fixed.Point26_6{
-			X: fixed.I((PaperWidth - lineWidth) / 2),
+			X: fixed.I((width - lineWidth) / 2),
 			Y: fixed.I(yPos) + face.Metrics().Ascent,
 		}
 		d.DrawString(line)
@@ -1593,7 +1678,7 @@ func MessageToImageWithTitle(title, userName, extra, details string, useColor bo
 		lineWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 
 		d.Dot = fixed.Point26_6{
-			X: fixed.I((PaperWidth - lineWidth) / 2),
+			X: fixed.I((width - lineWidth) / 2),
 			Y: fixed.I(yPos) + face.Metrics().Ascent,
 		}
 		d.DrawString(line)
@@ -1609,7 +1694,7 @@ func MessageToImageWithTitle(title, userName, extra, details string, useColor bo
 		lineWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 
 		d.Dot = fixed.Point26_6{
-			X: fixed.I((PaperWidth - lineWidth) / 2),
+			X: fixed.I((width - lineWidth) / 2),
 			Y: fixed.I(yPos) + face.Metrics().Ascent,
 		}
 		d.DrawString(line)
@@ -1625,7 +1710,7 @@ func MessageToImageWithTitle(title, userName, extra, details string, useColor bo
 		lineWidth := bounds.Max.X.Round() - bounds.Min.X.Round()
 
 		d.Dot = fixed.Point26_6{
-			X: fixed.I((PaperWidth - lineWidth) / 2),
+			X: fixed.I((width - lineWidth) / 2),
 			Y: fixed.I(yPos) + face.Metrics().Ascent,
 		}
 		d.DrawString(line)
@@ -1633,26 +1718,7 @@ func MessageToImageWithTitle(title, userName, extra, details string, useColor bo
 	}
 
 	// 下端の線を描画
-	underlineY := imgHeight - UnderlineHeight - 10
-	if UnderlineDashed {
-		for x0 := 0; x0 < PaperWidth; x0 += UnderlineDashLength + UnderlineDashGap {
-			end := x0 + UnderlineDashLength
-			if end > PaperWidth {
-				end = PaperWidth
-			}
-			for y := 0; y < UnderlineHeight; y++ {
-				for x := x0; x < end; x++ {
-					img.Set(x, underlineY+y, color.Black)
-				}
-			}
-		}
-	} else {
-		for y := 0; y < UnderlineHeight; y++ {
-			for x := 0; x < PaperWidth; x++ {
-				img.Set(x, underlineY+y, color.Black)
-			}
-		}
-	}
+	drawUnderline(img, imgHeight-underlineHeight()-10)
 
-	return img, nil
+	return applyFaxTheme(img, theme), nil
 }
@@ -0,0 +1,230 @@
+package output
+
+import (
+	"image"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// testGlyphWidth は fixedWidthFace の1文字の幅（px）
+const testGlyphWidth = 10
+
+// fixedWidthFace is a font.Face where every rune is testGlyphWidth wide
+// (結合文字・ZWJ・異体字セレクタは実際のフォントと同じく幅0)
+type fixedWidthFace struct{}
+
+func (fixedWidthFace) Close() error { return nil }
+
+func (f fixedWidthFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	advance, ok := f.GlyphAdvance(r)
+	return image.Rectangle{}, nil, image.Point{}, advance, ok
+}
+
+func (f fixedWidthFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	advance, ok := f.GlyphAdvance(r)
+	return fixed.Rectangle26_6{}, advance, ok
+}
+
+func (fixedWidthFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Variation_Selector) || r == '\u200d' {
+		return 0, true
+	}
+	return fixed.I(testGlyphWidth), true
+}
+
+func (fixedWidthFace) Kern(r0, r1 rune) fixed.Int26_6 { return 0 }
+
+func (fixedWidthFace) Metrics() font.Metrics {
+	return font.Metrics{Height: fixed.I(12), Ascent: fixed.I(10), Descent: fixed.I(2)}
+}
+
+func newTestWidths() *runeWidthCache {
+	return newRuneWidthCache(fixedWidthFace{})
+}
+
+// fragmentLineTexts flattens wrapped fragment lines into their texts (Emote は "[emote]")
+func fragmentLineTexts(lines [][]twitch.ChatMessageFragment) []string {
+	texts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		var b strings.Builder
+		for _, f := range line {
+			if f.Emote != nil {
+				b.WriteString("[emote]")
+				continue
+			}
+			b.WriteString(f.Text)
+		}
+		texts = append(texts, b.String())
+	}
+	return texts
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxWidth int
+		want     []string
+	}{
+		{"empty", "", 50, []string{}},
+		{"fits", "abc", 50, []string{"abc"}},
+		{"exactly max width", "abcde", 50, []string{"abcde"}},
+		{"one character over", "abcdef", 50, []string{"abcde", "f"}},
+		{"long single word", "abcdefghijkl", 50, []string{"abcde", "fghij", "kl"}},
+		{"spaces are kept", "ab cd ef", 50, []string{"ab cd", " ef"}},
+		{"CJK", "あいうえおかきくけこさ", 50, []string{"あいうえお", "かきくけこ", "さ"}},
+		{"narrower than one character", "abc", 5, []string{"a", "b", "c"}},
+	}
+
+	widths := newTestWidths()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapText(tt.text, widths, tt.maxWidth)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrapText(%q, %d) = %q, want %q", tt.text, tt.maxWidth, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapFragments(t *testing.T) {
+	emote := twitch.ChatMessageFragment{Type: "emote", Text: "Kappa", Emote: &twitch.ChatMessageFragmentEmote{Id: "25"}}
+	text := func(s string) twitch.ChatMessageFragment {
+		return twitch.ChatMessageFragment{Type: "text", Text: s}
+	}
+
+	tests := []struct {
+		name  string
+		frags []twitch.ChatMessageFragment
+		want  []string
+	}{
+		{"empty", nil, []string{}},
+		{"fits", []twitch.ChatMessageFragment{text("hello")}, []string{"hello"}},
+		{"long single word", []twitch.ChatMessageFragment{text("abcdefghijkl")}, []string{"abcde", "fghij", "kl"}},
+		{"newlines are removed", []twitch.ChatMessageFragment{text("ab\ncd\nef")}, []string{"abcde", "f"}},
+		{"emote takes a line height", []twitch.ChatMessageFragment{text("abcd"), emote, text("e")}, []string{"abcd", "[emote]e"}},
+		{"text across fragments", []twitch.ChatMessageFragment{text("abc"), text("defg")}, []string{"abcde", "fg"}},
+		{"URL on its own line", []twitch.ChatMessageFragment{text("ab"), text("https://example.com/" + strings.Repeat("x", 20)), text("cd")},
+			[]string{"ab", "https://example.com/" + strings.Repeat("x", 20), "cd"}},
+	}
+
+	widths := newTestWidths()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fragmentLineTexts(wrapFragments(tt.frags, widths, 50, 12))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrapFragments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapLongTokenFitsPaper(t *testing.T) {
+	token := strings.Repeat("abcdefghij", 20) // スペースのない200文字
+	widths := newTestWidths()
+	maxWidth := PaperWidth()
+
+	check := func(t *testing.T, lines []string) {
+		t.Helper()
+		if len(lines) < 2 {
+			t.Fatalf("got %d line(s), want the token to be broken", len(lines))
+		}
+		for i, line := range lines {
+			if w := widths.measure(line); w > maxWidth {
+				t.Errorf("line %d is %dpx wide, exceeds paper width %dpx", i, w, maxWidth)
+			}
+		}
+		if joined := strings.Join(lines, ""); joined != token {
+			t.Errorf("lines joined = %q, want the original token", joined)
+		}
+	}
+
+	t.Run("wrapText", func(t *testing.T) {
+		check(t, wrapText(token, widths, maxWidth))
+	})
+	t.Run("wrapFragments", func(t *testing.T) {
+		frags := []twitch.ChatMessageFragment{{Type: "text", Text: token}}
+		check(t, fragmentLineTexts(wrapFragments(frags, widths, maxWidth, 12)))
+	})
+}
+
+func TestGraphemes(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"Thai", "สวัสดี", []string{"ส", "วั", "ส", "ดี"}},
+		{"Devanagari", "हिंदी", []string{"हिं", "दी"}},
+		{"flags", "🇯🇵🇺🇸", []string{"🇯🇵", "🇺🇸"}},
+		{"ZWJ family", "👨‍👩‍👧a", []string{"👨‍👩‍👧", "a"}},
+		{"combining mark", "\u304b\u3099", []string{"\u304b\u3099"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := graphemes(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("graphemes(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+// assertClustersKept fails if a line break falls inside a grapheme cluster of text
+func assertClustersKept(t *testing.T, text string, lines []string) {
+	t.Helper()
+	if joined := strings.Join(lines, ""); joined != text {
+		t.Fatalf("lines joined = %q, want %q", joined, text)
+	}
+	boundaries := map[int]bool{0: true}
+	offset := 0
+	for _, g := range graphemes(text) {
+		offset += len(g)
+		boundaries[offset] = true
+	}
+	offset = 0
+	for i, line := range lines {
+		offset += len(line)
+		if !boundaries[offset] {
+			t.Errorf("line %d (%q) ends inside a grapheme cluster", i, line)
+		}
+	}
+}
+
+func TestWrapKeepsGraphemeClusters(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"Thai", strings.Repeat("สวัสดีครับ", 5)},
+		{"Devanagari", strings.Repeat("नमस्ते हिंदी ", 5)},
+		{"flags", strings.Repeat("🇯🇵🇺🇸🇫🇷", 5)},
+		{"ZWJ family", strings.Repeat("👨‍👩‍👧‍👦", 5)},
+	}
+
+	widths := newTestWidths()
+	// 旗（地域指示子2文字）やZWJ絵文字が途中で切れうる幅で折り返す
+	const maxWidth = 3 * testGlyphWidth
+	for _, tt := range tests {
+		t.Run(tt.name+"/wrapText", func(t *testing.T) {
+			lines := wrapText(tt.text, widths, maxWidth)
+			if len(lines) < 2 {
+				t.Fatalf("got %d line(s), want the text to be wrapped", len(lines))
+			}
+			assertClustersKept(t, tt.text, lines)
+		})
+		t.Run(tt.name+"/wrapFragments", func(t *testing.T) {
+			frags := []twitch.ChatMessageFragment{{Type: "text", Text: tt.text}}
+			lines := fragmentLineTexts(wrapFragments(frags, widths, maxWidth, 12))
+			if len(lines) < 2 {
+				t.Fatalf("got %d line(s), want the text to be wrapped", len(lines))
+			}
+			assertClustersKept(t, tt.text, lines)
+		})
+	}
+}
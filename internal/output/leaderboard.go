@@ -0,0 +1,258 @@
+package output
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/fontmanager"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/numfmt"
+	"github.com/nantokaworks/twitch-overlay/internal/twitchapi"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+)
+
+// leaderboardFaces are the font faces used by the monthly Cheer leaderboard section
+type leaderboardFaces struct {
+	stats, small, xsmall             font.Face
+	statsSize, smallSize, xsmallSize int
+}
+
+// leaderboardPeriodLabel returns the Japanese label of a LEADERBOARD_PERIOD (今月 など)
+func leaderboardPeriodLabel(period string) string {
+	switch period {
+	case "day":
+		return "今日"
+	case "week":
+		return "今週"
+	case "year":
+		return "今年"
+	case "all":
+		return "歴代"
+	}
+	return "今月"
+}
+
+// leaderboardTitle returns the section title for the Cheer leaderboard of the given period
+func leaderboardTitle(period string) string {
+	if period == "all" {
+		return "歴代トップCheer"
+	}
+	return leaderboardPeriodLabel(period) + "のトップCheer"
+}
+
+// leaderboardHeader returns the heading of the standalone leaderboard print (期間がわかる日付).
+// API に指定する期間と同じく PST の暦で区切る
+func leaderboardHeader(period string, now time.Time) string {
+	start, ok := twitchapi.LeaderboardPeriodStart(period, now)
+	if !ok {
+		return "歴代ランキング"
+	}
+	switch period {
+	case "day":
+		return start.Format("2006年1月2日")
+	case "week":
+		return start.Format("1月2日") + "からの週"
+	case "year":
+		return start.Format("2006年")
+	}
+	return start.Format("2006年1月")
+}
+
+// monthlyLeadersHeight returns the height of the leaderboard section drawn by drawMonthlyLeaders (places 位まで)
+func monthlyLeadersHeight(leaders, places int, f leaderboardFaces) int {
+	lineSpacing := 10
+	extraHeight := 0
+	// Always add height for leaderboard section header
+	// Separator + title
+	extraHeight += 20 + f.smallSize + 10
+
+	if leaders == 0 {
+		// Empty leaderboard - just add space for the message
+		extraHeight += 50 + f.statsSize + 50 + f.xsmallSize + (f.xsmallSize + 7) + f.xsmallSize + 30 // Space + "まだ誰もいません" + 空行 + "最初のCheerを..." + 間隔 + "さいふ" + margin
+	} else {
+		// Normal leaderboard - show LEADERBOARD_SIZE places
+		// First place with avatar
+		extraHeight += 128 + 10 + f.statsSize + f.statsSize + lineSpacing
+		// 2nd and later places (smaller font) - always places-1 entries
+		for i := 1; i < places; i++ {
+			extraHeight += f.smallSize + f.smallSize + lineSpacing
+		}
+	}
+	return extraHeight
+}
+
+// drawMonthlyLeaders draws the separator, title and top 5 Cheer leaders (monochrome) starting at yPos.
+// 時計とリーダーボード単体の印刷で共通。描画後の y 座標を返す
+func drawMonthlyLeaders(img *image.RGBA, d *font.Drawer, yPos int, monthLeaders []*twitchapi.BitsLeaderboardEntry, missingScope bool, places int, f leaderboardFaces) int {
+	// Draw separator line with margins
+	yPos += 10
+	drawHorizontalLine(img, yPos, 20, 20, 2, color.Black)
+	yPos += 15 // Space after separator
+
+	// Section title
+	d.Face = f.small
+	titleStr := leaderboardTitle(twitchapi.LeaderboardPeriod())
+	drawCenteredText(d, titleStr, yPos)
+	yPos += f.smallSize + 10 // Title height + space
+
+	// Check if no leaders exist
+	if len(monthLeaders) == 0 {
+		// Show gentle message for empty leaderboard (or why it is unavailable)
+		headline, note1, note2 := emptyLeaderboardTexts(missingScope)
+		yPos += 50 // Add some space
+		d.Face = f.stats
+		d.Src = image.NewUniform(color.Gray{150})
+		drawCenteredText(d, headline, yPos)
+
+		yPos += 50 // Add empty line
+		d.Face = f.xsmall
+		drawCenteredText(d, note1, yPos)
+
+		yPos += f.xsmallSize + 7
+		drawCenteredText(d, note2, yPos)
+	} else {
+		// Draw LEADERBOARD_SIZE places (with or without data)
+		for i := 0; i < places; i++ {
+			if i == 0 {
+				// First place with avatar
+				avatarLocalSize := 128
+				avatarDrawn := false
+
+				if i < len(monthLeaders) && monthLeaders[i].AvatarURL != "" {
+					avatarImg, err := downloadAndResizeAvatarGray(monthLeaders[i].AvatarURL, avatarLocalSize)
+					if err == nil {
+						avatarX := (PaperWidth() - avatarLocalSize) / 2
+						draw.Draw(img, image.Rect(avatarX, yPos, avatarX+avatarLocalSize, yPos+avatarLocalSize),
+							avatarImg, image.Point{}, draw.Over)
+						yPos += avatarLocalSize
+						avatarDrawn = true
+					}
+				}
+
+				// Leader name or placeholder
+				d.Face = f.stats
+				if !avatarDrawn {
+					yPos += avatarLocalSize // Add space for missing avatar
+				}
+				yPos += 10
+
+				if i < len(monthLeaders) {
+					d.Src = image.Black
+					drawCenteredText(d, monthLeaders[i].UserName, yPos)
+				} else {
+					d.Src = image.NewUniform(color.Gray{200})
+					drawCenteredText(d, "---", yPos)
+				}
+
+				// Bits count
+				yPos += f.statsSize
+				if i < len(monthLeaders) {
+					bitsStr := numfmt.Int(monthLeaders[i].Score, env.Value.Locale) + " Bits"
+					d.Src = image.Black
+					drawCenteredText(d, bitsStr, yPos)
+				} else {
+					d.Src = image.NewUniform(color.Gray{200})
+					drawCenteredText(d, "--- Bits", yPos)
+				}
+				yPos += f.statsSize + 10 // Bits height + line spacing
+			} else {
+				// 2nd and later places
+				d.Face = f.small
+
+				// 名前とBitsの2行分の高さの小さいアバターを左端に描画
+				if i < len(monthLeaders) && monthLeaders[i].AvatarURL != "" {
+					placeAvatarSize := f.smallSize * 2
+					if avatarImg, err := downloadAndResizeAvatarGray(monthLeaders[i].AvatarURL, placeAvatarSize); err == nil {
+						draw.Draw(img, image.Rect(10, yPos, 10+placeAvatarSize, yPos+placeAvatarSize),
+							avatarImg, image.Point{}, draw.Over)
+					}
+				}
+
+				if i < len(monthLeaders) {
+					d.Src = image.NewUniform(color.Gray{128})
+					placeStr := fmt.Sprintf("%d位 %s", i+1, monthLeaders[i].UserName)
+					drawCenteredText(d, placeStr, yPos)
+				} else {
+					d.Src = image.NewUniform(color.Gray{200})
+					placeStr := fmt.Sprintf("%d位 ---", i+1)
+					drawCenteredText(d, placeStr, yPos)
+				}
+
+				// Bits count
+				yPos += f.smallSize
+				if i < len(monthLeaders) {
+					bitsStr := numfmt.Int(monthLeaders[i].Score, env.Value.Locale) + " Bits"
+					d.Src = image.NewUniform(color.Gray{128})
+					drawCenteredText(d, bitsStr, yPos)
+				} else {
+					d.Src = image.NewUniform(color.Gray{200})
+					drawCenteredText(d, "--- Bits", yPos)
+				}
+				yPos += f.smallSize + 10 // Bits height + line spacing
+			}
+		}
+	}
+	return yPos
+}
+
+// GenerateLeaderboardImage renders the monthly Cheer leaderboard on its own (月末のまとめ印刷用).
+// forceEmpty は時計のデバッグと同じく、空のリーダーボードの表示を確認するためのもの
+func GenerateLeaderboardImage(forceEmpty bool) (image.Image, error) {
+	monthLeaders, missingScope := getBitsLeaders(forceEmpty)
+
+	f, err := fontmanager.ParseCurrentFont()
+	if err != nil {
+		return nil, fontError(err)
+	}
+
+	statsSize := scaledFontSize(36)
+	smallSize := scaledFontSize(24)
+	xsmallSize := scaledFontSize(18)
+	var faceList []font.Face
+	for _, size := range []int{statsSize, smallSize, xsmallSize} {
+		face, err := opentype.NewFace(f, &opentype.FaceOptions{
+			Size:    float64(size),
+			DPI:     72,
+			Hinting: font.HintingFull,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create font face: %w", err)
+		}
+		defer face.Close()
+		faceList = append(faceList, face)
+	}
+	faces := leaderboardFaces{faceList[0], faceList[1], faceList[2], statsSize, smallSize, xsmallSize}
+
+	// 見出し（期間）+ リーダーボード + 下の点線
+	padding := 20
+	headerHeight := padding*2 + statsSize
+	places := twitchapi.LeaderboardSize()
+	height := headerHeight + monthlyLeadersHeight(len(monthLeaders), places, faces) + 20
+
+	img := image.NewRGBA(image.Rect(0, 0, PaperWidth(), height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+	drawHorizontalLine(img, 0, 0, 0, 1, color.Black)
+
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.Black,
+		Face: faces.stats,
+	}
+	drawCenteredText(d, leaderboardHeader(twitchapi.LeaderboardPeriod(), time.Now()), padding)
+
+	drawMonthlyLeaders(img, d, headerHeight, monthLeaders, missingScope, places, faces)
+
+	// Draw bottom separator (dashed)
+	lineY := height - 10
+	for x := 10; x < PaperWidth()-10; x += 4 {
+		for y := 0; y < 2; y++ {
+			img.Set(x, lineY+y, color.Black)
+		}
+	}
+
+	return img, nil
+}
@@ -0,0 +1,28 @@
+package output
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeaderboardHeaderUsesPSTPeriod(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	// 日本時間では2月1日（土）の朝だが、PST ではまだ1月31日（金）
+	now := time.Date(2025, 2, 1, 5, 0, 0, 0, jst)
+
+	tests := []struct {
+		period string
+		want   string
+	}{
+		{"day", "2025年1月31日"},
+		{"week", "1月27日からの週"},
+		{"month", "2025年1月"},
+		{"year", "2025年"},
+		{"all", "歴代ランキング"},
+	}
+	for _, tt := range tests {
+		if got := leaderboardHeader(tt.period, now); got != tt.want {
+			t.Errorf("leaderboardHeader(%q) = %q, want %q", tt.period, got, tt.want)
+		}
+	}
+}
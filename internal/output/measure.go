@@ -0,0 +1,58 @@
+package output
+
+import (
+	"github.com/rivo/uniseg"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// runeWidthCache は1回の描画の間、同じ文字の幅を何度も計測しないためのキャッシュ。
+// face ごとに作成し、描画が終わったら捨てる（face をまたいで共有しない）
+type runeWidthCache struct {
+	face   font.Face
+	widths map[rune]fixed.Int26_6
+}
+
+func newRuneWidthCache(face font.Face) *runeWidthCache {
+	return &runeWidthCache{
+		face:   face,
+		widths: make(map[rune]fixed.Int26_6),
+	}
+}
+
+// advance returns the advance width of a single rune.
+// 結合文字（濁点の合成用など）はフォント上の送り幅（通常0）がそのまま使われる
+func (c *runeWidthCache) advance(r rune) fixed.Int26_6 {
+	if w, ok := c.widths[r]; ok {
+		return w
+	}
+	// グリフがない場合も font.MeasureString と同じく返された送り幅を使う
+	w, _ := c.face.GlyphAdvance(r)
+	c.widths[r] = w
+	return w
+}
+
+// measure returns the width of s in pixels (kerning is ignored)
+func (c *runeWidthCache) measure(s string) int {
+	return int(c.advanceString(s) >> 6)
+}
+
+// advanceString returns the summed advance width of s (grapheme clusters included)
+func (c *runeWidthCache) advanceString(s string) fixed.Int26_6 {
+	var total fixed.Int26_6
+	for _, r := range s {
+		total += c.advance(r)
+	}
+	return total
+}
+
+// graphemes splits s into grapheme clusters (base character + combining marks,
+// ZWJ emoji sequences, regional indicator pairs など、見た目上1文字になる単位)
+func graphemes(s string) []string {
+	var clusters []string
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		clusters = append(clusters, g.Str())
+	}
+	return clusters
+}
@@ -0,0 +1,101 @@
+package output
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/broadcast"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// 一時停止（用紙交換やプリンターの移動中）の状態。
+// DRY-RUN と違い、停止中のFAXは捨てずに印刷キューに溜めておき、再開したら順に印刷する。
+// 溜められるのはキューの容量まで（超えた分は PRINT_QUEUE_FULL_POLICY に従う）
+var (
+	pauseMu  sync.Mutex
+	paused   bool
+	pausedAt time.Time
+	resumeCh chan struct{} // 停止中のみ。再開時に close する
+)
+
+// PauseStatus is the printer pause state shown in the printer status
+type PauseStatus struct {
+	Paused   bool       `json:"paused"`
+	PausedAt *time.Time `json:"paused_at,omitempty"`
+	Queued   int        `json:"queued"` // 再開待ちのジョブ数
+}
+
+// PausePrinting stops printing until ResumePrinting is called. 停止済みの場合は何もしない
+func PausePrinting() PauseStatus {
+	pauseMu.Lock()
+	if !paused {
+		paused = true
+		pausedAt = time.Now()
+		resumeCh = make(chan struct{})
+		logger.Info("Printing paused")
+		pauseMu.Unlock()
+		broadcastPauseState("printer_paused")
+	} else {
+		pauseMu.Unlock()
+	}
+	return GetPauseStatus()
+}
+
+// ResumePrinting resumes printing; buffered faxes are printed in order
+func ResumePrinting() PauseStatus {
+	pauseMu.Lock()
+	if paused {
+		paused = false
+		close(resumeCh)
+		resumeCh = nil
+		logger.Info("Printing resumed",
+			zap.Duration("paused_for", time.Since(pausedAt)),
+			zap.Int("queued", GetPrintQueueSize()))
+		pauseMu.Unlock()
+		broadcastPauseState("printer_resumed")
+	} else {
+		pauseMu.Unlock()
+	}
+	return GetPauseStatus()
+}
+
+// IsPrintingPaused reports whether printing is paused
+func IsPrintingPaused() bool {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	return paused
+}
+
+// GetPauseStatus returns the pause state and the number of waiting jobs
+func GetPauseStatus() PauseStatus {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	s := PauseStatus{Paused: paused, Queued: GetPrintQueueSize()}
+	if paused {
+		at := pausedAt
+		s.PausedAt = &at
+	}
+	return s
+}
+
+// waitWhilePaused blocks the print queue until printing is resumed
+func waitWhilePaused() {
+	pauseMu.Lock()
+	if !paused {
+		pauseMu.Unlock()
+		return
+	}
+	ch := resumeCh
+	pauseMu.Unlock()
+
+	logger.Info("Print queue is paused, holding job until resumed")
+	<-ch
+}
+
+func broadcastPauseState(eventType string) {
+	broadcast.Send(map[string]interface{}{
+		"type": eventType,
+		"data": GetPauseStatus(),
+	})
+}
@@ -0,0 +1,98 @@
+package output
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// printerLockWait は1回の取得待ちの上限
+	printerLockWait = 30 * time.Second
+	// printerLockStuckAfter を超えて保持されているロックは BLE 操作が固まったとみなして強制解放する
+	printerLockStuckAfter = 3 * time.Minute
+)
+
+// printerLocker serializes setup/connect/print on the printer with a bounded wait.
+// sync.Mutex と違い、BLE 操作が固まって解放されないロックを強制的に取り戻せる。
+// 強制解放後に元の保持者が release しても、世代（token）が違うので無視される
+type printerLocker struct {
+	sem chan struct{}
+
+	mu    sync.Mutex
+	gen   uint64
+	owner string
+	since time.Time
+}
+
+var printerLock = &printerLocker{sem: make(chan struct{}, 1)}
+
+// acquire waits up to wait for the printer and returns a token to pass to release.
+// 待ち時間を過ぎた時点で保持者が printerLockStuckAfter 以上ロックを持っていれば強制解放して取得する
+func (l *printerLocker) acquire(owner string, wait time.Duration) (uint64, bool) {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case l.sem <- struct{}{}:
+		return l.take(owner), true
+	case <-timer.C:
+	}
+
+	l.mu.Lock()
+	holder, heldFor := l.owner, time.Since(l.since)
+	stuck := heldFor > printerLockStuckAfter
+	if stuck {
+		logger.Warn("Printer lock held too long, forcing release",
+			zap.String("holder", holder),
+			zap.Duration("held_for", heldFor),
+			zap.String("waiter", owner))
+		l.gen++ // 元の保持者の release を無効にする
+		l.owner = ""
+		select {
+		case <-l.sem:
+		default:
+		}
+	}
+	l.mu.Unlock()
+
+	if !stuck {
+		logger.Warn("Timed out waiting for printer lock",
+			zap.String("holder", holder),
+			zap.Duration("held_for", heldFor),
+			zap.String("waiter", owner))
+		return 0, false
+	}
+
+	// 強制解放した直後なので通常はすぐ取得できる
+	select {
+	case l.sem <- struct{}{}:
+		// 固まった接続は次の SetupPrinter で完全にリセットされる
+		return l.take(owner), true
+	case <-time.After(time.Second):
+		return 0, false
+	}
+}
+
+func (l *printerLocker) take(owner string) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.gen++
+	l.owner = owner
+	l.since = time.Now()
+	return l.gen
+}
+
+// release frees the lock if token is still the current holder
+func (l *printerLocker) release(token uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if token != l.gen || l.owner == "" {
+		logger.Warn("Ignoring release of a printer lock that was already forcibly released")
+		return
+	}
+	l.owner = ""
+	<-l.sem
+}
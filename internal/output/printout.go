@@ -10,32 +10,94 @@ import (
 	"time"
 
 	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/nantokaworks/twitch-overlay/internal/clockschedule"
 	"github.com/nantokaworks/twitch-overlay/internal/env"
 	"github.com/nantokaworks/twitch-overlay/internal/faxmanager"
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
+	"github.com/nantokaworks/twitch-overlay/internal/printrules"
 	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
 	"github.com/nantokaworks/twitch-overlay/internal/broadcast"
 	"github.com/nantokaworks/twitch-overlay/internal/status"
 	"go.uber.org/zap"
 )
 
-var printQueue chan image.Image
+// printJob is a single item in the print queue
+type printJob struct {
+	id             int64 // print_jobs の行ID（永続化していないジョブは0）
+	img            image.Image
+	colorImg       image.Image // カラー印刷が要求された場合のみ設定
+	printerAddress string      // 空の場合はPRINTER_ADDRESSを使用
+}
+
+// 通常のFAX（チャット・画像・時計）は printQueue、イベントのFAX（cheer/raid/sub など）は
+// priorityQueue に入れ、ワーカーは priorityQueue を先に取り出す
+var printQueue chan printJob
+var priorityQueue chan printJob
 var lastPrintTime time.Time
 var lastPrintMutex sync.Mutex
-var printerMutex sync.Mutex
+
+// lastPrintStateKey は最終印刷時刻を保存する app_state のキー
+const lastPrintStateKey = "printer.last_print"
+
+// 時計・キープアライブのgoroutine停止用（設定の再読み込みで再起動する）
+var (
+	routinesMu    sync.Mutex
+	clockStop     chan struct{}
+	keepAliveStop chan struct{}
+)
 
 // shouldUseDryRun determines if dry-run mode should be active
 func shouldUseDryRun() bool {
+	return dryRunReason() != ""
+}
+
+// dryRunReason returns why printing is skipped right now ("" なら実際に印刷する)
+func dryRunReason() string {
 	// If DryRunMode is explicitly set, always use it
 	if env.Value.DryRunMode {
-		return true
+		return "DRY-RUN MODE"
 	}
 	
 	// If AutoDryRunWhenOffline is enabled and stream is offline, use dry-run
 	if env.Value.AutoDryRunWhenOffline && !status.IsStreamLive() {
-		return true
+		return "AUTO DRY-RUN: STREAM OFFLINE"
+	}
+
+	// 静音時間帯（QUIET_HOURS_START〜QUIET_HOURS_END）は画像の保存と配信だけ行う
+	if IsQuietHours() {
+		return "QUIET HOURS"
 	}
 	
-	return false
+	return ""
+}
+
+// IsDryRunActive reports whether printing is currently skipped by dry-run mode
+func IsDryRunActive() bool {
+	return shouldUseDryRun()
+}
+
+// CheckPrinterReachable connects to the configured printer if not already connected
+func CheckPrinterReachable() error {
+	if env.Value.PrinterAddress == nil || *env.Value.PrinterAddress == "" {
+		return fmt.Errorf("printer address not configured")
+	}
+
+	lock, ok := printerLock.acquire("reachability check", printerLockWait)
+	if !ok {
+		return fmt.Errorf("printer is busy")
+	}
+	defer printerLock.release(lock)
+
+	if IsConnected() {
+		return nil
+	}
+
+	c, err := SetupPrinter()
+	if err != nil {
+		return fmt.Errorf("failed to setup printer: %w", err)
+	}
+
+	return ConnectPrinter(c, *env.Value.PrinterAddress)
 }
 
 // InitializePrinter initializes the printer subsystem (including keep-alive and clock)
@@ -52,21 +114,17 @@ func InitializePrinter() {
 			return "<not set>"
 		}()))
 	
-	// Start keep-alive goroutine if enabled
-	if env.Value.KeepAliveEnabled {
-		logger.Info("[InitializePrinter] Starting keep-alive routine")
-		go keepAliveRoutine()
-	} else {
-		logger.Info("[InitializePrinter] Keep-alive routine disabled")
-	}
-	
-	// Start clock routine
-	if env.Value.ClockEnabled {
-		logger.Info("[InitializePrinter] Starting clock routine")
-		go clockRoutine()
-	} else {
-		logger.Info("[InitializePrinter] Clock routine disabled")
-	}
+	// 前回の最終印刷時刻を引き継いでから keep-alive を開始する
+	restoreLastPrintTime()
+
+	// 前回終了時に印刷されずに残っていたジョブをキューに戻す
+	restorePrintJobs()
+
+	// Start keep-alive and clock goroutines if enabled
+	RestartBackgroundRoutines()
+
+	// 設定の再読み込みに追従する
+	subscribeSettingChanges()
 	
 	logger.Info("[InitializePrinter] Printer subsystem initialization complete", 
 		zap.Bool("keep_alive_enabled", env.Value.KeepAliveEnabled),
@@ -75,7 +133,8 @@ func InitializePrinter() {
 }
 
 func init() {
-	printQueue = make(chan image.Image, 100)
+	printQueue = make(chan printJob, 100)
+	priorityQueue = make(chan printJob, 20)
 	
 	// Initialize last print time to now
 	lastPrintTime = time.Now()
@@ -84,38 +143,66 @@ func init() {
 	// after env.Value is properly initialized
 	
 	go func() {
-		for img := range printQueue {
-			// Lock printer for exclusive access
-			printerMutex.Lock()
+		for {
+			job := nextPrintJob()
+
+			// 一時停止中は再開までジョブを保持する（残りはキューに溜まる）
+			waitWhilePaused()
+
+			// 静音時間帯はプリンターに接続もしない（画像の保存とオーバーレイへの配信は済んでいる）
+			if IsQuietHours() {
+				logger.Info("Skipping actual printing", zap.String("reason", "QUIET HOURS"))
+				markPrintJobDone(job)
+				continue
+			}
+
+			img := job.img
+			address := *env.Value.PrinterAddress
+			if job.printerAddress != "" {
+				address = job.printerAddress
+			}
+
+			// Lock printer for exclusive access（固まったロックは printerLock が強制解放する）
+			lock, ok := printerLock.acquire("print queue", printerLockWait)
+			for !ok {
+				lock, ok = printerLock.acquire("print queue", printerLockWait)
+			}
 			
 			// Setup printer if needed
 			c, err := SetupPrinter()
 			if err != nil {
 				logger.Error("failed to setup printer", zap.Error(err))
-				printerMutex.Unlock()
+				recordFaxFailed("setup: " + err.Error())
+				printerLock.release(lock)
+				// 印刷されていないので print_jobs に残し、次回起動時に restorePrintJobs で印刷し直す
 				continue
 			}
 			
 			// Try to connect if not connected
-			err = ConnectPrinter(c, *env.Value.PrinterAddress)
+			err = ConnectPrinter(c, address)
 			if err != nil {
 				logger.Error("failed to connect printer", zap.Error(err))
-				printerMutex.Unlock()
+				recordFaxFailed("connect: " + err.Error())
+				printerLock.release(lock)
+				// プリンターがオフラインの間のジョブも次回起動時まで残す
 				continue
 			}
 			
+			// 印刷できた・意図的に印刷しなかった（dry-run）ジョブだけを完了にする
+			done := false
+
 			// Check for dry-run mode (including auto dry-run when offline)
-			if shouldUseDryRun() {
-				if env.Value.AutoDryRunWhenOffline && !status.IsStreamLive() {
-					logger.Info("Auto dry-run mode (stream offline): skipping actual printing")
-				} else {
-					logger.Info("Dry-run mode: skipping actual printing")
-				}
+			if reason := dryRunReason(); reason != "" {
+				logger.Info("Skipping actual printing", zap.String("reason", reason))
 				// Update last print time even in dry-run mode
-				lastPrintMutex.Lock()
-				lastPrintTime = time.Now()
-				lastPrintMutex.Unlock()
+				recordLastPrint()
+				done = true
 			} else {
+				// catprinter プロトコルはカラー非対応のため、カラー印刷が要求されてもモノクロで印刷する
+				if job.colorImg != nil {
+					warnColorFallback()
+				}
+
 				// Rotate image 180 degrees if ROTATE_PRINT is enabled
 				finalImg := img
 				if env.Value.RotatePrint {
@@ -124,16 +211,31 @@ func init() {
 				
 				if err := c.Print(finalImg, opts, false); err != nil {
 					logger.Error("failed to print", zap.Error(err))
+					recordFaxFailed("print: " + err.Error())
 				} else {
+					recordFaxPrinted()
+					done = true
+
+					// 印刷完了の合図（PRINT_BEEP）
+					if env.Value.PrintBeep {
+						printBeep()
+					}
+
 					// Update last print time on successful print
-					lastPrintMutex.Lock()
-					lastPrintTime = time.Now()
-					lastPrintMutex.Unlock()
+					recordLastPrint()
+
+					// 続けて印刷するジョブがある場合は区切りを入れる（FAX_SEPARATOR）
+					if GetPrintQueueSize() > 0 {
+						printSeparator(c)
+					}
 				}
 			}
 			
 			// Release printer lock
-			printerMutex.Unlock()
+			printerLock.release(lock)
+			if done {
+				markPrintJobDone(job)
+			}
 		}
 	}()
 }
@@ -145,20 +247,33 @@ func PrintClock(timeStr string) error {
 
 // PrintClockWithOptions sends clock output to printer and frontend with options
 func PrintClockWithOptions(timeStr string, forceEmptyLeaderboard bool) error {
-	// Generate color version
-	colorImg, err := GenerateTimeImageWithStatsColorOptions(timeStr, forceEmptyLeaderboard)
-	if err != nil {
-		return fmt.Errorf("failed to create color clock image: %w", err)
-	}
+	var colorImg, monoImg image.Image
+	var err error
 
-	// Generate monochrome version for printing
-	monoImg, err := GenerateTimeImageWithStatsOptions(timeStr, forceEmptyLeaderboard)
-	if err != nil {
-		return fmt.Errorf("failed to create monochrome clock image: %w", err)
+	if env.Value.ClockCondensed {
+		// 省スペース版はモノクロ/カラー共通の画像を使う
+		monthLeaders, missingScope := getBitsLeaders(forceEmptyLeaderboard)
+		monoImg, err = GenerateTimeImageCondensed(timeStr, monthLeaders, missingScope)
+		if err != nil {
+			return fmt.Errorf("failed to create condensed clock image: %w", err)
+		}
+		colorImg = monoImg
+	} else {
+		// Generate color version
+		colorImg, err = GenerateTimeImageWithStatsColorOptions(timeStr, forceEmptyLeaderboard)
+		if err != nil {
+			return fmt.Errorf("failed to create color clock image: %w", err)
+		}
+
+		// Generate monochrome version for printing
+		monoImg, err = GenerateTimeImageWithStatsOptions(timeStr, forceEmptyLeaderboard)
+		if err != nil {
+			return fmt.Errorf("failed to create monochrome clock image: %w", err)
+		}
 	}
 
 	// Save fax with faxmanager (use "System" as username for clock)
-	fax, err := faxmanager.SaveFax("🕐 Clock", timeStr, "", colorImg, monoImg)
+	fax, err := faxmanager.SaveFax(faxmanager.SenderClock, timeStr, "", colorImg, monoImg)
 	if err != nil {
 		return fmt.Errorf("failed to save clock fax: %w", err)
 	}
@@ -172,8 +287,29 @@ func PrintClockWithOptions(timeStr string, forceEmptyLeaderboard bool) error {
 	broadcast.BroadcastFax(fax)
 
 	// Add to print queue
-	printQueue <- monoImg
-	return nil
+	return enqueueFax(printrules.Event{Type: printrules.EventClock}, fax, colorImg, monoImg)
+}
+
+// PrintLeaderboard prints the monthly Cheer leaderboard as its own fax (時計を待たずに月末のまとめを印刷する)
+func PrintLeaderboard(forceEmptyLeaderboard bool) error {
+	img, err := GenerateLeaderboardImage(forceEmptyLeaderboard)
+	if err != nil {
+		return fmt.Errorf("failed to create leaderboard image: %w", err)
+	}
+
+	fax, err := faxmanager.SaveFax(faxmanager.SenderLeaderboard, time.Now().Format("2006-01"), "", img, img)
+	if err != nil {
+		return fmt.Errorf("failed to save leaderboard fax: %w", err)
+	}
+
+	if err := saveFaxImages(fax, img, img); err != nil {
+		return fmt.Errorf("failed to save leaderboard fax images: %w", err)
+	}
+
+	broadcast.BroadcastFax(fax)
+
+	// 時計と同じ印刷ルールを適用する
+	return enqueueFax(printrules.Event{Type: printrules.EventClock}, fax, img, img)
 }
 
 func PrintOut(userName string, message []twitch.ChatMessageFragment, timestamp time.Time) error {
@@ -212,12 +348,16 @@ func PrintOut(userName string, message []twitch.ChatMessageFragment, timestamp t
 	broadcast.BroadcastFax(fax)
 
 	// Add to print queue
-	printQueue <- monoImg
-	return nil
+	return enqueueFax(printrules.Event{Type: printrules.EventChat}, fax, colorImg, monoImg)
 }
 
 // PrintOutWithTitle sends fax output with separate title and details to printer and frontend
 func PrintOutWithTitle(title, userName, extra, details string, timestamp time.Time) error {
+	return PrintOutWithTitleForEvent(printrules.Event{}, title, userName, extra, details, timestamp)
+}
+
+// PrintOutWithTitleForEvent is PrintOutWithTitle with the source event used for print routing rules
+func PrintOutWithTitleForEvent(event printrules.Event, title, userName, extra, details string, timestamp time.Time) error {
 	// Generate color version
 	colorImg, err := MessageToImageWithTitle(title, userName, extra, details, true)
 	if err != nil {
@@ -254,7 +394,68 @@ func PrintOutWithTitle(title, userName, extra, details string, timestamp time.Ti
 	broadcast.BroadcastFax(fax)
 
 	// Add to print queue
-	printQueue <- monoImg
+	return enqueueFax(event, fax, colorImg, monoImg)
+}
+
+// PrintImage prints an arbitrary uploaded image (logos, test patterns) scaled to the paper width
+func PrintImage(title string, src image.Image) error {
+	if src.Bounds().Dx() == 0 || src.Bounds().Dy() == 0 {
+		return fmt.Errorf("image is empty")
+	}
+
+	// Generate color version (紙幅に合わせてリサイズ)
+	colorImg := resizeToWidth(src, PaperWidth())
+
+	// Generate monochrome version for printing
+	monoImg := convertToGrayscaleWithDithering(colorImg)
+
+	// Save fax with faxmanager
+	fax, err := faxmanager.SaveFax(faxmanager.SenderImage, title, "", colorImg, monoImg)
+	if err != nil {
+		return fmt.Errorf("failed to save image fax: %w", err)
+	}
+
+	// Save images to disk
+	if err := saveFaxImages(fax, colorImg, monoImg); err != nil {
+		return fmt.Errorf("failed to save image fax images: %w", err)
+	}
+
+	// Broadcast to SSE clients
+	broadcast.BroadcastFax(fax)
+
+	// Add to print queue
+	return enqueueFax(printrules.Event{}, fax, colorImg, monoImg)
+}
+
+// enqueueFax evaluates print routing rules and adds the fax to the print queue.
+// キューが満杯で印刷できなかった場合は ErrQueueFull を返す
+func enqueueFax(event printrules.Event, fax *faxmanager.Fax, colorImg, monoImg image.Image) error {
+	decision := printrules.Evaluate(event)
+
+	job := printJob{img: monoImg, printerAddress: decision.PrinterAddress}
+	if decision.Color || env.Value.ColorPrint {
+		job.colorImg = colorImg
+	}
+
+	if decision.RuleID != 0 {
+		logger.Info("Print routing rule applied",
+			zap.Int64("rule_id", decision.RuleID),
+			zap.String("event_type", event.Type),
+			zap.String("printer_address", decision.PrinterAddress),
+			zap.Bool("color", decision.Color),
+			zap.Int("copies", decision.Copies))
+	}
+
+	priority := priorityForEvent(event.Type)
+	for i := 0; i < decision.Copies; i++ {
+		// 再起動しても失われないよう、キューに入れる前に記録する
+		copyJob := job
+		persistPrintJob(&copyJob, fax, priority)
+		if err := enqueuePrintJob(copyJob, priority); err != nil {
+			markPrintJobDone(copyJob)
+			return err
+		}
+	}
 	return nil
 }
 
@@ -282,18 +483,11 @@ func saveFaxImages(fax *faxmanager.Fax, colorImg, monoImg image.Image) error {
 		return fmt.Errorf("failed to encode mono image: %w", err)
 	}
 
-	if shouldUseDryRun() {
-		if env.Value.AutoDryRunWhenOffline && !status.IsStreamLive() {
-			logger.Info("Fax images saved (AUTO DRY-RUN: STREAM OFFLINE)",
-				zap.String("id", fax.ID),
-				zap.String("colorPath", fax.ColorPath),
-				zap.String("monoPath", fax.MonoPath))
-		} else {
-			logger.Info("Fax images saved (DRY-RUN MODE)",
-				zap.String("id", fax.ID),
-				zap.String("colorPath", fax.ColorPath),
-				zap.String("monoPath", fax.MonoPath))
-		}
+	if reason := dryRunReason(); reason != "" {
+		logger.Info("Fax images saved ("+reason+")",
+			zap.String("id", fax.ID),
+			zap.String("colorPath", fax.ColorPath),
+			zap.String("monoPath", fax.MonoPath))
 	} else {
 		logger.Info("Fax images saved",
 			zap.String("id", fax.ID),
@@ -305,16 +499,30 @@ func saveFaxImages(fax *faxmanager.Fax, colorImg, monoImg image.Image) error {
 }
 
 
-func clockRoutine() {
+func clockRoutine(stop <-chan struct{}) {
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 	
-	lastPrintedTime := ""
+	var dedupe clockDeduper
+	var scheduleSpec string
+	var schedule *clockschedule.Schedule
 	lastMonth := time.Now().Format("2006-01")
 	
-	for range ticker.C {
+	for {
+		select {
+		case <-stop:
+			logger.Info("Clock routine stopped")
+			return
+		case <-ticker.C:
+		}
+
+		// CLOCK_SCHEDULE は毎回参照する（変更されても重複防止の状態を保ったまま切り替える）
+		if schedule == nil || env.Value.ClockSchedule != scheduleSpec {
+			scheduleSpec = env.Value.ClockSchedule
+			schedule = parseClockSchedule(scheduleSpec)
+		}
+
 		now := time.Now()
-		minute := now.Minute()
 		currentMonth := now.Format("2006-01")
 		
 		// Check if month has changed
@@ -325,22 +533,17 @@ func clockRoutine() {
 			lastMonth = currentMonth
 		}
 		
-		// Check if it's 0 minutes (on the hour)
-		if minute == 0 {
-			currentTimeStr := now.Format("15:04")
-			
-			// Avoid printing the same time multiple times
-			if currentTimeStr != lastPrintedTime {
-				lastPrintedTime = currentTimeStr
-				
-				logger.Info("Clock: printing time with latest leaderboard data", zap.String("time", currentTimeStr))
-				
-				// Use PrintClock to handle everything (generation, saving, broadcasting, and printing)
-				if err := PrintClock(currentTimeStr); err != nil {
-					logger.Error("Clock: failed to print clock", zap.Error(err))
-				} else {
-					logger.Info("Clock: successfully printed and broadcasted")
-				}
+		// 予定時刻（遅れても clockCatchUp 以内）に1回だけ印刷する
+		if slot, ok := dedupe.due(now, schedule); ok {
+			currentTimeStr := slot.Format("15:04")
+
+			logger.Info("Clock: printing time with latest leaderboard data", zap.String("time", currentTimeStr))
+
+			// Use PrintClock to handle everything (generation, saving, broadcasting, and printing)
+			if err := PrintClock(currentTimeStr); err != nil {
+				logger.Error("Clock: failed to print clock", zap.Error(err))
+			} else {
+				logger.Info("Clock: successfully printed and broadcasted")
 			}
 		}
 	}
@@ -349,24 +552,100 @@ func clockRoutine() {
 
 
 
+// parseClockSchedule parses CLOCK_SCHEDULE, falling back to on-the-hour when it is invalid
+func parseClockSchedule(spec string) *clockschedule.Schedule {
+	schedule, err := clockschedule.Parse(spec)
+	if err != nil {
+		logger.Warn("Clock: invalid CLOCK_SCHEDULE, printing on the hour", zap.String("schedule", spec), zap.Error(err))
+		schedule, _ = clockschedule.Parse(clockschedule.Default)
+	}
+	return schedule
+}
+
+// RestartBackgroundRoutines stops the clock and keep-alive goroutines and starts
+// them again according to the current env.Value (used after settings reload)
+func RestartBackgroundRoutines() {
+	routinesMu.Lock()
+	defer routinesMu.Unlock()
+
+	if keepAliveStop != nil {
+		close(keepAliveStop)
+		keepAliveStop = nil
+	}
+	if clockStop != nil {
+		close(clockStop)
+		clockStop = nil
+	}
+
+	if env.Value.KeepAliveEnabled {
+		logger.Info("Starting keep-alive routine")
+		keepAliveStop = make(chan struct{})
+		go keepAliveRoutine(keepAliveStop)
+	} else {
+		logger.Info("Keep-alive routine disabled")
+	}
+
+	if env.Value.ClockEnabled {
+		logger.Info("Starting clock routine")
+		clockStop = make(chan struct{})
+		go clockRoutine(clockStop)
+	} else {
+		logger.Info("Clock routine disabled")
+	}
+}
+
+// pauseKeepAlive stops the keep-alive routine and returns a function that starts it again.
+// 再接続中に keep-alive が割り込んで接続をやり直さないようにするため
+func pauseKeepAlive() (resume func()) {
+	routinesMu.Lock()
+	defer routinesMu.Unlock()
+
+	if keepAliveStop == nil {
+		return func() {}
+	}
+	close(keepAliveStop)
+	keepAliveStop = nil
+
+	return func() {
+		routinesMu.Lock()
+		defer routinesMu.Unlock()
+		// 停止中に設定変更で再起動されていれば何もしない
+		if keepAliveStop != nil || !env.Value.KeepAliveEnabled {
+			return
+		}
+		keepAliveStop = make(chan struct{})
+		go keepAliveRoutine(keepAliveStop)
+	}
+}
+
 // keepAliveRoutine maintains printer connection
-func keepAliveRoutine() {
+func keepAliveRoutine(stop <-chan struct{}) {
 	ticker := time.NewTicker(1 * time.Second) // Check every second
 	defer ticker.Stop()
 	
-	for range ticker.C {
+	for {
+		select {
+		case <-stop:
+			logger.Info("Keep-alive routine stopped")
+			return
+		case <-ticker.C:
+		}
+
 		// First check if we need to do initial connection
 		if !IsConnected() && !HasInitialPrintBeenDone() {
 			logger.Info("Keep-alive: attempting initial printer connection")
 			
-			// Lock printer for exclusive access
-			printerMutex.Lock()
+			// Lock printer for exclusive access（取れなければ次の tick で再試行）
+			lock, ok := printerLock.acquire("keep-alive", printerLockWait)
+			if !ok {
+				continue
+			}
 			
 			// Setup printer if needed
 			c, err := SetupPrinter()
 			if err != nil {
 				logger.Error("Keep-alive: failed to setup printer for initial connection", zap.Error(err))
-				printerMutex.Unlock()
+				printerLock.release(lock)
 				continue
 			}
 			
@@ -374,7 +653,7 @@ func keepAliveRoutine() {
 			err = ConnectPrinter(c, *env.Value.PrinterAddress)
 			if err != nil {
 				logger.Error("Keep-alive: failed initial connection to printer", zap.Error(err))
-				printerMutex.Unlock()
+				printerLock.release(lock)
 				continue
 			}
 			
@@ -385,14 +664,17 @@ func keepAliveRoutine() {
 			MarkInitialPrintDone()
 			
 			// Update last print time
-			lastPrintMutex.Lock()
-			lastPrintTime = time.Now()
-			lastPrintMutex.Unlock()
+			recordLastPrint()
 			
-			printerMutex.Unlock()
+			printerLock.release(lock)
 			continue
 		}
 		
+		// 一時停止中（用紙交換・移動中）はプリンターに接続しない
+		if IsPrintingPaused() {
+			continue
+		}
+
 		lastPrintMutex.Lock()
 		timeSinceLastPrint := time.Since(lastPrintTime)
 		lastPrintMutex.Unlock()
@@ -401,8 +683,11 @@ func keepAliveRoutine() {
 		if timeSinceLastPrint > time.Duration(env.Value.KeepAliveInterval)*time.Second {
 			logger.Info("Keep-alive: waiting for printer access", zap.Int("seconds_since_last_print", int(timeSinceLastPrint.Seconds())))
 			
-			// Lock printer for exclusive access
-			printerMutex.Lock()
+			// Lock printer for exclusive access（取れなければ次の tick で再試行）
+			lock, ok := printerLock.acquire("keep-alive", printerLockWait)
+			if !ok {
+				continue
+			}
 			
 			logger.Info("Keep-alive: creating new connection")
 			
@@ -410,14 +695,14 @@ func keepAliveRoutine() {
 			c, err := SetupPrinter()
 			if err != nil {
 				logger.Error("Keep-alive: failed to setup printer", zap.Error(err))
-				printerMutex.Unlock()
+				printerLock.release(lock)
 				continue
 			}
 			
 			err = ConnectPrinter(c, *env.Value.PrinterAddress)
 			if err != nil {
 				logger.Error("Keep-alive: failed to connect printer", zap.Error(err))
-				printerMutex.Unlock()
+				printerLock.release(lock)
 				continue
 			}
 			
@@ -430,12 +715,10 @@ func keepAliveRoutine() {
 			}
 			
 			// Update last print time
-			lastPrintMutex.Lock()
-			lastPrintTime = time.Now()
-			lastPrintMutex.Unlock()
+			recordLastPrint()
 			
 			// Release printer lock
-			printerMutex.Unlock()
+			printerLock.release(lock)
 		}
 	}
 }
@@ -478,16 +761,52 @@ func PrintInitialClock() error {
 	// Directly add to print queue without frontend notification
 	// This is the only output that doesn't notify the frontend
 	select {
-	case printQueue <- img:
+	case printQueue <- printJob{img: img}:
 		logger.Info("Initial clock added to print queue (no frontend notification)")
 	default:
-		return fmt.Errorf("print queue is full")
+		return ErrQueueFull
 	}
 	
 	return nil
 }
 
-// GetPrintQueueSize returns the current number of items in the print queue
+// GetLastPrintTime returns when the printer last printed (dry-run と keep-alive の接続も含む)
+func GetLastPrintTime() time.Time {
+	lastPrintMutex.Lock()
+	defer lastPrintMutex.Unlock()
+	return lastPrintTime
+}
+
+// recordLastPrint updates the last print time and persists it so it survives restarts
+func recordLastPrint() {
+	now := time.Now()
+	lastPrintMutex.Lock()
+	lastPrintTime = now
+	lastPrintMutex.Unlock()
+
+	if err := localdb.SetState(lastPrintStateKey, now.Format(time.RFC3339Nano)); err != nil {
+		logger.Debug("Failed to persist last print time", zap.Error(err))
+	}
+}
+
+// restoreLastPrintTime loads the persisted last print time.
+// 再起動直後に keep-alive が不要な再接続をしないよう、前回の値を引き継ぐ
+func restoreLastPrintTime() {
+	value, ok := localdb.GetState(lastPrintStateKey)
+	if !ok {
+		return
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil || t.After(time.Now()) {
+		return
+	}
+	lastPrintMutex.Lock()
+	lastPrintTime = t
+	lastPrintMutex.Unlock()
+	logger.Info("Restored last print time", zap.Time("last_print", t))
+}
+
+// GetPrintQueueSize returns the current number of items in the print queue (両方のレーンの合計)
 func GetPrintQueueSize() int {
-	return len(printQueue)
+	return len(printQueue) + len(priorityQueue)
 }
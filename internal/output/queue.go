@@ -0,0 +1,135 @@
+package output
+
+import (
+	"errors"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/broadcast"
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/printrules"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// ErrQueueFull is returned when a fax could not be queued because the print queue is full.
+// FAX自体は保存・表示済みで、印刷だけが行われない
+var ErrQueueFull = errors.New("print queue is full")
+
+// 印刷キューが満杯のときの動作 (PRINT_QUEUE_FULL_POLICY)
+const (
+	queuePolicyDropOldest = "drop_oldest"
+	queuePolicyDropNewest = "drop_newest"
+	queuePolicyBlock      = "block"
+)
+
+// printPriority selects the print queue lane for a job
+type printPriority int
+
+const (
+	priorityNormal printPriority = iota
+	priorityHigh
+)
+
+// priorityForEvent returns the lane for a fax of the given event type.
+// 応援・レイド・サブスクなどのイベントはチャットの連投に埋もれないよう優先レーンに入れる
+func priorityForEvent(eventType string) printPriority {
+	switch eventType {
+	case printrules.EventCheer, printrules.EventRaid, printrules.EventSubscribe,
+		printrules.EventGiftSub, printrules.EventResub, printrules.EventHypeTrain:
+		return priorityHigh
+	}
+	return priorityNormal
+}
+
+// nextPrintJob blocks until a job is available, always preferring the priority lane
+func nextPrintJob() printJob {
+	select {
+	case job := <-priorityQueue:
+		return job
+	default:
+	}
+
+	select {
+	case job := <-priorityQueue:
+		return job
+	case job := <-printQueue:
+		return job
+	}
+}
+
+// enqueuePrintJob adds job to the print queue lane without blocking the caller indefinitely.
+// プリンターが詰まってキューが満杯でも EventSub の処理が止まらないよう、
+// PRINT_QUEUE_FULL_POLICY に従って古いジョブ・新しいジョブのどちらかを捨てる。
+// 新しいジョブを捨てた場合は ErrQueueFull を返す（既定の drop_newest では待たずにすぐ返す）
+func enqueuePrintJob(job printJob, priority printPriority) error {
+	queue := printQueue
+	if priority == priorityHigh {
+		queue = priorityQueue
+	}
+
+	select {
+	case queue <- job:
+		return nil
+	default:
+	}
+
+	switch env.Value.PrintQueueFullPolicy {
+	case queuePolicyDropOldest:
+		// ワーカーが同時に取り出すこともあるので、捨てられなくても送信を試みる
+		select {
+		case oldest := <-queue:
+			markPrintJobDone(oldest)
+			reportQueueDrop(queuePolicyDropOldest, "oldest job dropped")
+		default:
+		}
+		select {
+		case queue <- job:
+			return nil
+		default:
+			reportQueueDrop(queuePolicyDropOldest, "new job dropped")
+			return ErrQueueFull
+		}
+
+	case queuePolicyBlock:
+		timeout := time.Duration(env.Value.PrintQueueBlockTimeout) * time.Second
+		if timeout <= 0 {
+			timeout = 10 * time.Second
+		}
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		select {
+		case queue <- job:
+			return nil
+		case <-timer.C:
+			reportQueueDrop(queuePolicyBlock, "new job dropped after timeout")
+			return ErrQueueFull
+		}
+
+	default:
+		reportQueueDrop(queuePolicyDropNewest, "new job dropped")
+		return ErrQueueFull
+	}
+}
+
+// reportQueueDrop logs a dropped job and notifies the frontend (printer_busy)
+func reportQueueDrop(policy, reason string) {
+	recordFaxDropped()
+
+	logger.Warn("Print queue is full, dropping a print job",
+		zap.String("policy", policy),
+		zap.String("reason", reason),
+		zap.Int("queue_size", GetPrintQueueSize()),
+		zap.Int("queue_capacity", cap(printQueue)+cap(priorityQueue)))
+
+	broadcast.Send(map[string]interface{}{
+		"type": "printer_busy",
+		"data": map[string]interface{}{
+			"policy":         policy,
+			"reason":         reason,
+			"queue_size":     GetPrintQueueSize(),
+			"queue_capacity": cap(printQueue) + cap(priorityQueue),
+			"message":        "印刷キューが満杯のため、FAXを1件破棄しました",
+		},
+	})
+}
@@ -0,0 +1,196 @@
+package output
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nantokaworks/twitch-overlay/internal/faxmanager"
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/paths"
+	"go.uber.org/zap"
+)
+
+// printJobsDir holds the images of pending print jobs.
+// FAX画像は保持期間（10分）で削除されるので、一時停止中やオフライン中のジョブのために別に保存する
+func printJobsDir() string {
+	return filepath.Join(paths.GetDataDir(), "print_jobs")
+}
+
+// persistPrintJob records a queued fax in print_jobs so it can be restored after a restart.
+// 画像は saveFaxImages で保存済みのファイルを printJobsDir にリンク（またはコピー）する（失敗しても印刷自体は続ける）
+func persistPrintJob(job *printJob, fax *faxmanager.Fax, priority printPriority) {
+	db := localdb.GetDB()
+	if db == nil || fax == nil {
+		return
+	}
+
+	res, err := db.Exec(`INSERT INTO print_jobs (mono_path, color_path, printer_address, priority) VALUES ('', '', ?, ?)`,
+		job.printerAddress, int(priority))
+	if err != nil {
+		logger.Warn("Failed to persist print job", zap.String("fax_id", fax.ID), zap.Error(err))
+		return
+	}
+	job.id, _ = res.LastInsertId()
+
+	if err := os.MkdirAll(printJobsDir(), 0755); err != nil {
+		logger.Warn("Failed to create print job directory", zap.Error(err))
+		markPrintJobDone(*job)
+		job.id = 0
+		return
+	}
+	monoPath := filepath.Join(printJobsDir(), fmt.Sprintf("%d.mono.png", job.id))
+	if err := linkOrCopyFile(fax.MonoPath, monoPath); err != nil {
+		logger.Warn("Failed to store print job image", zap.String("fax_id", fax.ID), zap.Error(err))
+		markPrintJobDone(*job)
+		job.id = 0
+		return
+	}
+	colorPath := ""
+	if job.colorImg != nil {
+		colorPath = filepath.Join(printJobsDir(), fmt.Sprintf("%d.color.png", job.id))
+		if err := linkOrCopyFile(fax.ColorPath, colorPath); err != nil {
+			// カラー画像がなくてもモノクロで印刷できる
+			logger.Warn("Failed to store print job color image", zap.String("fax_id", fax.ID), zap.Error(err))
+			colorPath = ""
+		}
+	}
+
+	if _, err := db.Exec(`UPDATE print_jobs SET mono_path = ?, color_path = ? WHERE id = ?`, monoPath, colorPath, job.id); err != nil {
+		logger.Warn("Failed to persist print job", zap.String("fax_id", fax.ID), zap.Error(err))
+	}
+}
+
+// markPrintJobDone removes a finished (printed, skipped or dropped) job and its images from print_jobs.
+// 印刷に失敗したジョブは残しておき、次回起動時に印刷し直す
+func markPrintJobDone(job printJob) {
+	if job.id == 0 {
+		return
+	}
+	db := localdb.GetDB()
+	if db == nil {
+		return
+	}
+
+	var monoPath, colorPath string
+	if err := db.QueryRow(`SELECT mono_path, color_path FROM print_jobs WHERE id = ?`, job.id).Scan(&monoPath, &colorPath); err == nil {
+		removePrintJobImages(monoPath, colorPath)
+	}
+	if _, err := db.Exec(`DELETE FROM print_jobs WHERE id = ?`, job.id); err != nil {
+		logger.Warn("Failed to mark print job as done", zap.Int64("job_id", job.id), zap.Error(err))
+	}
+}
+
+// removePrintJobImages deletes job images stored in printJobsDir.
+// 以前の形式のジョブは FAX 画像そのものを参照しているので削除しない
+func removePrintJobImages(imagePaths ...string) {
+	dir := printJobsDir() + string(filepath.Separator)
+	for _, p := range imagePaths {
+		if p != "" && strings.HasPrefix(p, dir) {
+			os.Remove(p)
+		}
+	}
+}
+
+// linkOrCopyFile hard-links src to dst, falling back to a copy (別のファイルシステムなど)
+func linkOrCopyFile(src, dst string) error {
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
+
+// restorePrintJobs puts jobs left over from the previous run back into the print queue (古い順)
+func restorePrintJobs() {
+	db := localdb.GetDB()
+	if db == nil {
+		return
+	}
+
+	rows, err := db.Query(`SELECT id, mono_path, color_path, printer_address, priority FROM print_jobs ORDER BY id`)
+	if err != nil {
+		logger.Warn("Failed to load pending print jobs", zap.Error(err))
+		return
+	}
+	type pendingJob struct {
+		id                              int64
+		monoPath, colorPath, printerAdr string
+		priority                        printPriority
+	}
+	var pending []pendingJob
+	for rows.Next() {
+		var p pendingJob
+		var priority int
+		if err := rows.Scan(&p.id, &p.monoPath, &p.colorPath, &p.printerAdr, &priority); err == nil {
+			p.priority = printPriority(priority)
+			pending = append(pending, p)
+		}
+	}
+	rows.Close()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	restored := 0
+	for _, p := range pending {
+		job := printJob{id: p.id, printerAddress: p.printerAdr}
+
+		img, err := loadPNGFile(p.monoPath)
+		if err != nil {
+			// 画像が削除済みなら印刷できないので破棄する
+			logger.Warn("Discarding pending print job without image", zap.Int64("job_id", p.id), zap.Error(err))
+			markPrintJobDone(job)
+			continue
+		}
+		job.img = img
+		if p.colorPath != "" {
+			if colorImg, err := loadPNGFile(p.colorPath); err == nil {
+				job.colorImg = colorImg
+			}
+		}
+
+		queue := printQueue
+		if p.priority == priorityHigh {
+			queue = priorityQueue
+		}
+		select {
+		case queue <- job:
+			restored++
+		default:
+			logger.Warn("Print queue is full, discarding pending print job", zap.Int64("job_id", p.id))
+			markPrintJobDone(job)
+		}
+	}
+
+	logger.Info("Restored pending print jobs", zap.Int("restored", restored), zap.Int("total", len(pending)))
+}
+
+func loadPNGFile(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
@@ -0,0 +1,44 @@
+package output
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+)
+
+// IsQuietHours reports whether printing is currently suppressed by QUIET_HOURS_START / QUIET_HOURS_END
+func IsQuietHours() bool {
+	return inQuietHours(time.Now())
+}
+
+// inQuietHours checks now (TIMEZONE の時刻) against the quiet-hours range.
+// 開始 > 終了 なら日付をまたぐ範囲（例: 23:00〜06:00）。どちらかが未設定、または同じ時刻なら無効
+func inQuietHours(now time.Time) bool {
+	start, err := parseClockMinutes(env.Value.QuietHoursStart)
+	if err != nil {
+		return false
+	}
+	end, err := parseClockMinutes(env.Value.QuietHoursEnd)
+	if err != nil || start == end {
+		return false
+	}
+
+	if loc, err := time.LoadLocation(env.Value.TimeZone); err == nil {
+		now = now.In(loc)
+	}
+	current := now.Hour()*60 + now.Minute()
+	if start < end {
+		return current >= start && current < end
+	}
+	return current >= start || current < end
+}
+
+// parseClockMinutes parses "HH:MM" into minutes since midnight
+func parseClockMinutes(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,120 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+var subscribeOnce sync.Once
+
+// subscribeSettingChanges reacts to settings reloads (settings UI / SIGHUP) for the printer subsystem.
+// 変更されたキーに関係する部分だけを再設定する
+func subscribeSettingChanges() {
+	subscribeOnce.Do(func() {
+		env.Subscribe(func(changed []string) {
+			if err := SetupPrinterOptions(env.Value.BestQuality, env.Value.Dither, env.Value.AutoRotate, env.Value.BlackPoint); err != nil {
+				logger.Error("Failed to setup printer options", zap.Error(err))
+			}
+		}, "BestQuality", "Dither", "AutoRotate", "BlackPoint")
+
+		// KEEP_ALIVE_INTERVAL は毎回参照しているので再起動は不要
+		env.Subscribe(func(changed []string) {
+			RestartBackgroundRoutines()
+		}, "KeepAliveEnabled", "ClockEnabled")
+
+		env.Subscribe(func(changed []string) {
+			if env.Value.PrinterAddress == nil || *env.Value.PrinterAddress == "" {
+				return
+			}
+			go reconnectToAddress(*env.Value.PrinterAddress)
+		}, "PrinterAddress")
+	})
+}
+
+// ErrReconnectTimeout is returned by Reconnect when the reconnection did not finish in time
+var ErrReconnectTimeout = errors.New("printer reconnection timed out")
+
+// Reconnect synchronously drops the current connection and connects to address.
+// keep-alive を止めてから行い、終わったら再開する。ctx の期限までに終わらなければ ErrReconnectTimeout を返す
+// （BLE 操作は中断できないので、その場合も再接続自体はバックグラウンドで続く）
+func Reconnect(ctx context.Context, address string) error {
+	resumeKeepAlive := pauseKeepAlive()
+
+	done := make(chan error, 1)
+	go func() {
+		defer resumeKeepAlive()
+
+		lock, ok := printerLock.acquire("reconnect", printerLockWait)
+		if !ok {
+			done <- errors.New("printer is busy")
+			return
+		}
+		defer printerLock.release(lock)
+
+		if ctx.Err() != nil {
+			// ロック待ちの間に呼び出し側が諦めた
+			done <- ErrReconnectTimeout
+			return
+		}
+		done <- reconnect(address)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		logger.Warn("Printer reconnection timed out", zap.String("address", address))
+		return ErrReconnectTimeout
+	}
+}
+
+// reconnectToAddress drops the current connection and connects to a newly configured printer address
+func reconnectToAddress(address string) {
+	logger.Info("Printer address changed, attempting reconnection", zap.String("new_address", address))
+	if err := reconnect(address); err != nil {
+		logger.Error("Failed to reconnect to printer with new address", zap.String("address", address), zap.Error(err))
+		return
+	}
+	logger.Info("Successfully reconnected to printer", zap.String("address", address))
+}
+
+// reconnect resets the BLE client and connects to address, turning panics into errors
+func reconnect(address string) (err error) {
+	// パニックからの回復処理
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Panic during printer reconnection",
+				zap.Any("panic", r),
+				zap.String("address", address))
+			err = fmt.Errorf("panic during reconnection: %v", r)
+		}
+	}()
+
+	// 既存の接続をリセット（Stop()でBLEデバイスごと解放）
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Warn("Recovered from panic during stop", zap.Any("panic", r))
+			}
+		}()
+		Stop()
+	}()
+
+	time.Sleep(500 * time.Millisecond) // 少し待機
+
+	c, err := SetupPrinter()
+	if err != nil {
+		return fmt.Errorf("setup: %w", err)
+	}
+	if err := ConnectPrinter(c, address); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	return nil
+}
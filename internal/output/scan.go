@@ -0,0 +1,111 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"git.massivebox.net/massivebox/go-catprinter"
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// DiscoveredDevice is a printer found by a BLE scan
+type DiscoveredDevice struct {
+	MACAddress string `json:"mac_address"`
+	Name       string `json:"name"`
+	RSSI       int    `json:"rssi"` // スキャン結果に含まれない場合は0
+}
+
+// ScanForPrinters scans for nearby printers for the given duration.
+// The scan is abandoned early when ctx is cancelled (e.g. the HTTP client disconnected).
+func ScanForPrinters(ctx context.Context, timeout time.Duration) ([]DiscoveredDevice, error) {
+	c, err := SetupPrinter()
+	if err != nil {
+		return nil, err
+	}
+
+	// デバッグログを有効にする
+	c.Debug.Log = true
+	c.Timeout = timeout
+
+	return scanDevices(ctx, c, "", true)
+}
+
+// FindPrinter reports whether a printer with the given MAC address is reachable.
+// 既に接続中ならスキャンせずに true を返す（スキャンすると既存の接続がリセットされるため）
+func FindPrinter(ctx context.Context, address string, timeout time.Duration) (bool, error) {
+	if isConnected && env.Value.PrinterAddress != nil && strings.EqualFold(*env.Value.PrinterAddress, address) {
+		return true, nil
+	}
+
+	// 印刷中にスキャンで接続を奪わないようにする
+	lock, ok := printerLock.acquire("reachability scan", printerLockWait)
+	if !ok {
+		return false, fmt.Errorf("printer is busy")
+	}
+	defer printerLock.release(lock)
+
+	devices, err := ScanForPrinters(ctx, timeout)
+	if err != nil {
+		return false, err
+	}
+	for _, d := range devices {
+		if strings.EqualFold(d.MACAddress, address) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// scanDevices runs ScanDevices in the background so the caller can stop waiting on
+// cancellation, and returns the devices sorted by RSSI (strongest first) then MAC.
+func scanDevices(ctx context.Context, c *catprinter.Client, name string, stopAfter bool) ([]DiscoveredDevice, error) {
+	type scanResult struct {
+		devices []DiscoveredDevice
+		err     error
+	}
+
+	resultCh := make(chan scanResult, 1)
+	go func() {
+		if stopAfter {
+			defer c.Stop()
+		}
+
+		recordBLEDebug("Scanning for devices (name=%q, timeout=%s)", name, c.Timeout)
+		found, err := c.ScanDevices(name)
+		if err != nil {
+			recordBLEDebug("Scan failed: %v", err)
+			resultCh <- scanResult{err: err}
+			return
+		}
+
+		devices := make([]DiscoveredDevice, 0, len(found))
+		for mac, deviceName := range found {
+			recordBLEDebug("Found device %s (%s)", mac, deviceName)
+			devices = append(devices, DiscoveredDevice{
+				MACAddress: mac,
+				Name:       string(deviceName),
+			})
+		}
+		sort.SliceStable(devices, func(i, j int) bool {
+			if devices[i].RSSI != devices[j].RSSI {
+				return devices[i].RSSI > devices[j].RSSI
+			}
+			return devices[i].MACAddress < devices[j].MACAddress
+		})
+
+		resultCh <- scanResult{devices: devices}
+	}()
+
+	select {
+	case res := <-resultCh:
+		return res.devices, res.err
+	case <-ctx.Done():
+		logger.Info("Printer scan cancelled", zap.Error(ctx.Err()))
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,70 @@
+package output
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+
+	"git.massivebox.net/massivebox/go-catprinter"
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// separatorFeedHeight は feed で送る白紙の高さ(px)
+	separatorFeedHeight = 80
+	// separatorLineHeight は line で印刷する区切り線画像の高さ(px)
+	separatorLineHeight = 32
+)
+
+var cutFallbackOnce sync.Once
+
+// printSeparator prints the FAX_SEPARATOR between two back-to-back jobs.
+// Must be called with printerLock held.
+func printSeparator(c *catprinter.Client) {
+	var img image.Image
+
+	switch env.Value.FaxSeparator {
+	case "feed":
+		img = separatorFeedImage()
+	case "cut":
+		// catprinter にはカット命令がないため feed で代用する
+		cutFallbackOnce.Do(func() {
+			logger.Warn("Printer does not support cutting, using paper feed as fax separator")
+		})
+		img = separatorFeedImage()
+	case "line":
+		img = separatorLineImage()
+	default:
+		return
+	}
+
+	if env.Value.RotatePrint {
+		img = rotateImage180(img)
+	}
+	if err := c.Print(img, opts, false); err != nil {
+		logger.Warn("Failed to print fax separator", zap.String("separator", env.Value.FaxSeparator), zap.Error(err))
+	}
+}
+
+// separatorFeedImage returns a blank image used to feed paper
+func separatorFeedImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, PaperWidth(), separatorFeedHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+	return img
+}
+
+// separatorLineImage returns a dashed cut line with margins above and below
+func separatorLineImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, PaperWidth(), separatorLineHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+
+	y := separatorLineHeight/2 - 1
+	dashLength, dashGap := underlineDash()
+	for x := 0; x < PaperWidth(); x += dashLength + dashGap {
+		fillRect(img, x, y, dashLength, 2)
+	}
+	return img
+}
@@ -0,0 +1,160 @@
+package output
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+)
+
+// FaxTheme decorates a rendered fax (border/background).
+// Inset is the space reserved on each side for the decoration; the content is
+// rendered at faxContentWidth so it fits inside without scaling. テーマは感熱紙向けに黒一色で描画する
+type FaxTheme interface {
+	Inset() int
+	Decorate(img *image.RGBA)
+}
+
+var faxThemes = map[string]FaxTheme{
+	"plain":    plainTheme{},
+	"dotted":   dottedTheme{},
+	"rounded":  roundedTheme{},
+	"seasonal": seasonalTheme{},
+}
+
+// currentFaxTheme returns the theme selected by FAX_THEME (plain if unknown)
+func currentFaxTheme() FaxTheme {
+	if theme, ok := faxThemes[env.Value.FaxTheme]; ok {
+		return theme
+	}
+	return plainTheme{}
+}
+
+// faxContentWidth returns the width the fax content is rendered at inside the theme's frame
+func faxContentWidth(theme FaxTheme) int {
+	return PaperWidth() - theme.Inset()*2
+}
+
+// applyFaxTheme places the content (faxContentWidth 幅で描画済み) inside the theme's frame.
+// 1bit の文字がぼやけないよう拡大縮小はせず、そのまま枠の内側に配置する
+func applyFaxTheme(content *image.RGBA, theme FaxTheme) image.Image {
+	inset := theme.Inset()
+	if inset == 0 {
+		theme.Decorate(content)
+		return content
+	}
+
+	b := content.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, PaperWidth(), b.Dy()+inset*2))
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(inset, inset, inset+b.Dx(), inset+b.Dy()), content, b.Min, draw.Src)
+
+	theme.Decorate(dst)
+	return dst
+}
+
+// plainTheme は装飾なし（従来の出力）
+type plainTheme struct{}
+
+func (plainTheme) Inset() int               { return 0 }
+func (plainTheme) Decorate(img *image.RGBA) {}
+
+// dottedTheme は点線の枠
+type dottedTheme struct{}
+
+func (dottedTheme) Inset() int { return 12 }
+
+func (dottedTheme) Decorate(img *image.RGBA) {
+	b := img.Bounds()
+	const dot, gap, margin = 3, 5, 3
+	for x := margin; x < b.Dx()-margin; x += dot + gap {
+		fillRect(img, x, margin, dot, dot)
+		fillRect(img, x, b.Dy()-margin-dot, dot, dot)
+	}
+	for y := margin; y < b.Dy()-margin; y += dot + gap {
+		fillRect(img, margin, y, dot, dot)
+		fillRect(img, b.Dx()-margin-dot, y, dot, dot)
+	}
+}
+
+// roundedTheme は角丸の実線枠
+type roundedTheme struct{}
+
+func (roundedTheme) Inset() int { return 14 }
+
+func (roundedTheme) Decorate(img *image.RGBA) {
+	b := img.Bounds()
+	const thickness, radius, margin = 3, 12, 3
+	left, top := margin, margin
+	right, bottom := b.Dx()-margin, b.Dy()-margin
+
+	// 直線部分
+	fillRect(img, left+radius, top, right-left-radius*2, thickness)
+	fillRect(img, left+radius, bottom-thickness, right-left-radius*2, thickness)
+	fillRect(img, left, top+radius, thickness, bottom-top-radius*2)
+	fillRect(img, right-thickness, top+radius, thickness, bottom-top-radius*2)
+
+	// 角の円弧
+	corners := []struct{ cx, cy, sx, sy int }{
+		{left + radius, top + radius, -1, -1},
+		{right - radius - 1, top + radius, 1, -1},
+		{left + radius, bottom - radius - 1, -1, 1},
+		{right - radius - 1, bottom - radius - 1, 1, 1},
+	}
+	outer := radius * radius
+	inner := (radius - thickness) * (radius - thickness)
+	for _, c := range corners {
+		for dy := 0; dy <= radius; dy++ {
+			for dx := 0; dx <= radius; dx++ {
+				dist := dx*dx + dy*dy
+				if dist <= outer && dist > inner {
+					img.Set(c.cx+dx*c.sx, c.cy+dy*c.sy, color.Black)
+				}
+			}
+		}
+	}
+}
+
+// seasonalTheme は季節のモチーフ（冬は雪の結晶、それ以外は星）を並べた枠
+type seasonalTheme struct{}
+
+func (seasonalTheme) Inset() int { return 16 }
+
+func (seasonalTheme) Decorate(img *image.RGBA) {
+	b := img.Bounds()
+	const size, step = 9, 24
+	month := time.Now().Month()
+	winter := month == time.December || month == time.January || month == time.February
+
+	drawMotif := func(cx, cy int) {
+		half := size / 2
+		for i := -half; i <= half; i++ {
+			img.Set(cx+i, cy, color.Black)
+			img.Set(cx, cy+i, color.Black)
+			if winter {
+				img.Set(cx+i, cy+i, color.Black)
+				img.Set(cx+i, cy-i, color.Black)
+			}
+		}
+		if !winter {
+			fillRect(img, cx-1, cy-1, 3, 3)
+		}
+	}
+
+	offset := size/2 + 3
+	for x := offset; x < b.Dx()-offset; x += step {
+		drawMotif(x, offset)
+		drawMotif(x, b.Dy()-offset-1)
+	}
+	for y := offset + step; y < b.Dy()-offset-step/2; y += step {
+		drawMotif(offset, y)
+		drawMotif(b.Dx()-offset-1, y)
+	}
+}
+
+// fillRect fills a black rectangle
+func fillRect(img *image.RGBA, x, y, w, h int) {
+	draw.Draw(img, image.Rect(x, y, x+w, y+h), &image.Uniform{color.Black}, image.Point{}, draw.Src)
+}
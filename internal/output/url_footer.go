@@ -0,0 +1,64 @@
+package output
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// urlFooterColumns は PRINT_URL_MODE=text_qr で末尾に並べる QR の列数
+const urlFooterColumns = 2
+
+// urlFooterCell returns the width of one footer cell and the QR size drawn in it (width は描画幅)
+func urlFooterCell(width int) (cellW, size int) {
+	cellW = width / urlFooterColumns
+	size = cellW
+	if qs := qrSize(width); qs < size {
+		size = qs
+	}
+	return cellW, size
+}
+
+// urlFooterHeight returns the height of the numbered QR footer for n URLs (番号の行 + QR)
+func urlFooterHeight(n, lineHeight, width int) int {
+	if n == 0 {
+		return 0
+	}
+	_, size := urlFooterCell(width)
+	rows := (n + urlFooterColumns - 1) / urlFooterColumns
+	return rows * (lineHeight + size)
+}
+
+// drawURLFooter draws a small QR for each URL below the text, labelled [1], [2], ...
+// to match the markers printed after the URLs in the message
+func drawURLFooter(img *image.RGBA, d *font.Drawer, urls []string, top, lineHeight, ascent int) {
+	width := img.Bounds().Dx()
+	cellW, size := urlFooterCell(width)
+	for i, u := range urls {
+		row, col := i/urlFooterColumns, i%urlFooterColumns
+
+		// 最終行の数が列数に満たない場合は中央に寄せる
+		inRow := urlFooterColumns
+		if rest := len(urls) - row*urlFooterColumns; rest < inRow {
+			inRow = rest
+		}
+		left := (width-inRow*cellW)/2 + col*cellW
+		y := top + row*(lineHeight+size)
+
+		label := fmt.Sprintf("[%d]", i+1)
+		labelW := font.MeasureString(d.Face, label).Round()
+		d.Dot = fixed.Point26_6{X: fixed.I(left + (cellW-labelW)/2), Y: fixed.I(y + ascent)}
+		d.DrawString(label)
+
+		qrImg, err := generateQR(u, size)
+		if err != nil {
+			continue
+		}
+		b := qrImg.Bounds()
+		x := left + (cellW-b.Dx())/2
+		draw.Draw(img, image.Rect(x, y+lineHeight, x+b.Dx(), y+lineHeight+b.Dy()), qrImg, b.Min, draw.Over)
+	}
+}
@@ -1,6 +1,7 @@
 package output
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -13,7 +14,7 @@ import (
 func FindAddress(c *catprinter.Client, name string) (string, error) {
 	fmt.Printf("Finding MAC by name (will take %d seconds)...", c.Timeout/time.Second)
 
-	devices, err := c.ScanDevices(name)
+	devices, err := scanDevices(context.Background(), c, name, false)
 	if err != nil {
 		return "", err
 	}
@@ -21,15 +22,12 @@ func FindAddress(c *catprinter.Client, name string) (string, error) {
 	case 0:
 		return "", errors.New("no devices found with name " + name)
 	case 1:
-		for k, _ := range devices {
-			return k, nil
-		}
+		return devices[0].MACAddress, nil
 	default:
 		logger.Info("Found multiple devices", zap.Int("devices", len(devices)))
-		for m, n := range devices {
-			logger.Info("Found device", zap.String("name", m), zap.String("address", string(n)))
+		for _, d := range devices {
+			logger.Info("Found device", zap.String("name", d.MACAddress), zap.String("address", d.Name))
 		}
 		return "", errors.New("multiple devices found with name " + name + ", please specify MAC directly")
 	}
-	return "", nil
 }
@@ -0,0 +1,350 @@
+package printrules
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// イベント種別
+const (
+	EventAny        = "*"
+	EventChat       = "chat"
+	EventClock      = "clock"
+	EventCheer      = "cheer"
+	EventFollow     = "follow"
+	EventRaid       = "raid"
+	EventShoutout   = "shoutout"
+	EventSubscribe  = "subscribe"
+	EventGiftSub    = "gift_sub"
+	EventResub      = "resub"
+	EventHypeTrain  = "hype_train"
+	EventPoll       = "poll"
+	EventPrediction = "prediction"
+	EventGoal       = "goal"
+)
+
+// MaxCopies は1イベントあたりの最大印刷部数
+const MaxCopies = 5
+
+var (
+	ErrNotFound         = errors.New("rule not found")
+	ErrInvalidEventType = errors.New("invalid event type")
+	ErrInvalidCopies    = fmt.Errorf("copies must be between 1 and %d", MaxCopies)
+	ErrInvalidMinAmount = errors.New("min_amount must be non-negative")
+)
+
+var validEventTypes = map[string]bool{
+	EventAny:        true,
+	EventChat:       true,
+	EventClock:      true,
+	EventCheer:      true,
+	EventFollow:     true,
+	EventRaid:       true,
+	EventShoutout:   true,
+	EventSubscribe:  true,
+	EventGiftSub:    true,
+	EventResub:      true,
+	EventHypeTrain:  true,
+	EventPoll:       true,
+	EventPrediction: true,
+	EventGoal:       true,
+}
+
+// Rule はイベントの印刷先・カラー・部数を決めるルール
+type Rule struct {
+	ID             int64     `json:"id"`
+	Name           string    `json:"name"`
+	EventType      string    `json:"event_type"`
+	MinAmount      int       `json:"min_amount"`      // cheer: bits, raid: viewers, gift_sub: total, resub: months, hype_train: level, poll: votes, prediction: points, goal: target
+	PrinterAddress string    `json:"printer_address"` // 空の場合はPRINTER_ADDRESSを使用
+	Color          bool      `json:"color"`
+	Copies         int       `json:"copies"`
+	Priority       int       `json:"priority"` // 小さいほど先に評価
+	Enabled        bool      `json:"enabled"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Event はルール評価の入力
+type Event struct {
+	Type   string
+	Amount int
+}
+
+// Decision はルール評価の結果
+type Decision struct {
+	PrinterAddress string
+	Color          bool
+	Copies         int
+	RuleID         int64
+}
+
+var (
+	mu          sync.RWMutex
+	cachedRules []*Rule
+	cacheValid  bool
+)
+
+// InitDB creates the print_rules table
+func InitDB() error {
+	db := localdb.GetDB()
+	if db == nil {
+		return errors.New("database not initialized")
+	}
+
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS print_rules (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL DEFAULT '',
+		event_type TEXT NOT NULL DEFAULT '*',
+		min_amount INTEGER NOT NULL DEFAULT 0,
+		printer_address TEXT NOT NULL DEFAULT '',
+		color BOOLEAN NOT NULL DEFAULT false,
+		copies INTEGER NOT NULL DEFAULT 1,
+		priority INTEGER NOT NULL DEFAULT 0,
+		enabled BOOLEAN NOT NULL DEFAULT true,
+		created_at TEXT NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create print_rules table: %w", err)
+	}
+
+	logger.Info("Print rules database initialized")
+	return nil
+}
+
+// Validate checks rule fields and fills defaults
+func (r *Rule) Validate() error {
+	if r.EventType == "" {
+		r.EventType = EventAny
+	}
+	if !validEventTypes[r.EventType] {
+		return ErrInvalidEventType
+	}
+	if r.Copies == 0 {
+		r.Copies = 1
+	}
+	if r.Copies < 1 || r.Copies > MaxCopies {
+		return ErrInvalidCopies
+	}
+	if r.MinAmount < 0 {
+		return ErrInvalidMinAmount
+	}
+	return nil
+}
+
+// GetAllRules returns all rules ordered by priority
+func GetAllRules() ([]*Rule, error) {
+	db := localdb.GetDB()
+	if db == nil {
+		return nil, errors.New("database not initialized")
+	}
+
+	rows, err := db.Query(`SELECT id, name, event_type, min_amount, printer_address, color, copies, priority, enabled, created_at
+		FROM print_rules ORDER BY priority, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []*Rule{}
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			logger.Warn("Failed to scan print rule", zap.Error(err))
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// GetRule returns a rule by ID
+func GetRule(id int64) (*Rule, error) {
+	db := localdb.GetDB()
+	if db == nil {
+		return nil, errors.New("database not initialized")
+	}
+
+	row := db.QueryRow(`SELECT id, name, event_type, min_amount, printer_address, color, copies, priority, enabled, created_at
+		FROM print_rules WHERE id = ?`, id)
+	rule, err := scanRule(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return rule, err
+}
+
+// CreateRule inserts a new rule
+func CreateRule(rule *Rule) (*Rule, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	db := localdb.GetDB()
+	if db == nil {
+		return nil, errors.New("database not initialized")
+	}
+
+	rule.CreatedAt = time.Now()
+	result, err := db.Exec(`INSERT INTO print_rules (name, event_type, min_amount, printer_address, color, copies, priority, enabled, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rule.Name, rule.EventType, rule.MinAmount, rule.PrinterAddress, rule.Color,
+		rule.Copies, rule.Priority, rule.Enabled, rule.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rule: %w", err)
+	}
+
+	rule.ID, _ = result.LastInsertId()
+	invalidateCache()
+
+	logger.Info("Print rule created", zap.Int64("id", rule.ID), zap.String("event_type", rule.EventType))
+	return rule, nil
+}
+
+// UpdateRule replaces an existing rule
+func UpdateRule(id int64, rule *Rule) (*Rule, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := GetRule(id)
+	if err != nil {
+		return nil, err
+	}
+
+	db := localdb.GetDB()
+	_, err = db.Exec(`UPDATE print_rules SET name = ?, event_type = ?, min_amount = ?, printer_address = ?,
+		color = ?, copies = ?, priority = ?, enabled = ? WHERE id = ?`,
+		rule.Name, rule.EventType, rule.MinAmount, rule.PrinterAddress, rule.Color,
+		rule.Copies, rule.Priority, rule.Enabled, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update rule: %w", err)
+	}
+
+	rule.ID = id
+	rule.CreatedAt = existing.CreatedAt
+	invalidateCache()
+
+	logger.Info("Print rule updated", zap.Int64("id", id))
+	return rule, nil
+}
+
+// DeleteRule removes a rule
+func DeleteRule(id int64) error {
+	db := localdb.GetDB()
+	if db == nil {
+		return errors.New("database not initialized")
+	}
+
+	result, err := db.Exec("DELETE FROM print_rules WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete rule: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+
+	invalidateCache()
+
+	logger.Info("Print rule deleted", zap.Int64("id", id))
+	return nil
+}
+
+// Evaluate returns the decision of the first enabled rule matching the event.
+// ルールがない場合はデフォルト（既定プリンター・モノクロ・1部）を返す
+func Evaluate(event Event) Decision {
+	decision := Decision{Copies: 1}
+
+	rules, err := getCachedRules()
+	if err != nil {
+		logger.Debug("Print rules unavailable, using default routing", zap.Error(err))
+		return decision
+	}
+
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		if rule.EventType != EventAny && rule.EventType != event.Type {
+			continue
+		}
+		if event.Amount < rule.MinAmount {
+			continue
+		}
+
+		decision.PrinterAddress = rule.PrinterAddress
+		decision.Color = rule.Color
+		decision.Copies = rule.Copies
+		decision.RuleID = rule.ID
+
+		logger.Debug("Print rule matched",
+			zap.Int64("rule_id", rule.ID),
+			zap.String("event_type", event.Type),
+			zap.Int("amount", event.Amount))
+		break
+	}
+
+	return decision
+}
+
+func getCachedRules() ([]*Rule, error) {
+	mu.RLock()
+	if cacheValid {
+		rules := cachedRules
+		mu.RUnlock()
+		return rules, nil
+	}
+	mu.RUnlock()
+
+	rules, err := GetAllRules()
+	if err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	cachedRules = rules
+	cacheValid = true
+	mu.Unlock()
+
+	return rules, nil
+}
+
+func invalidateCache() {
+	mu.Lock()
+	cacheValid = false
+	cachedRules = nil
+	mu.Unlock()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRule(s scanner) (*Rule, error) {
+	var rule Rule
+	var createdAt string
+	err := s.Scan(
+		&rule.ID,
+		&rule.Name,
+		&rule.EventType,
+		&rule.MinAmount,
+		&rule.PrinterAddress,
+		&rule.Color,
+		&rule.Copies,
+		&rule.Priority,
+		&rule.Enabled,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rule.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return &rule, nil
+}
@@ -0,0 +1,180 @@
+package selftest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/fontmanager"
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
+	"github.com/nantokaworks/twitch-overlay/internal/output"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"github.com/nantokaworks/twitch-overlay/internal/twitchtoken"
+	"go.uber.org/zap"
+)
+
+type CheckStatus string
+
+const (
+	StatusOK      CheckStatus = "ok"
+	StatusWarning CheckStatus = "warning"
+	StatusError   CheckStatus = "error"
+	StatusSkipped CheckStatus = "skipped"
+)
+
+// CheckResult is the outcome of a single self-test check
+type CheckResult struct {
+	Name       string      `json:"name"`
+	Status     CheckStatus `json:"status"`
+	Message    string      `json:"message"`
+	DurationMs int64       `json:"duration_ms"`
+}
+
+// Report is the result of a full self-test run
+type Report struct {
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Passed     bool          `json:"passed"`
+	Checks     []CheckResult `json:"checks"`
+}
+
+var (
+	mu         sync.RWMutex
+	lastReport *Report
+)
+
+type check struct {
+	name string
+	run  func() (CheckStatus, string)
+}
+
+var checks = []check{
+	{"database", checkDatabase},
+	{"font", checkFont},
+	{"timezone", checkTimezone},
+	{"twitch_token", checkToken},
+	{"printer", checkPrinter},
+}
+
+// Run executes all checks, logs the results and stores the report
+func Run() *Report {
+	report := &Report{
+		StartedAt: time.Now(),
+		Passed:    true,
+		Checks:    []CheckResult{},
+	}
+
+	for _, c := range checks {
+		start := time.Now()
+		status, message := c.run()
+		result := CheckResult{
+			Name:       c.name,
+			Status:     status,
+			Message:    message,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		report.Checks = append(report.Checks, result)
+
+		fields := []zap.Field{
+			zap.String("check", c.name),
+			zap.String("status", string(status)),
+			zap.String("message", message),
+		}
+		switch status {
+		case StatusError:
+			report.Passed = false
+			logger.Error("Self-test check failed", fields...)
+		case StatusWarning:
+			logger.Warn("Self-test check warning", fields...)
+		default:
+			logger.Info("Self-test check", fields...)
+		}
+	}
+
+	report.FinishedAt = time.Now()
+
+	mu.Lock()
+	lastReport = report
+	mu.Unlock()
+
+	logger.Info("Self-test completed", zap.Bool("passed", report.Passed))
+	return report
+}
+
+// GetLastReport returns the most recent self-test report (nil if never run)
+func GetLastReport() *Report {
+	mu.RLock()
+	defer mu.RUnlock()
+	return lastReport
+}
+
+func checkDatabase() (CheckStatus, string) {
+	db := localdb.GetDB()
+	if db == nil {
+		return StatusError, "database not initialized"
+	}
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS selftest (
+		id INTEGER PRIMARY KEY,
+		checked_at TEXT NOT NULL
+	)`); err != nil {
+		return StatusError, fmt.Sprintf("database not writable: %v", err)
+	}
+
+	if _, err := db.Exec(`INSERT OR REPLACE INTO selftest (id, checked_at) VALUES (1, ?)`,
+		time.Now().Format(time.RFC3339)); err != nil {
+		return StatusError, fmt.Sprintf("database not writable: %v", err)
+	}
+
+	return StatusOK, "database is writable"
+}
+
+func checkFont() (CheckStatus, string) {
+	if err := fontmanager.ValidateCurrentFont(); err != nil {
+		if errors.Is(err, fontmanager.ErrNoCustomFont) {
+			if fontmanager.SystemFontAvailable() {
+				return StatusWarning, "no font uploaded, using system font (ALLOW_SYSTEM_FONT)"
+			}
+			return StatusError, "no font uploaded"
+		}
+		return StatusError, err.Error()
+	}
+	return StatusOK, "font is parseable"
+}
+
+func checkTimezone() (CheckStatus, string) {
+	if env.Value.TimeZone == "" {
+		return StatusWarning, "timezone not set, using system local time"
+	}
+	if _, err := time.LoadLocation(env.Value.TimeZone); err != nil {
+		return StatusError, fmt.Sprintf("failed to load timezone %s: %v", env.Value.TimeZone, err)
+	}
+	return StatusOK, fmt.Sprintf("timezone %s loaded", env.Value.TimeZone)
+}
+
+func checkToken() (CheckStatus, string) {
+	token, isValid, err := twitchtoken.GetLatestToken()
+	if err != nil {
+		return StatusError, "no Twitch token found, please authorize the app"
+	}
+	if !isValid {
+		return StatusWarning, "Twitch token expired (will be refreshed automatically)"
+	}
+	expiresIn := time.Until(time.Unix(token.ExpiresAt, 0)).Round(time.Minute)
+	return StatusOK, fmt.Sprintf("Twitch token valid (expires in %s)", expiresIn)
+}
+
+func checkPrinter() (CheckStatus, string) {
+	if env.Value.PrinterAddress == nil || *env.Value.PrinterAddress == "" {
+		return StatusSkipped, "printer address not configured"
+	}
+	if output.IsDryRunActive() {
+		return StatusSkipped, "dry-run mode is active"
+	}
+	if err := output.CheckPrinterReachable(); err != nil {
+		return StatusError, fmt.Sprintf("printer not reachable: %v", err)
+	}
+	return StatusOK, fmt.Sprintf("printer %s reachable", *env.Value.PrinterAddress)
+}
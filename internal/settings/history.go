@@ -0,0 +1,95 @@
+package settings
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// maskedSecretValue は履歴に記録するシークレットの値（実際の値は残さない）
+const maskedSecretValue = "********"
+
+// settingsHistoryMax は履歴に残す件数の上限
+const settingsHistoryMax = 5000
+
+// SettingChange is a recorded settings change
+type SettingChange struct {
+	ID        int64     `json:"id"`
+	Key       string    `json:"key"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// dbExecutor は *sql.DB と *sql.Tx の共通部分
+type dbExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// currentValue returns the stored value of key, or its default when not stored yet
+func currentValue(db dbExecutor, key string) string {
+	var value string
+	if err := db.QueryRow("SELECT value FROM settings WHERE key = ?", key).Scan(&value); err != nil {
+		return DefaultSettings[key].Value
+	}
+	return value
+}
+
+// recordChange appends a change to settings_history (値が変わっていない場合は記録しない)
+func recordChange(db dbExecutor, key, oldValue, newValue string) error {
+	if oldValue == newValue {
+		return nil
+	}
+	if DefaultSettings[key].Type == SettingTypeSecret {
+		oldValue = maskSecret(oldValue)
+		newValue = maskSecret(newValue)
+	}
+
+	if _, err := db.Exec(`INSERT INTO settings_history (key, old_value, new_value) VALUES (?, ?, ?)`,
+		key, oldValue, newValue); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`DELETE FROM settings_history WHERE id <= (SELECT MAX(id) FROM settings_history) - ?`,
+		settingsHistoryMax); err != nil {
+		logger.Warn("Failed to prune settings history", zap.Error(err))
+	}
+	return nil
+}
+
+func maskSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return maskedSecretValue
+}
+
+// GetHistory returns recorded settings changes, newest first. key が空の場合は全ての設定
+func (sm *SettingsManager) GetHistory(key string, limit int) ([]SettingChange, error) {
+	query := `SELECT id, key, old_value, new_value, changed_at FROM settings_history`
+	args := []interface{}{}
+	if key != "" {
+		query += ` WHERE key = ?`
+		args = append(args, key)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := sm.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []SettingChange{}
+	for rows.Next() {
+		var c SettingChange
+		if err := rows.Scan(&c.ID, &c.Key, &c.OldValue, &c.NewValue, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
@@ -3,12 +3,16 @@ package settings
 import (
 	"database/sql"
 	"fmt"
+	"net/url"
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/nantokaworks/twitch-overlay/internal/clockschedule"
 	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	printerstatus "github.com/nantokaworks/twitch-overlay/internal/status"
 	"go.uber.org/zap"
 )
 
@@ -86,6 +90,10 @@ var DefaultSettings = map[string]Setting{
 		Key: "ROTATE_PRINT", Value: "true", Type: SettingTypeNormal, Required: false,
 		Description: "Rotate print output 180 degrees",
 	},
+	"COLOR_PRINT": {
+		Key: "COLOR_PRINT", Value: "false", Type: SettingTypeNormal, Required: false,
+		Description: "Send color images to the printer (falls back to mono if unsupported)",
+	},
 
 	// 動作設定
 	"KEEP_ALIVE_INTERVAL": {
@@ -124,6 +132,270 @@ var DefaultSettings = map[string]Setting{
 		Key: "AUTO_DRY_RUN_WHEN_OFFLINE", Value: "false", Type: SettingTypeNormal, Required: false,
 		Description: "Automatically enable dry-run mode when stream is offline",
 	},
+	"FAX_THEME": {
+		Key: "FAX_THEME", Value: "plain", Type: SettingTypeNormal, Required: false,
+		Description: "Decorative theme for faxes (plain, dotted, rounded, seasonal)",
+	},
+	"LOCALE": {
+		Key: "LOCALE", Value: "ja-JP", Type: SettingTypeNormal, Required: false,
+		Description: "Locale for number formatting on printed output (e.g. ja-JP, en-US, de-DE)",
+	},
+	"EMOTE_TRANSPARENCY": {
+		Key: "EMOTE_TRANSPARENCY", Value: "over", Type: SettingTypeNormal, Required: false,
+		Description: "How emote transparency is printed (over, flatten, paper)",
+	},
+	"CLOCK_CONDENSED": {
+		Key: "CLOCK_CONDENSED", Value: "false", Type: SettingTypeNormal, Required: false,
+		Description: "Print a compact clock without avatar (one line per leaderboard place)",
+	},
+	"FAX_SEPARATOR": {
+		Key: "FAX_SEPARATOR", Value: "none", Type: SettingTypeNormal, Required: false,
+		Description: "Separator printed between back-to-back faxes (feed, cut, line, none)",
+	},
+	"EMOTE_CACHE_MAX_WRITERS": {
+		Key: "EMOTE_CACHE_MAX_WRITERS", Value: "2", Type: SettingTypeNormal, Required: false,
+		Description: "Maximum number of concurrent emote cache file writes",
+	},
+	"ALLOW_SYSTEM_FONT": {
+		Key: "ALLOW_SYSTEM_FONT", Value: "false", Type: SettingTypeNormal, Required: false,
+		Description: "Fall back to an OS font when no custom font is uploaded",
+	},
+	"SINGLE_CHAR_SCALE": {
+		Key: "SINGLE_CHAR_SCALE", Value: "true", Type: SettingTypeNormal, Required: false,
+		Description: "Scale single-character messages up to the full paper width",
+	},
+	"SINGLE_CHAR_MAX_SCALE": {
+		Key: "SINGLE_CHAR_MAX_SCALE", Value: "4", Type: SettingTypeNormal, Required: false,
+		Description: "Maximum scale factor for single-character messages (1-20)",
+	},
+	"FAX_ALLOWED_USERS": {
+		Key: "FAX_ALLOWED_USERS", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Comma-separated list of users allowed to send faxes (empty = everyone)",
+	},
+	"MUSIC_MAX_FILE_MB": {
+		Key: "MUSIC_MAX_FILE_MB", Value: "50", Type: SettingTypeNormal, Required: false,
+		Description: "Maximum music upload size (MB)",
+	},
+	"MUSIC_ALLOWED_FORMATS": {
+		Key: "MUSIC_ALLOWED_FORMATS", Value: "mp3,wav,m4a,ogg,flac", Type: SettingTypeNormal, Required: false,
+		Description: "Comma-separated list of allowed music upload formats",
+	},
+	"MUSIC_SERVER_SEQUENCING": {
+		Key: "MUSIC_SERVER_SEQUENCING", Value: "false", Type: SettingTypeNormal, Required: false,
+		Description: "Advance the playlist on the server even when no overlay is open",
+	},
+	"PAPER_WIDTH": {
+		Key: "PAPER_WIDTH", Value: "384", Type: SettingTypeNormal, Required: false,
+		Description: "Printable paper width in pixels (58mm: 384, 80mm: 576)",
+	},
+	"FONT_SIZE": {
+		Key: "FONT_SIZE", Value: "32", Type: SettingTypeNormal, Required: false,
+		Description: "Fax font size in points (clock fonts scale with it)",
+	},
+	"EMOTE_CACHE_MAX_MB": {
+		Key: "EMOTE_CACHE_MAX_MB", Value: "100", Type: SettingTypeNormal, Required: false,
+		Description: "Maximum emote cache size in MB (0 = unlimited)",
+	},
+	"EMOTE_CACHE_TTL_HOURS": {
+		Key: "EMOTE_CACHE_TTL_HOURS", Value: "168", Type: SettingTypeNormal, Required: false,
+		Description: "Emote cache lifetime in hours (0 = never expire)",
+	},
+	"MUSIC_TRANSCODE": {
+		Key: "MUSIC_TRANSCODE", Value: "false", Type: SettingTypeNormal, Required: false,
+		Description: "Transcode uploads to browser-friendly MP3 (requires ffmpeg)",
+	},
+	"FFMPEG_PATH": {
+		Key: "FFMPEG_PATH", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Path to the ffmpeg executable (empty = search PATH)",
+	},
+	"THIRD_PARTY_EMOTES": {
+		Key: "THIRD_PARTY_EMOTES", Value: "false", Type: SettingTypeNormal, Required: false,
+		Description: "Print BTTV/FFZ/7TV emotes as images",
+	},
+	"UNDERLINE_STYLE": {
+		Key: "UNDERLINE_STYLE", Value: "dashed", Type: SettingTypeNormal, Required: false,
+		Description: "Style of the line at the bottom of faxes (solid/dashed/none)",
+	},
+	"UNDERLINE_THICKNESS": {
+		Key: "UNDERLINE_THICKNESS", Value: "4", Type: SettingTypeNormal, Required: false,
+		Description: "Thickness of the bottom line in pixels",
+	},
+	"UNDERLINE_DASH_LENGTH": {
+		Key: "UNDERLINE_DASH_LENGTH", Value: "8", Type: SettingTypeNormal, Required: false,
+		Description: "Dash length of the dashed line in pixels",
+	},
+	"UNDERLINE_DASH_GAP": {
+		Key: "UNDERLINE_DASH_GAP", Value: "4", Type: SettingTypeNormal, Required: false,
+		Description: "Gap between dashes of the dashed line in pixels",
+	},
+	"QR_RECOVERY_LEVEL": {
+		Key: "QR_RECOVERY_LEVEL", Value: "medium", Type: SettingTypeNormal, Required: false,
+		Description: "QR code error correction level (low/medium/high/highest)",
+	},
+	"QR_MAX_SIZE": {
+		Key: "QR_MAX_SIZE", Value: "0", Type: SettingTypeNormal, Required: false,
+		Description: "Maximum QR code size in pixels (0 = paper width)",
+	},
+	"QR_ONLY": {
+		Key: "QR_ONLY", Value: "false", Type: SettingTypeNormal, Required: false,
+		Description: "Print only a QR code for URLs instead of fetching images",
+	},
+	"PRINT_URL_MODE": {
+		Key: "PRINT_URL_MODE", Value: "image_qr", Type: SettingTypeNormal, Required: false,
+		Description: "How URLs in chat are printed (image_qr/qr_only/text_only/text_qr)",
+	},
+	"PRINT_BEEP": {
+		Key: "PRINT_BEEP", Value: "false", Type: SettingTypeNormal, Required: false,
+		Description: "Beep the printer after each successful print (no-op if the printer has no beep command)",
+	},
+	"PRINT_FOLLOW_ENABLED": {
+		Key: "PRINT_FOLLOW_ENABLED", Value: "true", Type: SettingTypeNormal, Required: false,
+		Description: "Print follows",
+	},
+	"PRINT_CHEER_ENABLED": {
+		Key: "PRINT_CHEER_ENABLED", Value: "true", Type: SettingTypeNormal, Required: false,
+		Description: "Print Cheers (bits)",
+	},
+	"PRINT_RAID_ENABLED": {
+		Key: "PRINT_RAID_ENABLED", Value: "true", Type: SettingTypeNormal, Required: false,
+		Description: "Print raids",
+	},
+	"PRINT_SHOUTOUT_ENABLED": {
+		Key: "PRINT_SHOUTOUT_ENABLED", Value: "true", Type: SettingTypeNormal, Required: false,
+		Description: "Print shoutouts",
+	},
+	"PRINT_SUBSCRIBE_ENABLED": {
+		Key: "PRINT_SUBSCRIBE_ENABLED", Value: "true", Type: SettingTypeNormal, Required: false,
+		Description: "Print subscriptions",
+	},
+	"PRINT_GIFT_SUB_ENABLED": {
+		Key: "PRINT_GIFT_SUB_ENABLED", Value: "true", Type: SettingTypeNormal, Required: false,
+		Description: "Print gift subscriptions",
+	},
+	"PRINT_RESUB_ENABLED": {
+		Key: "PRINT_RESUB_ENABLED", Value: "true", Type: SettingTypeNormal, Required: false,
+		Description: "Print resubscription messages",
+	},
+	"CHEER_MIN_BITS": {
+		Key: "CHEER_MIN_BITS", Value: "0", Type: SettingTypeNormal, Required: false,
+		Description: "Do not print Cheers below this many bits (0 = print all)",
+	},
+	"TEMPLATE_CHEER_TITLE": {
+		Key: "TEMPLATE_CHEER_TITLE", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Title template for Cheer faxes ({user} {bits}, empty = default)",
+	},
+	"TEMPLATE_CHEER_DETAIL": {
+		Key: "TEMPLATE_CHEER_DETAIL", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Detail line template for Cheer faxes ({user} {bits}, empty = default)",
+	},
+	"TEMPLATE_FOLLOW_TITLE": {
+		Key: "TEMPLATE_FOLLOW_TITLE", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Title template for follow faxes ({user}, empty = default)",
+	},
+	"TEMPLATE_FOLLOW_DETAIL": {
+		Key: "TEMPLATE_FOLLOW_DETAIL", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Detail line template for follow faxes ({user}, empty = default)",
+	},
+	"TEMPLATE_RAID_TITLE": {
+		Key: "TEMPLATE_RAID_TITLE", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Title template for raid faxes ({user} {viewers}, empty = default)",
+	},
+	"TEMPLATE_RAID_DETAIL": {
+		Key: "TEMPLATE_RAID_DETAIL", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Detail line template for raid faxes ({user} {viewers}, empty = default)",
+	},
+	"TEMPLATE_SHOUTOUT_TITLE": {
+		Key: "TEMPLATE_SHOUTOUT_TITLE", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Title template for shoutout faxes ({user} {viewers}, empty = default)",
+	},
+	"TEMPLATE_SHOUTOUT_DETAIL": {
+		Key: "TEMPLATE_SHOUTOUT_DETAIL", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Detail line template for shoutout faxes ({user} {viewers}, empty = default)",
+	},
+	"TEMPLATE_SUBSCRIBE_TITLE": {
+		Key: "TEMPLATE_SUBSCRIBE_TITLE", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Title template for subscription faxes ({user} {tier}, empty = default)",
+	},
+	"TEMPLATE_SUBSCRIBE_DETAIL": {
+		Key: "TEMPLATE_SUBSCRIBE_DETAIL", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Detail line template for subscription faxes ({user} {tier}, empty = default)",
+	},
+	"TEMPLATE_GIFT_SUB_TITLE": {
+		Key: "TEMPLATE_GIFT_SUB_TITLE", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Title template for gift subscription faxes ({user} {tier} {count}, empty = default)",
+	},
+	"TEMPLATE_GIFT_SUB_DETAIL": {
+		Key: "TEMPLATE_GIFT_SUB_DETAIL", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Detail line template for gift subscription faxes ({user} {tier} {count}, empty = default)",
+	},
+	"TEMPLATE_RESUB_TITLE": {
+		Key: "TEMPLATE_RESUB_TITLE", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Title template for resubscription faxes ({user} {tier} {months}, empty = default)",
+	},
+	"TEMPLATE_RESUB_DETAIL": {
+		Key: "TEMPLATE_RESUB_DETAIL", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Detail line template for resubscription faxes ({user} {tier} {months}, empty = default)",
+	},
+	"PRINT_QUEUE_FULL_POLICY": {
+		Key: "PRINT_QUEUE_FULL_POLICY", Value: "drop_newest", Type: SettingTypeNormal, Required: false,
+		Description: "Behavior when the print queue is full (drop_oldest/drop_newest/block)",
+	},
+	"PRINT_QUEUE_BLOCK_TIMEOUT": {
+		Key: "PRINT_QUEUE_BLOCK_TIMEOUT", Value: "10", Type: SettingTypeNormal, Required: false,
+		Description: "Maximum seconds to wait for space with the block policy",
+	},
+	"PRINT_HYPE_TRAIN_ENABLED": {
+		Key: "PRINT_HYPE_TRAIN_ENABLED", Value: "true", Type: SettingTypeNormal, Required: false,
+		Description: "Print Hype Train start and end",
+	},
+	"PRINT_POLL_ENABLED": {
+		Key: "PRINT_POLL_ENABLED", Value: "true", Type: SettingTypeNormal, Required: false,
+		Description: "Print poll start and results",
+	},
+	"PRINT_PREDICTION_ENABLED": {
+		Key: "PRINT_PREDICTION_ENABLED", Value: "true", Type: SettingTypeNormal, Required: false,
+		Description: "Print prediction start and results",
+	},
+	"PRINT_GOAL_ENABLED": {
+		Key: "PRINT_GOAL_ENABLED", Value: "true", Type: SettingTypeNormal, Required: false,
+		Description: "Print when a goal is achieved",
+	},
+	"API_TOKEN": {
+		Key: "API_TOKEN", Value: "", Type: SettingTypeSecret, Required: false,
+		Description: "Token required for mutating API requests (empty = no authentication)",
+	},
+	"ALLOWED_ORIGINS": {
+		Key: "ALLOWED_ORIGINS", Value: "*", Type: SettingTypeNormal, Required: false,
+		Description: "Comma-separated list of origins allowed by CORS (* = any)",
+	},
+	"DEBUG_MODE": {
+		Key: "DEBUG_MODE", Value: "false", Type: SettingTypeNormal, Required: false,
+		Description: "Enable debug endpoints (/debug/*, /api/debug/*)",
+	},
+	"LOG_LEVEL": {
+		Key: "LOG_LEVEL", Value: "info", Type: SettingTypeNormal, Required: false,
+		Description: "Log level (debug/info/warn/error)",
+	},
+	"CLOCK_SCHEDULE": {
+		Key: "CLOCK_SCHEDULE", Value: "60", Type: SettingTypeNormal, Required: false,
+		Description: "Clock print schedule: interval in minutes (60 = on the hour) or 5-field cron spec",
+	},
+	"QUIET_HOURS_START": {
+		Key: "QUIET_HOURS_START", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "Start of quiet hours (HH:MM, no printing until QUIET_HOURS_END)",
+	},
+	"QUIET_HOURS_END": {
+		Key: "QUIET_HOURS_END", Value: "", Type: SettingTypeNormal, Required: false,
+		Description: "End of quiet hours (HH:MM)",
+	},
+	"LEADERBOARD_SIZE": {
+		Key: "LEADERBOARD_SIZE", Value: "5", Type: SettingTypeNormal, Required: false,
+		Description: "Number of Cheer leaders shown on the clock and leaderboard prints (1-100)",
+	},
+	"LEADERBOARD_PERIOD": {
+		Key: "LEADERBOARD_PERIOD", Value: "month", Type: SettingTypeNormal, Required: false,
+		Description: "Cheer leaderboard period (day, week, month, year, all)",
+	},
 	
 	// フォント設定
 	"FONT_FILENAME": {
@@ -166,8 +438,8 @@ func (sm *SettingsManager) CheckFeatureStatus() (*FeatureStatus, error) {
 		status.PrinterConfigured = false
 	} else {
 		status.PrinterConfigured = true
-		// TODO: 実際の接続テストを実装
-		status.PrinterConnected = false
+		// 接続中、または直近の疎通確認（/api/settings/printer/validate）でスキャンに見えていれば到達可能とみなす
+		status.PrinterConnected = printerstatus.IsPrinterConnected() || printerstatus.IsPrinterReachable(printerAddr)
 	}
 
 	// 警告チェック
@@ -194,12 +466,28 @@ func (sm *SettingsManager) GetSetting(key string) (string, error) {
 
 func (sm *SettingsManager) SetSetting(key, value string) error {
 	// デフォルト設定が存在するかチェック
-	defaultSetting, exists := DefaultSettings[key]
-	if !exists {
+	if _, exists := DefaultSettings[key]; !exists {
 		return fmt.Errorf("unknown setting key: %s", key)
 	}
 
-	_, err := sm.db.Exec(`
+	tx, err := sm.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := setSettingTx(tx, key, value); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// setSettingTx upserts a setting and records the change in settings_history
+func setSettingTx(tx *sql.Tx, key, value string) error {
+	defaultSetting := DefaultSettings[key]
+	oldValue := currentValue(tx, key)
+
+	_, err := tx.Exec(`
 		INSERT INTO settings (key, value, setting_type, is_required, description) 
 		VALUES (?, ?, ?, ?, ?) 
 		ON CONFLICT(key) DO UPDATE SET 
@@ -210,7 +498,30 @@ func (sm *SettingsManager) SetSetting(key, value string) error {
 		defaultSetting.Required,
 		defaultSetting.Description,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return recordChange(tx, key, oldValue, value)
+}
+
+// SetSettings updates several settings in one transaction (一つでも失敗したら何も変更しない)
+func (sm *SettingsManager) SetSettings(values map[string]string) error {
+	tx, err := sm.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for key, value := range values {
+		if _, exists := DefaultSettings[key]; !exists {
+			return fmt.Errorf("unknown setting key: %s", key)
+		}
+		if err := setSettingTx(tx, key, value); err != nil {
+			return fmt.Errorf("failed to update %s: %w", key, err)
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (sm *SettingsManager) GetAllSettings() (map[string]Setting, error) {
@@ -358,7 +669,151 @@ func ValidateSetting(key, value string) error {
 				return fmt.Errorf("must be an integer between 0 and 9999999")
 			}
 		}
-	case "DRY_RUN_MODE", "BEST_QUALITY", "DITHER", "AUTO_ROTATE", "ROTATE_PRINT", "KEEP_ALIVE_ENABLED", "CLOCK_ENABLED", "CLOCK_SHOW_ICONS", "DEBUG_OUTPUT":
+	case "FAX_THEME":
+		switch value {
+		case "plain", "dotted", "rounded", "seasonal":
+		default:
+			return fmt.Errorf("must be one of: plain, dotted, rounded, seasonal")
+		}
+	case "QUIET_HOURS_START", "QUIET_HOURS_END":
+		// 空なら無効
+		if value != "" {
+			if _, err := time.Parse("15:04", value); err != nil || len(value) != 5 {
+				return fmt.Errorf("must be a time in HH:MM format (e.g. 01:00)")
+			}
+		}
+	case "LEADERBOARD_SIZE":
+		// Twitch API の count の上限は100
+		if val, err := strconv.Atoi(value); err != nil || val < 1 || val > 100 {
+			return fmt.Errorf("must be an integer between 1 and 100")
+		}
+	case "LEADERBOARD_PERIOD":
+		switch value {
+		case "day", "week", "month", "year", "all":
+		default:
+			return fmt.Errorf("must be one of: day, week, month, year, all")
+		}
+	case "CLOCK_SCHEDULE":
+		if _, err := clockschedule.Parse(value); err != nil {
+			return fmt.Errorf("invalid clock schedule: %v", err)
+		}
+	case "LOG_LEVEL":
+		switch strings.ToLower(value) {
+		case "debug", "info", "warn", "error":
+		default:
+			return fmt.Errorf("must be one of: debug, info, warn, error")
+		}
+	case "LOCALE":
+		if value != "" {
+			if matched, _ := regexp.MatchString(`^[A-Za-z]{2,3}([-_][A-Za-z0-9]{2,8})*$`, value); !matched {
+				return fmt.Errorf("invalid locale (expected e.g. ja-JP or en-US)")
+			}
+		}
+	case "EMOTE_TRANSPARENCY":
+		switch value {
+		case "over", "flatten", "paper":
+		default:
+			return fmt.Errorf("must be one of: over, flatten, paper")
+		}
+	case "FAX_SEPARATOR":
+		switch value {
+		case "feed", "cut", "line", "none":
+		default:
+			return fmt.Errorf("must be one of: feed, cut, line, none")
+		}
+	case "EMOTE_CACHE_MAX_WRITERS":
+		if val, err := strconv.Atoi(value); err != nil || val < 1 || val > 16 {
+			return fmt.Errorf("must be integer between 1 and 16")
+		}
+	case "SINGLE_CHAR_MAX_SCALE":
+		if val, err := strconv.ParseFloat(value, 64); err != nil || val < 1 || val > 20 {
+			return fmt.Errorf("must be a number between 1 and 20")
+		}
+	case "MUSIC_MAX_FILE_MB":
+		if val, err := strconv.Atoi(value); err != nil || val < 1 || val > 2048 {
+			return fmt.Errorf("must be integer between 1 and 2048")
+		}
+	case "MUSIC_ALLOWED_FORMATS":
+		for _, ext := range strings.Split(value, ",") {
+			switch strings.ToLower(strings.TrimSpace(ext)) {
+			case "mp3", "wav", "m4a", "ogg", "flac":
+			default:
+				return fmt.Errorf("unsupported format %q (supported: mp3, wav, m4a, ogg, flac)", ext)
+			}
+		}
+	case "PAPER_WIDTH":
+		if val, err := strconv.Atoi(value); err != nil || val < 200 || val > 1200 {
+			return fmt.Errorf("must be integer between 200 and 1200")
+		}
+	case "FONT_SIZE":
+		if val, err := strconv.Atoi(value); err != nil || val < 12 || val > 96 {
+			return fmt.Errorf("must be integer between 12 and 96")
+		}
+	case "EMOTE_CACHE_MAX_MB":
+		if val, err := strconv.Atoi(value); err != nil || val < 0 || val > 10240 {
+			return fmt.Errorf("must be integer between 0 and 10240")
+		}
+	case "EMOTE_CACHE_TTL_HOURS":
+		if val, err := strconv.Atoi(value); err != nil || val < 0 || val > 8760 {
+			return fmt.Errorf("must be integer between 0 and 8760")
+		}
+	case "UNDERLINE_STYLE":
+		switch value {
+		case "solid", "dashed", "none":
+		default:
+			return fmt.Errorf("must be one of: solid, dashed, none")
+		}
+	case "UNDERLINE_THICKNESS":
+		if val, err := strconv.Atoi(value); err != nil || val < 1 || val > 32 {
+			return fmt.Errorf("must be integer between 1 and 32")
+		}
+	case "UNDERLINE_DASH_LENGTH", "UNDERLINE_DASH_GAP":
+		if val, err := strconv.Atoi(value); err != nil || val < 1 || val > 100 {
+			return fmt.Errorf("must be integer between 1 and 100")
+		}
+	case "QR_RECOVERY_LEVEL":
+		switch value {
+		case "low", "medium", "high", "highest":
+		default:
+			return fmt.Errorf("must be one of: low, medium, high, highest")
+		}
+	case "QR_MAX_SIZE":
+		if val, err := strconv.Atoi(value); err != nil || val < 0 || val > 1200 {
+			return fmt.Errorf("must be integer between 0 and 1200")
+		}
+	case "PRINT_URL_MODE":
+		switch value {
+		case "image_qr", "qr_only", "text_only", "text_qr":
+		default:
+			return fmt.Errorf("must be one of: image_qr, qr_only, text_only, text_qr")
+		}
+	case "CHEER_MIN_BITS":
+		if val, err := strconv.Atoi(value); err != nil || val < 0 {
+			return fmt.Errorf("must be a non-negative integer")
+		}
+	case "ALLOWED_ORIGINS":
+		// "*" またはカンマ区切りのオリジン（scheme://host[:port]）
+		for _, origin := range strings.Split(value, ",") {
+			origin = strings.TrimSpace(origin)
+			if origin == "*" {
+				continue
+			}
+			u, err := url.Parse(origin)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+				return fmt.Errorf("invalid origin %q (expected * or scheme://host[:port])", origin)
+			}
+		}
+	case "PRINT_QUEUE_FULL_POLICY":
+		switch value {
+		case "drop_oldest", "drop_newest", "block":
+		default:
+			return fmt.Errorf("must be one of: drop_oldest, drop_newest, block")
+		}
+	case "PRINT_QUEUE_BLOCK_TIMEOUT":
+		if val, err := strconv.Atoi(value); err != nil || val < 1 || val > 300 {
+			return fmt.Errorf("must be integer between 1 and 300")
+		}
+	case "DRY_RUN_MODE", "AUTO_DRY_RUN_WHEN_OFFLINE", "BEST_QUALITY", "DITHER", "AUTO_ROTATE", "ROTATE_PRINT", "COLOR_PRINT", "KEEP_ALIVE_ENABLED", "CLOCK_ENABLED", "CLOCK_SHOW_ICONS", "DEBUG_OUTPUT", "CLOCK_CONDENSED", "ALLOW_SYSTEM_FONT", "SINGLE_CHAR_SCALE", "MUSIC_SERVER_SEQUENCING", "MUSIC_TRANSCODE", "THIRD_PARTY_EMOTES", "QR_ONLY", "PRINT_BEEP", "PRINT_FOLLOW_ENABLED", "PRINT_CHEER_ENABLED", "PRINT_RAID_ENABLED", "PRINT_SHOUTOUT_ENABLED", "PRINT_SUBSCRIBE_ENABLED", "PRINT_GIFT_SUB_ENABLED", "PRINT_RESUB_ENABLED", "PRINT_HYPE_TRAIN_ENABLED", "PRINT_POLL_ENABLED", "PRINT_PREDICTION_ENABLED", "PRINT_GOAL_ENABLED", "DEBUG_MODE":
 		// boolean値のチェック
 		if value != "true" && value != "false" {
 			return fmt.Errorf("must be 'true' or 'false'")
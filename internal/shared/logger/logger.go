@@ -2,6 +2,7 @@ package logger
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
 	"strings"
@@ -16,6 +17,9 @@ var Log *zap.Logger
 
 var once sync.Once
 
+// level は実行中に変更できるログレベル（stdout とログバッファの両方に効く）
+var level zap.AtomicLevel
+
 func init() {
 
 	once.Do(func() {
@@ -25,7 +29,8 @@ func init() {
 		config.ErrorOutputPaths = []string{"stdout"}
 
 		// 表示するログレベルを設定
-		config.Level = zap.NewAtomicLevelAt(getZapLogLevel())
+		level = zap.NewAtomicLevelAt(getZapLogLevel())
+		config.Level = level
 
 		// カスタムコアを作成してログバッファに追加
 		encoderConfig := config.EncoderConfig
@@ -100,6 +105,37 @@ func Sync() error {
 	return Log.Sync()
 }
 
+// SetLevel changes the log level at runtime (debug/info/warn/error)
+func SetLevel(levelStr string) error {
+	l, err := ParseLevel(levelStr)
+	if err != nil {
+		return err
+	}
+	level.SetLevel(l)
+	return nil
+}
+
+// GetLevel returns the current log level
+func GetLevel() string {
+	return level.Level().String()
+}
+
+// ParseLevel parses a LOG_LEVEL value
+func ParseLevel(levelStr string) (zapcore.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(levelStr)) {
+	case "debug":
+		return zap.DebugLevel, nil
+	case "info", "":
+		return zap.InfoLevel, nil
+	case "warn", "warning":
+		return zap.WarnLevel, nil
+	case "error":
+		return zap.ErrorLevel, nil
+	default:
+		return zap.InfoLevel, fmt.Errorf("unknown log level: %s", levelStr)
+	}
+}
+
 func getZapLogLevel() zapcore.Level {
 	levelStr := strings.ToLower(os.Getenv("LOG_LEVEL"))
 	switch levelStr {
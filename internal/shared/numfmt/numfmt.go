@@ -0,0 +1,57 @@
+// Package numfmt formats numbers for printed output.
+package numfmt
+
+import (
+	"strconv"
+	"strings"
+)
+
+// separators は言語ごとの桁区切り文字（未登録の言語は ","）
+// 感熱紙用フォントに無いことが多いため、狭いノーブレークスペースの代わりに通常のスペースを使う
+var separators = map[string]string{
+	"de": ".", "es": ".", "it": ".", "nl": ".", "pt": ".", "id": ".", "tr": ".", "da": ".",
+	"fr": " ", "ru": " ", "pl": " ", "sv": " ", "cs": " ", "fi": " ", "nb": " ", "uk": " ",
+}
+
+// Int formats n with the thousands separator of locale (e.g. "ja-JP" → 123,456)
+func Int(n int, locale string) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	if len(digits) <= 3 {
+		return sign + digits
+	}
+
+	sep := separator(locale)
+	var b strings.Builder
+	b.WriteString(sign)
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+func separator(locale string) string {
+	// スイスのドイツ語圏などはアポストロフィ
+	switch strings.ToLower(strings.ReplaceAll(locale, "_", "-")) {
+	case "de-ch", "it-ch", "fr-ch":
+		return "'"
+	}
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	if sep, ok := separators[lang]; ok {
+		return sep
+	}
+	return ","
+}
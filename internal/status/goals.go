@@ -0,0 +1,69 @@
+package status
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/broadcast"
+)
+
+// Goal is the progress of an active creator goal (channel.goal.*)
+type Goal struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"` // follow, subscription, new_bit など
+	Description   string    `json:"description"`
+	CurrentAmount int       `json:"current_amount"`
+	TargetAmount  int       `json:"target_amount"`
+	StartedAt     time.Time `json:"started_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+var (
+	goalsMu sync.RWMutex
+	goals   = make(map[string]Goal)
+)
+
+// UpdateGoal records the latest progress of a goal and notifies the frontend
+func UpdateGoal(g Goal) {
+	g.UpdatedAt = time.Now()
+
+	goalsMu.Lock()
+	goals[g.ID] = g
+	goalsMu.Unlock()
+
+	broadcast.Send(map[string]interface{}{
+		"type": "goal_progress",
+		"data": g,
+	})
+}
+
+// EndGoal removes a finished goal
+func EndGoal(id string, achieved bool) {
+	goalsMu.Lock()
+	delete(goals, id)
+	goalsMu.Unlock()
+
+	broadcast.Send(map[string]interface{}{
+		"type": "goal_end",
+		"data": map[string]interface{}{
+			"id":       id,
+			"achieved": achieved,
+		},
+	})
+}
+
+// GetGoals returns the active goals, oldest first
+func GetGoals() []Goal {
+	goalsMu.RLock()
+	defer goalsMu.RUnlock()
+
+	list := make([]Goal, 0, len(goals))
+	for _, g := range goals {
+		list = append(list, g)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].StartedAt.Before(list[j].StartedAt)
+	})
+	return list
+}
@@ -1,6 +1,7 @@
 package status
 
 import (
+	"strings"
 	"sync"
 	"github.com/nantokaworks/twitch-overlay/internal/broadcast"
 )
@@ -8,6 +9,7 @@ import (
 var (
 	mu                sync.RWMutex
 	printerConnected  bool
+	reachableAddress  string // 直近の疎通確認でスキャンに見えたプリンターのアドレス
 )
 
 // SetPrinterConnected sets the printer connection status
@@ -38,4 +40,22 @@ func IsPrinterConnected() bool {
 	mu.RLock()
 	defer mu.RUnlock()
 	return printerConnected
-}
\ No newline at end of file
+}
+
+// SetPrinterReachable records the result of a reachability check (BLE scan) for the given address
+func SetPrinterReachable(address string, reachable bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	if reachable {
+		reachableAddress = strings.ToUpper(address)
+	} else if strings.EqualFold(reachableAddress, address) {
+		reachableAddress = ""
+	}
+}
+
+// IsPrinterReachable returns whether the last reachability check found the given address
+func IsPrinterReachable(address string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return address != "" && strings.EqualFold(reachableAddress, address)
+}
@@ -11,6 +11,17 @@ type StreamStatus struct {
 	StartedAt   *time.Time `json:"started_at,omitempty"`
 	ViewerCount int        `json:"viewer_count"`
 	LastChecked time.Time  `json:"last_checked"`
+	// UptimeSeconds はサーバー側で計算した配信経過時間（GetStreamStatus/コールバック時に設定）
+	UptimeSeconds int64 `json:"uptime_seconds"`
+}
+
+// withUptime returns a copy of s with UptimeSeconds computed from StartedAt
+func (s StreamStatus) withUptime() StreamStatus {
+	s.UptimeSeconds = 0
+	if s.IsLive && s.StartedAt != nil {
+		s.UptimeSeconds = int64(time.Since(*s.StartedAt).Seconds())
+	}
+	return s
 }
 
 var (
@@ -67,7 +78,7 @@ func UpdateViewerCount(count int) {
 func GetStreamStatus() StreamStatus {
 	streamMu.RLock()
 	defer streamMu.RUnlock()
-	return streamStatus
+	return streamStatus.withUptime()
 }
 
 // IsStreamLive returns whether the stream is currently live
@@ -113,6 +124,7 @@ func notifyCallbacks(status StreamStatus) {
 	callbackMu.RUnlock()
 
 	// コールバックを非同期で実行
+	status = status.withUptime()
 	for _, callback := range callbacks {
 		go callback(status)
 	}
@@ -122,6 +134,10 @@ func notifyCallbacks(status StreamStatus) {
 func UpdateStreamStatus(isLive bool, startedAt *time.Time, viewerCount int) {
 	streamMu.Lock()
 	previousStatus := streamStatus.IsLive
+	// 開始時刻が不明な場合は、配信継続中であれば既知の開始時刻を保持する
+	if isLive && startedAt == nil && previousStatus {
+		startedAt = streamStatus.StartedAt
+	}
 	streamStatus.IsLive = isLive
 	streamStatus.StartedAt = startedAt
 	streamStatus.ViewerCount = viewerCount
@@ -2,9 +2,12 @@ package twitchapi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/nantokaworks/twitch-overlay/internal/env"
@@ -12,10 +15,30 @@ import (
 	"go.uber.org/zap"
 )
 
+// ErrMissingScope is returned when the token lacks the OAuth scope required by an endpoint
+var ErrMissingScope = errors.New("missing OAuth scope")
+
+// checkScopeError は 401/403 のレスポンスがスコープ不足によるものか判定します
+func checkScopeError(resp *http.Response, scope string) error {
+	if resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden {
+		return nil
+	}
+
+	var body struct {
+		Message string `json:"message"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+	if strings.Contains(strings.ToLower(body.Message), "scope") {
+		return fmt.Errorf("%w: %s (%s)", ErrMissingScope, scope, body.Message)
+	}
+	return nil
+}
+
 // StreamInfo contains stream information
 type StreamInfo struct {
 	ViewerCount int
 	IsLive      bool
+	StartedAt   time.Time // 配信開始時刻（オフライン時はゼロ値）
 }
 
 // ChannelInfo contains channel information
@@ -39,7 +62,8 @@ func GetStreamInfo() (*StreamInfo, error) {
 
 	var result struct {
 		Data []struct {
-			ViewerCount int `json:"viewer_count"`
+			ViewerCount int       `json:"viewer_count"`
+			StartedAt   time.Time `json:"started_at"`
 		} `json:"data"`
 	}
 
@@ -55,6 +79,7 @@ func GetStreamInfo() (*StreamInfo, error) {
 	if len(result.Data) > 0 {
 		info.ViewerCount = result.Data[0].ViewerCount
 		info.IsLive = true
+		info.StartedAt = result.Data[0].StartedAt
 	}
 
 	return info, nil
@@ -127,24 +152,132 @@ type BitsLeaderboardResponse struct {
 	Total int `json:"total"`
 }
 
-// GetBitsLeaderboard retrieves the bits leaderboard for a specific period
+// leaderboardCacheTTL は時計の印刷やデバッグ印刷が続いても API を叩きすぎないためのキャッシュ期間
+const leaderboardCacheTTL = 60 * time.Second
+
+// LEADERBOARD_SIZE の既定値と Twitch API の count の上限
+const (
+	defaultLeaderboardSize = 5
+	maxLeaderboardSize     = 100
+)
+
+type cachedLeaderboard struct {
+	result    *BitsLeaderboardResponse
+	fetchedAt time.Time
+}
+
+var (
+	leaderboardCacheMu sync.Mutex
+	leaderboardCache   = make(map[string]cachedLeaderboard)
+)
+
+// GetBitsLeaderboard retrieves the bits leaderboard for a specific period.
+// 同じ期間の結果（アバターURLを含む）は leaderboardCacheTTL の間キャッシュから返す
 func GetBitsLeaderboard(period string) ([]*BitsLeaderboardEntry, *BitsLeaderboardResponse, error) {
-	logger.Info("Getting bits leaderboard", zap.String("period", period))
+	count := LeaderboardSize()
+	key := fmt.Sprintf("%s:%s:%d", period, *env.Value.TwitchUserID, count)
+
+	leaderboardCacheMu.Lock()
+	cached, ok := leaderboardCache[key]
+	leaderboardCacheMu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < leaderboardCacheTTL {
+		logger.Debug("Using cached bits leaderboard", zap.String("period", period), zap.Duration("age", time.Since(cached.fetchedAt)))
+		return copyLeaderboard(cached.result)
+	}
+
+	leaders, result, err := fetchBitsLeaderboard(period, count)
+	if err != nil || result == nil {
+		// エラー（スコープ不足など）は次回すぐに再確認できるようキャッシュしない
+		return leaders, result, err
+	}
+
+	leaderboardCacheMu.Lock()
+	leaderboardCache[key] = cachedLeaderboard{result: result, fetchedAt: time.Now()}
+	leaderboardCacheMu.Unlock()
+	return copyLeaderboard(result)
+}
+
+// InvalidateBitsLeaderboardCache drops cached leaderboards so the next call hits the API (used by the debug clock)
+func InvalidateBitsLeaderboardCache() {
+	leaderboardCacheMu.Lock()
+	defer leaderboardCacheMu.Unlock()
+	leaderboardCache = make(map[string]cachedLeaderboard)
+}
+
+// copyLeaderboard returns a copy of a cached response so callers cannot modify the cache
+func copyLeaderboard(result *BitsLeaderboardResponse) ([]*BitsLeaderboardEntry, *BitsLeaderboardResponse, error) {
+	copied := *result
+	copied.Data = append([]BitsLeaderboardEntry(nil), result.Data...)
+	if len(copied.Data) == 0 {
+		return nil, &copied, nil
+	}
+
+	leaders := make([]*BitsLeaderboardEntry, len(copied.Data))
+	for i := range copied.Data {
+		leaders[i] = &copied.Data[i]
+	}
+	return leaders, &copied, nil
+}
+
+// leaderboardZone は Twitch のリーダーボードの期間の区切りに使われるタイムゾーン（PST）
+var leaderboardZone = time.FixedZone("PST", -8*60*60)
+
+// LeaderboardPeriodStart returns the start of the period containing now in PST, the zone
+// Twitch uses for leaderboard periods (印刷の見出しもこの日付に合わせる)。all の場合は false
+func LeaderboardPeriodStart(period string, now time.Time) (time.Time, bool) {
+	now = now.In(leaderboardZone)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, leaderboardZone)
+	switch period {
+	case "day":
+		return today, true
+	case "week":
+		// 週は月曜日始まり
+		offset := (int(today.Weekday()) + 6) % 7
+		return today.AddDate(0, 0, -offset), true
+	case "month":
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, leaderboardZone), true
+	case "year":
+		return time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, leaderboardZone), true
+	}
+	return time.Time{}, false
+}
+
+// leaderboardStartedAt returns the started_at parameter (UTC) for the period containing now
+func leaderboardStartedAt(period string, now time.Time) (time.Time, bool) {
+	start, ok := LeaderboardPeriodStart(period, now)
+	return start.UTC(), ok
+}
+
+// LeaderboardPeriod returns LEADERBOARD_PERIOD (day, week, month, year, all)。未設定・不正なら month
+func LeaderboardPeriod() string {
+	switch period := env.Value.LeaderboardPeriod; period {
+	case "day", "week", "month", "year", "all":
+		return period
+	}
+	return "month"
+}
+
+// LeaderboardSize returns LEADERBOARD_SIZE clamped to what the API accepts (1〜100、未設定なら5)
+func LeaderboardSize() int {
+	size := env.Value.LeaderboardSize
+	if size <= 0 {
+		return defaultLeaderboardSize
+	}
+	if size > maxLeaderboardSize {
+		return maxLeaderboardSize
+	}
+	return size
+}
+
+// fetchBitsLeaderboard requests the top count leaders and their avatars from the API
+func fetchBitsLeaderboard(period string, count int) ([]*BitsLeaderboardEntry, *BitsLeaderboardResponse, error) {
+	logger.Info("Getting bits leaderboard", zap.String("period", period), zap.Int("count", count))
 	
-	// For "month" period, we need to specify started_at parameter
-	var reqURL string
-	if period == "month" {
-		// Get first day of current month
-		// Twitch API uses PST timezone, so we need to add 8 hours to UTC to ensure we get the correct month
-		// UTC 08:00:00 = PST 00:00:00
-		now := time.Now()
-		firstOfMonth := time.Date(now.Year(), now.Month(), 1, 8, 0, 0, 0, time.UTC)
-		startedAt := firstOfMonth.Format(time.RFC3339)
-		reqURL = fmt.Sprintf("https://api.twitch.tv/helix/bits/leaderboard?count=5&period=%s&started_at=%s&broadcaster_id=%s", 
-			url.QueryEscape(period), url.QueryEscape(startedAt), url.QueryEscape(*env.Value.TwitchUserID))
-	} else {
-		reqURL = fmt.Sprintf("https://api.twitch.tv/helix/bits/leaderboard?count=5&period=%s&broadcaster_id=%s", 
-			url.QueryEscape(period), url.QueryEscape(*env.Value.TwitchUserID))
+	// day / week / month / year は期間の開始時刻（started_at）を指定する。all は指定しない
+	reqURL := fmt.Sprintf("https://api.twitch.tv/helix/bits/leaderboard?count=%d&period=%s&broadcaster_id=%s",
+		count, url.QueryEscape(period), url.QueryEscape(*env.Value.TwitchUserID))
+	if startedAt, ok := leaderboardStartedAt(period, time.Now()); ok {
+		reqURL += "&started_at=" + url.QueryEscape(startedAt.Format(time.RFC3339))
 	}
 	
 	resp, err := makeAuthenticatedGetRequest(reqURL)
@@ -154,6 +287,11 @@ func GetBitsLeaderboard(period string) ([]*BitsLeaderboardEntry, *BitsLeaderboar
 	}
 	defer resp.Body.Close()
 
+	// bits:read スコープがない場合は「まだ誰もいない」と区別できるよう専用のエラーを返す
+	if err := checkScopeError(resp, "bits:read"); err != nil {
+		return nil, nil, err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, nil, fmt.Errorf("API request failed with status: %d", resp.StatusCode)
 	}
@@ -168,15 +306,18 @@ func GetBitsLeaderboard(period string) ([]*BitsLeaderboardEntry, *BitsLeaderboar
 		return nil, &result, nil // No leaders found but return the response for date_range
 	}
 
-	// Get avatar only for the first place
-	if len(result.Data) > 0 {
-		avatarURL, err := GetUserAvatar(result.Data[0].UserID)
-		if err != nil {
-			logger.Warn("Failed to get user avatar", zap.Error(err))
-			// Continue without avatar
-		} else {
-			result.Data[0].AvatarURL = avatarURL
-		}
+	// 全員のアバターを1回のリクエストでまとめて取得
+	userIDs := make([]string, len(result.Data))
+	for i, entry := range result.Data {
+		userIDs[i] = entry.UserID
+	}
+	avatars, err := GetUserAvatars(userIDs)
+	if err != nil {
+		logger.Warn("Failed to get user avatars", zap.Error(err))
+		// Continue without avatars
+	}
+	for i := range result.Data {
+		result.Data[i].AvatarURL = avatars[result.Data[i].UserID]
 	}
 
 	// Return slice of leaders
@@ -190,31 +331,24 @@ func GetBitsLeaderboard(period string) ([]*BitsLeaderboardEntry, *BitsLeaderboar
 
 // GetUserAvatar retrieves the profile image URL for a user
 func GetUserAvatar(userID string) (string, error) {
-	reqURL := fmt.Sprintf("https://api.twitch.tv/helix/users?id=%s", url.QueryEscape(userID))
-	
-	resp, err := makeAuthenticatedGetRequest(reqURL)
+	users, err := GetUsers([]string{userID})
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API request failed with status: %d", resp.StatusCode)
-	}
-
-	var result struct {
-		Data []struct {
-			ProfileImageURL string `json:"profile_image_url"`
-		} `json:"data"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", err
-	}
-
-	if len(result.Data) == 0 {
+	user, ok := users[userID]
+	if !ok {
 		return "", fmt.Errorf("user not found")
 	}
+	return user.ProfileImageURL, nil
+}
 
-	return result.Data[0].ProfileImageURL, nil
-}
\ No newline at end of file
+// GetUserAvatars retrieves profile image URLs for many users, keyed by user ID.
+// Helix /users は1リクエストで100件まで指定できるので、N人分を N 回ではなくまとめて取得する
+func GetUserAvatars(userIDs []string) (map[string]string, error) {
+	users, err := GetUsers(userIDs)
+	avatars := make(map[string]string, len(users))
+	for id, user := range users {
+		avatars[id] = user.ProfileImageURL
+	}
+	return avatars, err
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/nantokaworks/twitch-overlay/internal/env"
 	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
@@ -35,8 +36,7 @@ func makeAuthenticatedRequest(method, url string, body io.Reader) (*http.Respons
 		return nil, fmt.Errorf("token is invalid and no refresh token available")
 	}
 
-	// リクエストを実行する関数
-	doRequest := func(accessToken string) (*http.Response, error) {
+	send := func(accessToken string) (*http.Response, error) {
 		req, err := http.NewRequest(method, url, body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create request: %w", err)
@@ -47,7 +47,36 @@ func makeAuthenticatedRequest(method, url string, body io.Reader) (*http.Respons
 		req.Header.Set("Authorization", "Bearer "+accessToken)
 
 		client := &http.Client{}
-		return client.Do(req)
+		resp, err := client.Do(req)
+		recordAPICall(resp, err)
+		if err != nil {
+			return nil, err
+		}
+		recordRateLimit(resp)
+		return resp, nil
+	}
+
+	// リクエストを実行する関数（429 Too Many Requestsの場合はRatelimit-Resetまで待って1回だけ再試行）
+	doRequest := func(accessToken string) (*http.Response, error) {
+		resp, err := send(accessToken)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+
+		wait, ok := rateLimitWait(resp)
+		if !ok {
+			logger.Warn("Twitch API rate limit exceeded, reset is too far away to wait",
+				zap.String("url", url),
+				zap.Duration("reset_in", wait))
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		logger.Warn("Twitch API rate limit exceeded, retrying after reset",
+			zap.String("url", url),
+			zap.Duration("wait", wait))
+		time.Sleep(wait)
+		return send(accessToken)
 	}
 
 	// 最初のリクエストを実行
@@ -0,0 +1,83 @@
+package twitchapi
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxRateLimitWait は 429 を受けたときに Ratelimit-Reset まで待つ上限（これを超える場合は待たずにエラーを返す）
+const maxRateLimitWait = 30 * time.Second
+
+// RateLimitStatus is the Helix rate-limit bucket as reported by the most recent response
+type RateLimitStatus struct {
+	Known     bool      `json:"known"` // まだ一度も API を呼んでいなければ false
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"reset_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Throttled int       `json:"throttled"` // 起動してから 429 を受けた回数
+}
+
+var (
+	rateLimitMu     sync.Mutex
+	rateLimitStatus RateLimitStatus
+)
+
+// GetRateLimitStatus returns the remaining Helix quota seen on the last API response
+func GetRateLimitStatus() RateLimitStatus {
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+	return rateLimitStatus
+}
+
+// recordRateLimit stores the Ratelimit-* headers of resp
+func recordRateLimit(resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("Ratelimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+	rateLimitStatus.Known = true
+	rateLimitStatus.Remaining = remaining
+	rateLimitStatus.UpdatedAt = time.Now()
+	if limit, err := strconv.Atoi(resp.Header.Get("Ratelimit-Limit")); err == nil {
+		rateLimitStatus.Limit = limit
+	}
+	if reset, ok := rateLimitReset(resp); ok {
+		rateLimitStatus.ResetAt = reset
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		rateLimitStatus.Throttled++
+	}
+}
+
+// rateLimitReset parses Ratelimit-Reset (Unix seconds when the bucket refills)
+func rateLimitReset(resp *http.Response) (time.Time, bool) {
+	sec, err := strconv.ParseInt(resp.Header.Get("Ratelimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(sec, 0), true
+}
+
+// rateLimitWait returns how long to wait before retrying a 429 response.
+// ヘッダーがない場合は1秒、リセットまでが maxRateLimitWait を超える場合は ok=false
+func rateLimitWait(resp *http.Response) (time.Duration, bool) {
+	reset, ok := rateLimitReset(resp)
+	if !ok {
+		return time.Second, true
+	}
+	wait := time.Until(reset)
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > maxRateLimitWait {
+		return wait, false
+	}
+	// Ratelimit-Reset は秒単位なので少し余裕を持たせる
+	return wait + 100*time.Millisecond, true
+}
@@ -0,0 +1,24 @@
+package twitchapi
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+var (
+	apiCalls  atomic.Int64
+	apiErrors atomic.Int64
+)
+
+// APICallCounts returns how many Helix requests were sent since startup and how many failed
+// (通信エラーまたは 4xx/5xx。429 の再試行もそれぞれ1回として数える)
+func APICallCounts() (calls, errors int64) {
+	return apiCalls.Load(), apiErrors.Load()
+}
+
+func recordAPICall(resp *http.Response, err error) {
+	apiCalls.Add(1)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		apiErrors.Add(1)
+	}
+}
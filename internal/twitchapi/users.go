@@ -0,0 +1,113 @@
+package twitchapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// userCacheTTL はユーザー情報をキャッシュする期間（アバター変更はこの間反映されない）
+const userCacheTTL = time.Hour
+
+// helixUsersBatchSize is the maximum number of id parameters accepted by Helix /users
+const helixUsersBatchSize = 100
+
+// User is the profile data returned by Helix /users
+type User struct {
+	ID              string `json:"id"`
+	Login           string `json:"login"`
+	DisplayName     string `json:"display_name"`
+	ProfileImageURL string `json:"profile_image_url"`
+}
+
+type cachedUser struct {
+	user      *User
+	fetchedAt time.Time
+}
+
+var (
+	userCacheMu sync.Mutex
+	userCache   = make(map[string]cachedUser)
+)
+
+// GetUsers returns profile data for the given user IDs, keyed by user ID.
+// キャッシュにない（または期限切れの）IDだけを100件ずつまとめて問い合わせる。
+// 存在しないユーザーは結果に含まれない
+func GetUsers(userIDs []string) (map[string]*User, error) {
+	users := make(map[string]*User, len(userIDs))
+	var missing []string
+	seen := make(map[string]bool)
+
+	userCacheMu.Lock()
+	for _, id := range userIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if c, ok := userCache[id]; ok && time.Since(c.fetchedAt) < userCacheTTL {
+			users[id] = c.user
+			continue
+		}
+		missing = append(missing, id)
+	}
+	userCacheMu.Unlock()
+
+	for start := 0; start < len(missing); start += helixUsersBatchSize {
+		end := start + helixUsersBatchSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+
+		fetched, err := fetchUsers(missing[start:end])
+		if err != nil {
+			// 取得できた分（キャッシュ分を含む）は返す
+			return users, err
+		}
+
+		now := time.Now()
+		userCacheMu.Lock()
+		for _, u := range fetched {
+			userCache[u.ID] = cachedUser{user: u, fetchedAt: now}
+			users[u.ID] = u
+		}
+		userCacheMu.Unlock()
+	}
+
+	return users, nil
+}
+
+// InvalidateUserCache drops all cached user profiles
+func InvalidateUserCache() {
+	userCacheMu.Lock()
+	defer userCacheMu.Unlock()
+	userCache = make(map[string]cachedUser)
+}
+
+// fetchUsers performs a single Helix /users request for up to helixUsersBatchSize IDs
+func fetchUsers(userIDs []string) ([]*User, error) {
+	query := url.Values{}
+	for _, id := range userIDs {
+		query.Add("id", id)
+	}
+
+	resp, err := makeAuthenticatedGetRequest("https://api.twitch.tv/helix/users?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data []*User `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
@@ -2,19 +2,46 @@ package twitcheventsub
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/joeyak/go-twitch-eventsub/v3"
 	"github.com/nantokaworks/twitch-overlay/internal/env"
 	"github.com/nantokaworks/twitch-overlay/internal/output"
+	"github.com/nantokaworks/twitch-overlay/internal/printrules"
 	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/numfmt"
 	"go.uber.org/zap"
 )
 
+// isFaxAllowed reports whether the user may trigger a fax.
+// FAX_ALLOWED_USERS が空の場合は全員許可（ログイン名・表示名どちらでも一致すればOK、大文字小文字は区別しない）
+func isFaxAllowed(login, name string) bool {
+	allowed := strings.TrimSpace(env.Value.FaxAllowedUsers)
+	if allowed == "" {
+		return true
+	}
+	for _, u := range strings.Split(allowed, ",") {
+		u = strings.TrimSpace(u)
+		if u == "" {
+			continue
+		}
+		if strings.EqualFold(u, login) || strings.EqualFold(u, name) {
+			return true
+		}
+	}
+	return false
+}
+
 func HandleChannelChatMessage(message twitch.EventChannelChatMessage) {
 	if message.ChannelPointsCustomRewardId != *env.Value.TriggerCustomRewordID {
 		return
 	}
+	if !isFaxAllowed(message.Chatter.ChatterUserLogin, message.Chatter.ChatterUserName) {
+		logger.Info("FAX skipped: user not in FAX_ALLOWED_USERS", zap.String("user", message.Chatter.ChatterUserName))
+		output.RecordFaxBlocked()
+		return
+	}
 	output.PrintOut(message.Chatter.ChatterUserName, message.Message.Fragments, time.Now())
 }
 
@@ -22,6 +49,11 @@ func HandleChannelPointsCustomRedemptionAdd(message twitch.EventChannelChannelPo
 	if message.Reward.ID != *env.Value.TriggerCustomRewordID {
 		return
 	}
+	if !isFaxAllowed(message.User.UserLogin, message.User.UserName) {
+		logger.Info("FAX skipped: user not in FAX_ALLOWED_USERS", zap.String("user", message.User.UserName))
+		output.RecordFaxBlocked()
+		return
+	}
 
 	// fragments := []twitch.ChatMessageFragment{
 	// 	{
@@ -37,83 +69,148 @@ func HandleChannelPointsCustomRedemptionAdd(message twitch.EventChannelChannelPo
 }
 
 func HandleChannelCheer(message twitch.EventChannelCheer) {
-	title := "ビッツありがとう :)"
+	if !env.Value.PrintCheerEnabled {
+		logger.Debug("Printing disabled for event type", zap.String("event", "cheer"))
+		output.RecordFaxBlocked()
+		return
+	}
+	if message.Bits < env.Value.CheerMinBits {
+		logger.Debug("Cheer below CHEER_MIN_BITS, not printing",
+			zap.String("user", message.User.UserName),
+			zap.Int("bits", message.Bits),
+			zap.Int("min_bits", env.Value.CheerMinBits))
+		output.RecordFaxBlocked()
+		return
+	}
 	userName := message.User.UserName
-	details := fmt.Sprintf("%d ビッツ", message.Bits)
+	bits := numfmt.Int(message.Bits, env.Value.Locale)
+	vars := map[string]string{"user": userName, "bits": bits}
+	title := renderTemplate(env.Value.TemplateCheerTitle, "ビッツありがとう :)", vars)
+	details := renderTemplate(env.Value.TemplateCheerDetail, bits+" ビッツ", vars)
 
-	output.PrintOutWithTitle(title, userName, "", details, time.Now())
+	event := printrules.Event{Type: printrules.EventCheer, Amount: message.Bits}
+	output.PrintOutWithTitleForEvent(event, title, userName, "", details, time.Now())
 }
 func HandleChannelFollow(message twitch.EventChannelFollow) {
-	title := "フォローありがとう :)"
+	if !env.Value.PrintFollowEnabled {
+		logger.Debug("Printing disabled for event type", zap.String("event", "follow"))
+		output.RecordFaxBlocked()
+		return
+	}
 	userName := message.User.UserName
-	details := "" // フォローの場合は詳細なし
+	vars := map[string]string{"user": userName}
+	title := renderTemplate(env.Value.TemplateFollowTitle, "フォローありがとう :)", vars)
+	details := renderTemplate(env.Value.TemplateFollowDetail, "", vars) // フォローの場合は既定では詳細なし
 
-	output.PrintOutWithTitle(title, userName, "", details, time.Now())
+	event := printrules.Event{Type: printrules.EventFollow}
+	output.PrintOutWithTitleForEvent(event, title, userName, "", details, time.Now())
 }
 func HandleChannelRaid(message twitch.EventChannelRaid) {
-	title := "レイドありがとう :)"
+	if !env.Value.PrintRaidEnabled {
+		logger.Debug("Printing disabled for event type", zap.String("event", "raid"))
+		output.RecordFaxBlocked()
+		return
+	}
 	userName := message.FromBroadcasterUserName
-	details := fmt.Sprintf("%d 人", message.Viewers)
+	viewers := numfmt.Int(message.Viewers, env.Value.Locale)
+	vars := map[string]string{"user": userName, "viewers": viewers}
+	title := renderTemplate(env.Value.TemplateRaidTitle, "レイドありがとう :)", vars)
+	details := renderTemplate(env.Value.TemplateRaidDetail, viewers+" 人", vars)
 
-	output.PrintOutWithTitle(title, userName, "", details, time.Now())
+	event := printrules.Event{Type: printrules.EventRaid, Amount: message.Viewers}
+	output.PrintOutWithTitleForEvent(event, title, userName, "", details, time.Now())
 }
 func HandleChannelShoutoutReceive(message twitch.EventChannelShoutoutReceive) {
-	title := "応援ありがとう :)"
+	if !env.Value.PrintShoutoutEnabled {
+		logger.Debug("Printing disabled for event type", zap.String("event", "shoutout"))
+		output.RecordFaxBlocked()
+		return
+	}
 	userName := message.FromBroadcasterUserName
-	details := "" // シャウトアウトの場合は詳細なし
+	vars := map[string]string{"user": userName, "viewers": numfmt.Int(message.ViewerCount, env.Value.Locale)}
+	title := renderTemplate(env.Value.TemplateShoutoutTitle, "応援ありがとう :)", vars)
+	details := renderTemplate(env.Value.TemplateShoutoutDetail, "", vars) // シャウトアウトの場合は既定では詳細なし
 
-	output.PrintOutWithTitle(title, userName, "", details, time.Now())
+	event := printrules.Event{Type: printrules.EventShoutout, Amount: message.ViewerCount}
+	output.PrintOutWithTitleForEvent(event, title, userName, "", details, time.Now())
 }
 func HandleChannelSubscribe(message twitch.EventChannelSubscribe) {
+	if !env.Value.PrintSubscribeEnabled {
+		logger.Debug("Printing disabled for event type", zap.String("event", "subscribe"))
+		output.RecordFaxBlocked()
+		return
+	}
+	vars := map[string]string{"user": message.User.UserName, "tier": message.Tier}
 	if !message.IsGift {
-		title := "サブスクありがとう :)"
+		title := renderTemplate(env.Value.TemplateSubscribeTitle, "サブスクありがとう :)", vars)
 		userName := message.User.UserName
-		details := fmt.Sprintf("Tier %s", message.Tier)
+		details := renderTemplate(env.Value.TemplateSubscribeDetail, fmt.Sprintf("Tier %s", message.Tier), vars)
 
-		output.PrintOutWithTitle(title, userName, "", details, time.Now())
+		event := printrules.Event{Type: printrules.EventSubscribe}
+		output.PrintOutWithTitleForEvent(event, title, userName, "", details, time.Now())
 	} else {
-		title := "サブギフおめです :)"
+		// ギフトを受け取った側もサブギフのテンプレートを使う（1人分なので count は 1）
+		vars["count"] = numfmt.Int(1, env.Value.Locale)
+		title := renderTemplate(env.Value.TemplateGiftSubTitle, "サブギフおめです :)", vars)
 		userName := message.User.UserName
-		details := fmt.Sprintf("Tier %s", message.Tier)
+		details := renderTemplate(env.Value.TemplateGiftSubDetail, fmt.Sprintf("Tier %s", message.Tier), vars)
 
-		output.PrintOutWithTitle(title, userName, "", details, time.Now())
+		event := printrules.Event{Type: printrules.EventSubscribe}
+		output.PrintOutWithTitleForEvent(event, title, userName, "", details, time.Now())
 	}
 }
 
 func HandleChannelSubscriptionGift(message twitch.EventChannelSubscriptionGift) {
-	title := "サブギフありがとう :)"
+	if !env.Value.PrintGiftSubEnabled {
+		logger.Debug("Printing disabled for event type", zap.String("event", "gift sub"))
+		output.RecordFaxBlocked()
+		return
+	}
+	event := printrules.Event{Type: printrules.EventGiftSub, Amount: message.Total}
 
+	userName := "匿名さん"
 	if !message.IsAnonymous {
-		userName := message.User.UserName
-		details := fmt.Sprintf("Tier %s | %d個", message.Tier, message.Total)
-		output.PrintOutWithTitle(title, userName, "", details, time.Now())
-	} else {
-		userName := "匿名さん"
-		details := fmt.Sprintf("Tier %s | %d個", message.Tier, message.Total)
-		output.PrintOutWithTitle(title, userName, "", details, time.Now())
+		userName = message.User.UserName
 	}
+	count := numfmt.Int(message.Total, env.Value.Locale)
+	vars := map[string]string{"user": userName, "tier": message.Tier, "count": count}
+	title := renderTemplate(env.Value.TemplateGiftSubTitle, "サブギフありがとう :)", vars)
+	details := renderTemplate(env.Value.TemplateGiftSubDetail, fmt.Sprintf("Tier %s | %s個", message.Tier, count), vars)
+	output.PrintOutWithTitleForEvent(event, title, userName, "", details, time.Now())
 }
 
 func HandleChannelSubscriptionMessage(message twitch.EventChannelSubscriptionMessage) {
+	if !env.Value.PrintResubEnabled {
+		logger.Debug("Printing disabled for event type", zap.String("event", "resub"))
+		output.RecordFaxBlocked()
+		return
+	}
 	// 再サブスクメッセージの処理
 	var title string
 	var extra string
 	var details string
 
+	vars := map[string]string{
+		"user":   message.User.UserName,
+		"tier":   message.Tier,
+		"months": fmt.Sprintf("%d", message.CumulativeMonths),
+	}
+
 	if message.CumulativeMonths > 1 {
-		// 再サブスク - 4行レイアウト
-		title = "サブスクありがとう :)"
-		extra = fmt.Sprintf("%d ヶ月目", message.CumulativeMonths)
+		// 再サブスク - 4行レイアウト（TEMPLATE_RESUB_DETAIL は月数の行に使う）
+		title = renderTemplate(env.Value.TemplateResubTitle, "サブスクありがとう :)", vars)
+		extra = renderTemplate(env.Value.TemplateResubDetail, fmt.Sprintf("%d ヶ月目", message.CumulativeMonths), vars)
 		details = message.Message.Text // 空メッセージの場合は空文字列
 	} else {
 		// 初回サブスク（メッセージ付き）
-		title = "サブスクありがとう :)"
+		title = renderTemplate(env.Value.TemplateResubTitle, "サブスクありがとう :)", vars)
 		extra = ""                     // 初回は月数なし
 		details = message.Message.Text // 空メッセージの場合は空文字列のまま
 	}
 
 	userName := message.User.UserName
-	output.PrintOutWithTitle(title, userName, extra, details, time.Now())
+	event := printrules.Event{Type: printrules.EventResub, Amount: message.CumulativeMonths}
+	output.PrintOutWithTitleForEvent(event, title, userName, extra, details, time.Now())
 
 	logger.Info("サブスクメッセージ",
 		zap.String("user", message.User.UserName),
@@ -0,0 +1,85 @@
+package twitcheventsub
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/output"
+	"github.com/nantokaworks/twitch-overlay/internal/printrules"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/numfmt"
+	"github.com/nantokaworks/twitch-overlay/internal/status"
+	"go.uber.org/zap"
+)
+
+// goalTypeLabels はゴールの種類ごとの単位
+var goalTypeLabels = map[string]string{
+	"follow":                 "フォロワー",
+	"subscription":           "サブスクポイント",
+	"subscription_count":     "サブスク",
+	"new_subscription":       "新規サブスクポイント",
+	"new_subscription_count": "新規サブスク",
+	"new_bit":                "ビッツ",
+	"new_cheerer":            "Cheerした人",
+}
+
+func HandleChannelGoalBegin(message twitch.EventChannelGoalBegin) {
+	status.UpdateGoal(goalStatus(message))
+	logger.Info("Goal began",
+		zap.String("id", message.ID),
+		zap.String("type", message.Type),
+		zap.Int("target", message.TargetAmount))
+}
+
+// HandleChannelGoalProgress only updates the status; 進捗のたびに印刷すると大量になるので印刷しない
+func HandleChannelGoalProgress(message twitch.EventChannelGoalProgress) {
+	status.UpdateGoal(goalStatus(twitch.EventChannelGoalBegin(message)))
+}
+
+func HandleChannelGoalEnd(message twitch.EventChannelGoalEnd) {
+	status.EndGoal(message.ID, message.IsAchieved)
+
+	logger.Info("Goal ended",
+		zap.String("id", message.ID),
+		zap.String("type", message.Type),
+		zap.Bool("achieved", message.IsAchieved),
+		zap.Int("current", message.CurrentAmount),
+		zap.Int("target", message.TargetAmount))
+
+	// 達成した場合だけお祝いのFAXを印刷する
+	if !message.IsAchieved {
+		return
+	}
+	if !env.Value.PrintGoalEnabled {
+		logger.Debug("Printing disabled for event type", zap.String("event", "goal"))
+		output.RecordFaxBlocked()
+		return
+	}
+
+	unit := goalTypeLabels[message.Type]
+	title := "ゴール達成！"
+	name := message.Description
+	if name == "" {
+		name = unit + "ゴール"
+	}
+	details := fmt.Sprintf("%s / %s %s",
+		numfmt.Int(message.CurrentAmount, env.Value.Locale),
+		numfmt.Int(message.TargetAmount, env.Value.Locale),
+		unit)
+
+	event := printrules.Event{Type: printrules.EventGoal, Amount: message.TargetAmount}
+	output.PrintOutWithTitleForEvent(event, title, name, "みんなありがとう :)", details, time.Now())
+}
+
+func goalStatus(message twitch.EventChannelGoalBegin) status.Goal {
+	return status.Goal{
+		ID:            message.ID,
+		Type:          message.Type,
+		Description:   message.Description,
+		CurrentAmount: message.CurrentAmount,
+		TargetAmount:  message.TargetAmount,
+		StartedAt:     message.StartedAt,
+	}
+}
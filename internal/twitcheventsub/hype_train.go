@@ -0,0 +1,84 @@
+package twitcheventsub
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/output"
+	"github.com/nantokaworks/twitch-overlay/internal/printrules"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/numfmt"
+	"go.uber.org/zap"
+)
+
+// hypeTrainTopContributors は FAX に載せる上位貢献者の人数
+const hypeTrainTopContributors = 3
+
+func HandleHypeTrainBegin(message twitch.EventChannelHypeTrainBegin) {
+	if !env.Value.PrintHypeTrainEnabled {
+		logger.Debug("Printing disabled for event type", zap.String("event", "hype train"))
+		output.RecordFaxBlocked()
+		return
+	}
+
+	userName := message.LastContribution.UserName
+	title := "ハイプトレイン発車！"
+	extra := fmt.Sprintf("レベル %d", message.Level)
+	details := fmt.Sprintf("%s / %s", numfmt.Int(message.Progress, env.Value.Locale), numfmt.Int(message.Goal, env.Value.Locale))
+
+	event := printrules.Event{Type: printrules.EventHypeTrain, Amount: message.Level}
+	output.PrintOutWithTitleForEvent(event, title, userName, extra, details, time.Now())
+
+	logger.Info("Hype train began",
+		zap.String("id", message.Id),
+		zap.Int("level", message.Level),
+		zap.Int("total", message.Total))
+}
+
+func HandleHypeTrainEnd(message twitch.EventChannelHypeTrainEnd) {
+	if !env.Value.PrintHypeTrainEnabled {
+		logger.Debug("Printing disabled for event type", zap.String("event", "hype train"))
+		output.RecordFaxBlocked()
+		return
+	}
+
+	// 最大の貢献者を名前の欄に、上位数名を詳細に載せる
+	userName := "みんな"
+	if len(message.TopContributions) > 0 {
+		userName = message.TopContributions[0].UserName
+	}
+	title := "ハイプトレインありがとう :)"
+	extra := fmt.Sprintf("レベル %d | 合計 %s", message.Level, numfmt.Int(message.Total, env.Value.Locale))
+	details := hypeTrainContributors(message.TopContributions)
+
+	event := printrules.Event{Type: printrules.EventHypeTrain, Amount: message.Level}
+	output.PrintOutWithTitleForEvent(event, title, userName, extra, details, time.Now())
+
+	logger.Info("Hype train ended",
+		zap.String("id", message.Id),
+		zap.Int("level", message.Level),
+		zap.Int("total", message.Total),
+		zap.Int("top_contributions", len(message.TopContributions)))
+}
+
+// hypeTrainContributors formats the top contributors as "name 1,000 ビッツ / name サブスク"
+func hypeTrainContributors(contributions []twitch.HypeTrainContribution) string {
+	parts := make([]string, 0, hypeTrainTopContributors)
+	for i, c := range contributions {
+		if i >= hypeTrainTopContributors {
+			break
+		}
+		switch c.Type {
+		case "bits":
+			parts = append(parts, fmt.Sprintf("%s %s ビッツ", c.UserName, numfmt.Int(c.Total, env.Value.Locale)))
+		case "subscription":
+			parts = append(parts, fmt.Sprintf("%s サブスク", c.UserName))
+		default:
+			parts = append(parts, c.UserName)
+		}
+	}
+	return strings.Join(parts, " / ")
+}
@@ -0,0 +1,101 @@
+package twitcheventsub
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/output"
+	"github.com/nantokaworks/twitch-overlay/internal/printrules"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/numfmt"
+	"go.uber.org/zap"
+)
+
+func HandleChannelPollBegin(message twitch.EventChannelPollBegin) {
+	if !env.Value.PrintPollEnabled {
+		logger.Debug("Printing disabled for event type", zap.String("event", "poll"))
+		output.RecordFaxBlocked()
+		return
+	}
+
+	choices := make([]string, len(message.Choices))
+	for i, c := range message.Choices {
+		choices[i] = fmt.Sprintf("%d. %s", i+1, c.Title)
+	}
+	title := "投票開始！"
+	extra := ""
+	if !message.EndsAt.IsZero() {
+		extra = fmt.Sprintf("締め切り %s", message.EndsAt.In(pollLocation()).Format("15:04"))
+	}
+
+	event := printrules.Event{Type: printrules.EventPoll}
+	output.PrintOutWithTitleForEvent(event, title, message.Title, extra, strings.Join(choices, " / "), time.Now())
+
+	logger.Info("Poll began",
+		zap.String("id", message.ID),
+		zap.String("title", message.Title),
+		zap.Int("choices", len(message.Choices)))
+}
+
+func HandleChannelPollEnd(message twitch.EventChannelPollEnd) {
+	// 完了後にアーカイブされた際にも end が届くので、結果は completed / terminated の時だけ印刷する
+	if message.Status == "archived" {
+		return
+	}
+	if !env.Value.PrintPollEnabled {
+		logger.Debug("Printing disabled for event type", zap.String("event", "poll"))
+		output.RecordFaxBlocked()
+		return
+	}
+
+	choices := append([]twitch.PollChoice(nil), message.Choices...)
+	sort.SliceStable(choices, func(i, j int) bool {
+		return choices[i].Votes > choices[j].Votes
+	})
+
+	total := 0
+	results := make([]string, len(choices))
+	for i, c := range choices {
+		total += c.Votes
+		results[i] = fmt.Sprintf("%s %s票", c.Title, numfmt.Int(c.Votes, env.Value.Locale))
+	}
+
+	// 同票の場合は1位をすべて並べる
+	var winners []string
+	for _, c := range choices {
+		if c.Votes != choices[0].Votes {
+			break
+		}
+		winners = append(winners, c.Title)
+	}
+	winner := strings.Join(winners, " / ")
+	if total == 0 {
+		winner = "投票なし"
+	}
+
+	title := "投票結果"
+	extra := fmt.Sprintf("%s | 合計 %s票", message.Title, numfmt.Int(total, env.Value.Locale))
+
+	event := printrules.Event{Type: printrules.EventPoll, Amount: total}
+	output.PrintOutWithTitleForEvent(event, title, winner, extra, strings.Join(results, " / "), time.Now())
+
+	logger.Info("Poll ended",
+		zap.String("id", message.ID),
+		zap.String("title", message.Title),
+		zap.String("status", message.Status),
+		zap.String("winner", winner),
+		zap.Int("total_votes", total))
+}
+
+// pollLocation returns the TIMEZONE used to print the poll deadline
+func pollLocation() *time.Location {
+	loc, err := time.LoadLocation(env.Value.TimeZone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
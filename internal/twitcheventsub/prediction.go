@@ -0,0 +1,98 @@
+package twitcheventsub
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/output"
+	"github.com/nantokaworks/twitch-overlay/internal/printrules"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/numfmt"
+	"go.uber.org/zap"
+)
+
+func HandleChannelPredictionBegin(message twitch.EventChannelPredictionBegin) {
+	if !env.Value.PrintPredictionEnabled {
+		logger.Debug("Printing disabled for event type", zap.String("event", "prediction"))
+		output.RecordFaxBlocked()
+		return
+	}
+
+	outcomes := make([]string, len(message.Outcomes))
+	for i, o := range message.Outcomes {
+		outcomes[i] = fmt.Sprintf("%d. %s", i+1, o.Title)
+	}
+	title := "予想開始！"
+	extra := ""
+	if !message.LocksAt.IsZero() {
+		extra = fmt.Sprintf("締め切り %s", message.LocksAt.In(pollLocation()).Format("15:04"))
+	}
+
+	event := printrules.Event{Type: printrules.EventPrediction}
+	output.PrintOutWithTitleForEvent(event, title, message.Title, extra, strings.Join(outcomes, " / "), time.Now())
+
+	logger.Info("Prediction began",
+		zap.String("id", message.ID),
+		zap.String("title", message.Title),
+		zap.Int("outcomes", len(message.Outcomes)))
+}
+
+func HandleChannelPredictionEnd(message twitch.EventChannelPredictionEnd) {
+	if !env.Value.PrintPredictionEnabled {
+		logger.Debug("Printing disabled for event type", zap.String("event", "prediction"))
+		output.RecordFaxBlocked()
+		return
+	}
+
+	total := 0
+	for _, o := range message.Outcomes {
+		total += o.ChannelPoints
+	}
+
+	// キャンセルされた予想には勝者がいない（ポイントは返却される）ので結果を出さない
+	if message.Status == "canceled" {
+		event := printrules.Event{Type: printrules.EventPrediction}
+		output.PrintOutWithTitleForEvent(event, "予想キャンセル", message.Title, "", "ポイントは返却されました", time.Now())
+		logger.Info("Prediction canceled", zap.String("id", message.ID), zap.String("title", message.Title))
+		return
+	}
+
+	winner := "結果なし"
+	for _, o := range message.Outcomes {
+		if o.ID == message.WinningOutcomeID {
+			winner = o.Title
+			break
+		}
+	}
+
+	title := "予想結果"
+	extra := fmt.Sprintf("%s | 合計 %spt", message.Title, numfmt.Int(total, env.Value.Locale))
+	details := predictionDistribution(message.Outcomes, total)
+
+	event := printrules.Event{Type: printrules.EventPrediction, Amount: total}
+	output.PrintOutWithTitleForEvent(event, title, winner, extra, details, time.Now())
+
+	logger.Info("Prediction ended",
+		zap.String("id", message.ID),
+		zap.String("title", message.Title),
+		zap.String("status", message.Status),
+		zap.String("winner", winner),
+		zap.Int("total_points", total))
+}
+
+// predictionDistribution formats each outcome as "title 60% 1,200pt (3人)"
+func predictionDistribution(outcomes []twitch.PredictionOutcome, total int) string {
+	parts := make([]string, len(outcomes))
+	for i, o := range outcomes {
+		percent := 0
+		if total > 0 {
+			percent = o.ChannelPoints * 100 / total
+		}
+		parts[i] = fmt.Sprintf("%s %d%% %spt (%s人)", o.Title, percent,
+			numfmt.Int(o.ChannelPoints, env.Value.Locale), numfmt.Int(o.Users, env.Value.Locale))
+	}
+	return strings.Join(parts, " / ")
+}
@@ -31,6 +31,7 @@ func HandleStreamOnline(message twitch.EventStreamOnline) {
 			"broadcaster_id":   message.Broadcaster.BroadcasterUserId,
 			"broadcaster_name": message.Broadcaster.BroadcasterUserName,
 			"started_at":       startedAt,
+			"uptime_seconds":   int64(time.Since(startedAt).Seconds()),
 			"is_live":          true,
 		},
 	})
@@ -35,6 +35,16 @@ func SetupEventSub(token *twitchtoken.Token) {
 			twitch.SubChannelSubscriptionMessage,
 			twitch.SubStreamOffline,
 			twitch.SubStreamOnline,
+			// channel:read:hype_train / polls / predictions / goals が必要（古いトークンでは失敗するので最後に購読する）
+			twitch.SubChannelHypeTrainBegin,
+			twitch.SubChannelHypeTrainEnd,
+			twitch.SubChannelPollBegin,
+			twitch.SubChannelPollEnd,
+			twitch.SubChannelPredictionBegin,
+			twitch.SubChannelPredictionEnd,
+			twitch.SubChannelGoalBegin,
+			twitch.SubChannelGoalProgress,
+			twitch.SubChannelGoalEnd,
 		}
 
 		for _, event := range events {
@@ -146,6 +156,82 @@ func SetupEventSub(token *twitchtoken.Token) {
 				HandleChannelSubscriptionMessage(evt)
 			}
 
+		// use hype train
+		case twitch.SubChannelHypeTrainBegin:
+			var evt twitch.EventChannelHypeTrainBegin
+			if err := json.Unmarshal(*message.Payload.Event, &evt); err != nil {
+				fmt.Printf("Error parsing HYPE TRAIN BEGIN event: %v\n", err)
+			} else {
+				HandleHypeTrainBegin(evt)
+			}
+
+		case twitch.SubChannelHypeTrainEnd:
+			var evt twitch.EventChannelHypeTrainEnd
+			if err := json.Unmarshal(*message.Payload.Event, &evt); err != nil {
+				fmt.Printf("Error parsing HYPE TRAIN END event: %v\n", err)
+			} else {
+				HandleHypeTrainEnd(evt)
+			}
+
+		// use poll
+		case twitch.SubChannelPollBegin:
+			var evt twitch.EventChannelPollBegin
+			if err := json.Unmarshal(*message.Payload.Event, &evt); err != nil {
+				fmt.Printf("Error parsing POLL BEGIN event: %v\n", err)
+			} else {
+				HandleChannelPollBegin(evt)
+			}
+
+		case twitch.SubChannelPollEnd:
+			var evt twitch.EventChannelPollEnd
+			if err := json.Unmarshal(*message.Payload.Event, &evt); err != nil {
+				fmt.Printf("Error parsing POLL END event: %v\n", err)
+			} else {
+				HandleChannelPollEnd(evt)
+			}
+
+		// use prediction
+		case twitch.SubChannelPredictionBegin:
+			var evt twitch.EventChannelPredictionBegin
+			if err := json.Unmarshal(*message.Payload.Event, &evt); err != nil {
+				fmt.Printf("Error parsing PREDICTION BEGIN event: %v\n", err)
+			} else {
+				HandleChannelPredictionBegin(evt)
+			}
+
+		case twitch.SubChannelPredictionEnd:
+			var evt twitch.EventChannelPredictionEnd
+			if err := json.Unmarshal(*message.Payload.Event, &evt); err != nil {
+				fmt.Printf("Error parsing PREDICTION END event: %v\n", err)
+			} else {
+				HandleChannelPredictionEnd(evt)
+			}
+
+		// use goal
+		case twitch.SubChannelGoalBegin:
+			var evt twitch.EventChannelGoalBegin
+			if err := json.Unmarshal(*message.Payload.Event, &evt); err != nil {
+				fmt.Printf("Error parsing GOAL BEGIN event: %v\n", err)
+			} else {
+				HandleChannelGoalBegin(evt)
+			}
+
+		case twitch.SubChannelGoalProgress:
+			var evt twitch.EventChannelGoalProgress
+			if err := json.Unmarshal(*message.Payload.Event, &evt); err != nil {
+				fmt.Printf("Error parsing GOAL PROGRESS event: %v\n", err)
+			} else {
+				HandleChannelGoalProgress(evt)
+			}
+
+		case twitch.SubChannelGoalEnd:
+			var evt twitch.EventChannelGoalEnd
+			if err := json.Unmarshal(*message.Payload.Event, &evt); err != nil {
+				fmt.Printf("Error parsing GOAL END event: %v\n", err)
+			} else {
+				HandleChannelGoalEnd(evt)
+			}
+
 		// use stream offline
 		case twitch.SubStreamOffline:
 			var evt twitch.EventStreamOffline
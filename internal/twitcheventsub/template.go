@@ -0,0 +1,17 @@
+package twitcheventsub
+
+import "strings"
+
+// renderTemplate replaces {name} placeholders in tmpl with vars (TEMPLATE_* settings).
+// テンプレートが空の場合は既定の文言 fallback をそのまま使う。未知のプレースホルダーは残す
+func renderTemplate(tmpl, fallback string, vars map[string]string) string {
+	if strings.TrimSpace(tmpl) == "" {
+		return fallback
+	}
+
+	pairs := make([]string, 0, len(vars)*2)
+	for name, value := range vars {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(tmpl)
+}
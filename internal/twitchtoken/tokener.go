@@ -26,6 +26,10 @@ var scopes = []string{
 	"moderator:read:followers",
 	"channel:manage:redemptions",
 	"moderator:manage:shoutouts",
+	"channel:read:hype_train",
+	"channel:read:polls",
+	"channel:read:predictions",
+	"channel:read:goals",
 }
 
 func GetTwitchToken(code string) (map[string]interface{}, error) {
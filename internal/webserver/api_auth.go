@@ -0,0 +1,80 @@
+package webserver
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// overlayReportEndpoints はオーバーレイが自身の再生状態を報告するためのエンドポイント。
+// OBS のブラウザソースにはトークンを渡せないので、API_TOKEN 設定時も認証しない
+var overlayReportEndpoints = map[string]bool{
+	"/api/music/status/update": true,
+	"/api/music/state/update":  true,
+}
+
+// apiAuthMiddleware requires API_TOKEN on requests that can change state when the token is configured.
+// 対象は /api/ への GET 以外のリクエストと /debug/ への全リクエスト（GET /api/settings/v2 はシークレットをマスクして返す）。
+// オーバーレイ（OBS のブラウザソース）が使う /events, /fax/, /status などの読み取りはそのまま通す
+func apiAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := env.Value.APIToken
+		if token == "" || !requiresAPIToken(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !hasValidAPIToken(r) {
+			logger.Warn("Rejected unauthenticated API request",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("remote", r.RemoteAddr))
+			// ブラウザから読めるよう CORS ヘッダーは付けておく
+			setAllowOrigin(w, r)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="twitch-overlay"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requiresAPIToken reports whether the request must carry API_TOKEN
+func requiresAPIToken(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodOptions:
+		// CORS のプリフライトにはトークンが付かない
+		return false
+	}
+	if strings.HasPrefix(r.URL.Path, "/debug/") {
+		return true
+	}
+	if !strings.HasPrefix(r.URL.Path, "/api/") || overlayReportEndpoints[r.URL.Path] {
+		return false
+	}
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		// シークレットを含むエクスポートは読み取りでも保護する
+		return r.URL.Path == "/api/settings/export" && r.URL.Query().Get("include_secrets") == "true"
+	}
+	return true
+}
+
+// hasValidAPIToken reports whether the request carries the configured API_TOKEN
+func hasValidAPIToken(r *http.Request) bool {
+	token := env.Value.APIToken
+	return token != "" && subtle.ConstantTimeCompare([]byte(requestAPIToken(r)), []byte(token)) == 1
+}
+
+// requestAPIToken returns the token from "Authorization: Bearer" or ?token=
+func requestAPIToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	return r.URL.Query().Get("token")
+}
@@ -0,0 +1,161 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// clientHeartbeatInterval は SSE / WebSocket クライアントへのハートビート間隔
+	clientHeartbeatInterval = 30 * time.Second
+	// clientStaleAfter を超えて書き込みに成功していないクライアントは死んでいるとみなして切断する
+	clientStaleAfter = 3 * clientHeartbeatInterval
+)
+
+// 接続の種類
+const (
+	transportSSE          = "sse"
+	transportWebSocket    = "websocket"
+	transportMusicControl = "music_control"
+)
+
+// streamClient tracks a connected SSE / WebSocket client for diagnostics (/api/clients)
+type streamClient struct {
+	transport   string
+	remoteAddr  string
+	userAgent   string
+	connectedAt time.Time
+	lastSeen    atomic.Int64 // 最後に書き込みに成功した時刻（UnixNano）
+
+	stop     chan struct{} // 古いクライアントとして切断するときに閉じる
+	stopOnce sync.Once
+}
+
+func newStreamClient(r *http.Request, transport string) *streamClient {
+	c := &streamClient{
+		transport:   transport,
+		remoteAddr:  r.RemoteAddr,
+		userAgent:   r.UserAgent(),
+		connectedAt: time.Now(),
+		stop:        make(chan struct{}),
+	}
+	c.touch()
+	return c
+}
+
+// touch records a successful write to the client
+func (c *streamClient) touch() {
+	c.lastSeen.Store(time.Now().UnixNano())
+}
+
+func (c *streamClient) lastSeenAt() time.Time {
+	return time.Unix(0, c.lastSeen.Load())
+}
+
+func (c *streamClient) isStale(now time.Time) bool {
+	return now.Sub(c.lastSeenAt()) > clientStaleAfter
+}
+
+// disconnect asks the client's handler to return (ハンドラー側で登録解除される)
+func (c *streamClient) disconnect() {
+	c.stopOnce.Do(func() { close(c.stop) })
+}
+
+// clientSnapshot is the JSON view of a streamClient
+type clientSnapshot struct {
+	Transport   string    `json:"transport"`
+	RemoteAddr  string    `json:"remote_addr"`
+	UserAgent   string    `json:"user_agent,omitempty"`
+	ConnectedAt time.Time `json:"connected_at"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+func (c *streamClient) snapshot() clientSnapshot {
+	return clientSnapshot{
+		Transport:   c.transport,
+		RemoteAddr:  c.remoteAddr,
+		UserAgent:   c.userAgent,
+		ConnectedAt: c.connectedAt,
+		LastSeen:    c.lastSeenAt(),
+	}
+}
+
+// connectedClients returns the live fax event (SSE / WebSocket) and music control clients
+func connectedClients() (events, musicControl []*streamClient) {
+	sseServer.mu.RLock()
+	for _, c := range sseServer.clients {
+		events = append(events, c)
+	}
+	sseServer.mu.RUnlock()
+
+	musicControlMutex.RLock()
+	for _, c := range musicControlClients {
+		musicControl = append(musicControl, c)
+	}
+	musicControlMutex.RUnlock()
+	return events, musicControl
+}
+
+// pruneStaleClients disconnects clients that have not been written to successfully for clientStaleAfter
+func pruneStaleClients() int {
+	events, musicControl := connectedClients()
+	now := time.Now()
+	pruned := 0
+	for _, c := range append(events, musicControl...) {
+		if c.isStale(now) {
+			logger.Info("Disconnecting stale client",
+				zap.String("transport", c.transport),
+				zap.String("remote", c.remoteAddr),
+				zap.Time("last_seen", c.lastSeenAt()))
+			c.disconnect()
+			pruned++
+		}
+	}
+	return pruned
+}
+
+// pruneStaleClientsPeriodically runs pruneStaleClients every heartbeat interval
+func pruneStaleClientsPeriodically() {
+	ticker := time.NewTicker(clientHeartbeatInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		pruneStaleClients()
+	}
+}
+
+// handleClients returns how many overlays are connected (GET /api/clients)
+func handleClients(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pruneStaleClients()
+	events, musicControl := connectedClients()
+
+	counts := map[string]int{}
+	clients := make([]clientSnapshot, 0, len(events)+len(musicControl))
+	for _, c := range append(events, musicControl...) {
+		counts[c.transport]++
+		clients = append(clients, c.snapshot())
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].ConnectedAt.Before(clients[j].ConnectedAt)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"events":        len(events), // SSE (/events) と WebSocket (/ws/fax) の合計
+		"sse":           counts[transportSSE],
+		"websocket":     counts[transportWebSocket],
+		"music_control": counts[transportMusicControl],
+		"clients":       clients,
+	})
+}
@@ -0,0 +1,65 @@
+package webserver
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+)
+
+// allowedOriginFor returns the Access-Control-Allow-Origin value for the request (ALLOWED_ORIGINS).
+// 許可リストに "*" があれば "*"、Origin が一致すればその Origin、どちらでもなければ空文字
+func allowedOriginFor(r *http.Request) string {
+	list := env.Value.AllowedOrigins
+	if strings.TrimSpace(list) == "" {
+		list = "*"
+	}
+
+	origin := r.Header.Get("Origin")
+	for _, allowed := range strings.Split(list, ",") {
+		allowed = strings.TrimRight(strings.TrimSpace(allowed), "/")
+		if allowed == "*" {
+			return "*"
+		}
+		if origin != "" && strings.EqualFold(allowed, origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// setAllowOrigin sets Access-Control-Allow-Origin only when the request's origin is allowed
+func setAllowOrigin(w http.ResponseWriter, r *http.Request) {
+	value := allowedOriginFor(r)
+	if value == "" {
+		return
+	}
+	if value != "*" {
+		// オリジンごとに応答が変わるのでキャッシュを分ける
+		w.Header().Add("Vary", "Origin")
+	}
+	w.Header().Set("Access-Control-Allow-Origin", value)
+}
+
+// isOriginAllowed reports whether a browser request from this origin may use the API.
+// Origin のないリクエスト（curl や OBS 以外のツール）と同一オリジンは常に許可する
+func isOriginAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	return allowedOriginFor(r) != ""
+}
+
+// writePreflight answers a CORS preflight; 許可されていないオリジンからのプリフライトは 403 で拒否する
+func writePreflight(w http.ResponseWriter, r *http.Request) {
+	if !isOriginAllowed(r) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,174 @@
+package webserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	twitch "github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/nantokaworks/twitch-overlay/internal/output"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"github.com/nantokaworks/twitch-overlay/internal/twitcheventsub"
+	"go.uber.org/zap"
+)
+
+const defaultDemoDelay = 3 * time.Second
+
+// demoStep is one scripted event of the demo sequence
+type demoStep struct {
+	name string
+	run  func() error
+}
+
+var (
+	demoMu          sync.Mutex
+	demoCancel      context.CancelFunc
+	demoCurrentStep string
+)
+
+var demoSteps = []demoStep{
+	{"follow", func() error {
+		twitcheventsub.HandleChannelFollow(twitch.EventChannelFollow{
+			User:       twitch.User{UserID: "demo-follower", UserLogin: "demofollower", UserName: "DemoFollower"},
+			FollowedAt: time.Now(),
+		})
+		return nil
+	}},
+	{"cheer", func() error {
+		twitcheventsub.HandleChannelCheer(twitch.EventChannelCheer{
+			User: twitch.User{UserID: "demo-cheerer", UserLogin: "democheerer", UserName: "DemoCheerer"},
+			Bits: 1000,
+		})
+		return nil
+	}},
+	{"subscribe", func() error {
+		twitcheventsub.HandleChannelSubscribe(twitch.EventChannelSubscribe{
+			User: twitch.User{UserID: "demo-subscriber", UserLogin: "demosubscriber", UserName: "DemoSubscriber"},
+			Tier: "1000",
+		})
+		return nil
+	}},
+	{"raid", func() error {
+		twitcheventsub.HandleChannelRaid(twitch.EventChannelRaid{
+			FromBroadcaster: twitch.FromBroadcaster{
+				FromBroadcasterUserId:    "demo-raider",
+				FromBroadcasterUserLogin: "demoraider",
+				FromBroadcasterUserName:  "DemoRaider",
+			},
+			Viewers: 42,
+		})
+		return nil
+	}},
+	{"fax", func() error {
+		fragments := []twitch.ChatMessageFragment{
+			{Type: "text", Text: "デモFAXです！ This is a demo fax."},
+		}
+		return output.PrintOut("DemoViewer", fragments, time.Now())
+	}},
+	{"clock", func() error {
+		return output.PrintClockWithOptions(time.Now().Format("15:04"), false)
+	}},
+}
+
+// handleDebugDemo starts (POST), stops (DELETE) or reports (GET) the demo sequence
+func handleDebugDemo(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		demoMu.Lock()
+		running, step := demoCancel != nil, demoCurrentStep
+		demoMu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"running": running,
+			"step":    step,
+		})
+
+	case http.MethodPost:
+		var req struct {
+			DelayMs int `json:"delay_ms"`
+		}
+		if r.ContentLength > 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+		}
+		delay := defaultDemoDelay
+		if req.DelayMs > 0 {
+			delay = time.Duration(req.DelayMs) * time.Millisecond
+		}
+
+		demoMu.Lock()
+		if demoCancel != nil {
+			demoMu.Unlock()
+			http.Error(w, "Demo is already running", http.StatusConflict)
+			return
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		demoCancel = cancel
+		demoMu.Unlock()
+
+		go runDemo(ctx, delay)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "started",
+			"steps":    len(demoSteps),
+			"delay_ms": delay.Milliseconds(),
+		})
+
+	case http.MethodDelete:
+		demoMu.Lock()
+		cancel := demoCancel
+		demoMu.Unlock()
+
+		if cancel == nil {
+			http.Error(w, "Demo is not running", http.StatusNotFound)
+			return
+		}
+		cancel()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "stopping"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// runDemo fires the demo steps in order until finished or cancelled
+func runDemo(ctx context.Context, delay time.Duration) {
+	defer func() {
+		demoMu.Lock()
+		demoCancel() // 正常終了時もコンテキストを解放する
+		demoCancel = nil
+		demoCurrentStep = ""
+		demoMu.Unlock()
+	}()
+
+	logger.Info("Demo sequence started", zap.Int("steps", len(demoSteps)), zap.Duration("delay", delay))
+
+	for i, step := range demoSteps {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				logger.Info("Demo sequence stopped", zap.String("next_step", step.name))
+				return
+			case <-time.After(delay):
+			}
+		}
+
+		demoMu.Lock()
+		demoCurrentStep = step.name
+		demoMu.Unlock()
+
+		if err := step.run(); err != nil {
+			logger.Warn("Demo step failed", zap.String("step", step.name), zap.Error(err))
+		}
+	}
+
+	logger.Info("Demo sequence finished")
+}
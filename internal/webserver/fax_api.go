@@ -0,0 +1,194 @@
+package webserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/faxmanager"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// faxToJSON converts a fax to the shape used by the SSE "fax" event
+func faxToJSON(fax *faxmanager.Fax) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          fax.ID,
+		"timestamp":   fax.Timestamp.Unix() * 1000, // JavaScriptのミリ秒に変換
+		"username":    fax.UserName,
+		"displayName": fax.UserName,
+		"message":     fax.Message,
+		"imageUrl":    fmt.Sprintf("/fax/%s/color", fax.ID),
+		"permalink":   fmt.Sprintf("/fax/%s", fax.ID),
+		"featured":    fax.Featured,
+	}
+}
+
+// handleFaxes routes /api/faxes/featured and /api/faxes/{id}/feature
+func handleFaxes(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/faxes/"), "/")
+	parts := strings.Split(path, "/")
+
+	switch {
+	case path == "featured":
+		handleFeaturedFax(w, r)
+	case len(parts) == 2 && parts[1] == "feature":
+		handleFeatureFax(w, r, parts[0])
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// handleFeaturedFax returns (GET) or clears (DELETE) the featured fax
+func handleFeaturedFax(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		fax, ok := faxmanager.GetFeatured()
+		if !ok {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"featured": nil,
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"featured": faxToJSON(fax),
+		})
+	case http.MethodDelete:
+		faxmanager.ClearFeatured()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+		})
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleFeatureFax marks a fax as the featured "fax of the day"
+func handleFeatureFax(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	fax, err := faxmanager.SetFeatured(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":  true,
+		"featured": faxToJSON(fax),
+	})
+}
+
+// handleFaxHistory returns past faxes newest first (GET /api/fax/history?limit=&before=).
+// before には前のページの next_before を渡す。画像が削除済みの FAX は imageUrl が空になる
+func handleFaxHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := 20 // デフォルト20件
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	entries, next, err := faxmanager.ListHistory(limit, r.URL.Query().Get("before"))
+	if errors.Is(err, faxmanager.ErrCursorNotFound) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to list fax history", zap.Error(err))
+		http.Error(w, "Failed to load fax history", http.StatusInternalServerError)
+		return
+	}
+
+	faxes := make([]map[string]interface{}, 0, len(entries))
+	for i := range entries {
+		item := faxToJSON(&entries[i].Fax)
+		if !entries[i].ImageAvailable {
+			item["imageUrl"] = ""
+		}
+		item["imageAvailable"] = entries[i].ImageAvailable
+		faxes = append(faxes, item)
+	}
+
+	var nextBefore interface{}
+	if next != "" {
+		nextBefore = next
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"faxes":       faxes,
+		"next_before": nextBefore,
+	})
+}
+
+// faxStatsTop は統計で返す上位ユーザーの人数
+const faxStatsTop = 10
+
+// handleFaxStats returns fax counts and top contributors for today / this week / this month
+// (GET /api/stats/faxes?range=day|week|month、TIMEZONE の暦で区切る)
+func handleFaxStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	loc, err := time.LoadLocation(env.Value.TimeZone)
+	if err != nil {
+		loc = time.Local
+	}
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	rangeName := r.URL.Query().Get("range")
+	var since time.Time
+	switch rangeName {
+	case "", "day":
+		rangeName = "day"
+		since = today
+	case "week":
+		// 月曜日始まり
+		since = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	case "month":
+		since = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	default:
+		http.Error(w, "Invalid range. Use 'day', 'week' or 'month'", http.StatusBadRequest)
+		return
+	}
+
+	stats, err := faxmanager.HistoryStats(since, loc, faxStatsTop)
+	if err != nil {
+		logger.Error("Failed to aggregate fax stats", zap.Error(err))
+		http.Error(w, "Failed to aggregate fax stats", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"range":            rangeName,
+		"since":            stats.Since,
+		"total":            stats.Total,
+		"unique_users":     stats.UniqueUsers,
+		"daily":            stats.Daily,
+		"top_contributors": stats.TopContributors,
+	})
+}
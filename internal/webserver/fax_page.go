@@ -0,0 +1,96 @@
+package webserver
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/nantokaworks/twitch-overlay/internal/faxmanager"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// faxPageTemplate is the shareable permalink page for a single fax.
+// SNS でリンクを展開したときに画像が出るよう OpenGraph / Twitter Card のタグを入れる
+var faxPageTemplate = template.Must(template.New("fax").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>FAX from {{.UserName}}</title>
+<meta property="og:type" content="article">
+<meta property="og:title" content="FAX from {{.UserName}}">
+<meta property="og:description" content="{{.Message}}">
+{{if .ImageAvailable}}<meta property="og:image" content="{{.ImageURL}}">
+{{end}}<meta property="og:url" content="{{.PageURL}}">
+<meta name="twitter:card" content="{{if .ImageAvailable}}summary_large_image{{else}}summary{{end}}">
+<style>
+body { margin: 0; padding: 24px; background: #f4f4f4; font-family: sans-serif; color: #222; }
+main { max-width: 480px; margin: 0 auto; background: #fff; padding: 16px; box-shadow: 0 1px 4px rgba(0,0,0,.15); }
+img { display: block; width: 100%; image-rendering: pixelated; }
+.user { font-weight: bold; margin: 12px 0 4px; }
+.message { white-space: pre-wrap; word-break: break-word; margin: 0 0 8px; }
+time { color: #777; font-size: 0.85em; }
+</style>
+</head>
+<body>
+<main>
+{{if .ImageAvailable}}<img src="{{.ImagePath}}" alt="FAX from {{.UserName}}">
+{{end}}<p class="user">{{.UserName}}</p>
+<p class="message">{{.Message}}</p>
+<time datetime="{{.Timestamp}}">{{.TimestampDisplay}}</time>
+</main>
+</body>
+</html>
+`))
+
+// handleFaxPage renders GET /fax/{id} as an HTML page with the fax image and metadata
+func handleFaxPage(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 画像は保存から10分で削除されるが、ページ自体は fax_history の記録から表示し続ける
+	fax, ok := faxmanager.LookupFax(id)
+	if !ok {
+		http.Error(w, "Fax not found", http.StatusNotFound)
+		return
+	}
+
+	// og:image は絶対URLである必要がある（リバースプロキシ経由なら X-Forwarded-* を使う）
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	host := r.Host
+	if fwdHost := r.Header.Get("X-Forwarded-Host"); fwdHost != "" {
+		host = fwdHost
+	}
+	base := scheme + "://" + host
+	imagePath := "/fax/" + fax.ID + "/color"
+	imageAvailable := fax.ImageAvailable
+
+	data := map[string]interface{}{
+		"UserName":         fax.UserName,
+		"Message":          fax.Message,
+		"ImagePath":        imagePath,
+		"ImageAvailable":   imageAvailable,
+		"ImageURL":         base + imagePath,
+		"PageURL":          base + "/fax/" + fax.ID,
+		"Timestamp":        fax.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
+		"TimestampDisplay": fax.Timestamp.Format("2006/01/02 15:04:05"),
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if imageAvailable {
+		w.Header().Set("Cache-Control", "public, max-age=600") // 画像と同じく10分
+	} else {
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+	}
+	if err := faxPageTemplate.Execute(w, data); err != nil {
+		logger.Error("Failed to render fax page", zap.String("id", id), zap.Error(err))
+	}
+}
@@ -0,0 +1,104 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nantokaworks/twitch-overlay/internal/faxmanager"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+const faxWSWriteTimeout = 10 * time.Second
+
+// handleFaxWebSocket delivers the same events as /events over a WebSocket (/ws/fax).
+// SSE のクライアントと同じ sseServer に登録するので、どちらのトランスポートにも全てのイベントが届く。
+// ?since=<seq> を付けると切断中に届いた FAX を再送する
+func handleFaxWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Error("Failed to upgrade to WebSocket", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	clientChan := make(chan sseEvent, 10)
+	client := newStreamClient(r, transportWebSocket)
+	sseServer.mu.Lock()
+	sseServer.clients[clientChan] = client
+	sseServer.mu.Unlock()
+
+	defer func() {
+		sseServer.mu.Lock()
+		delete(sseServer.clients, clientChan)
+		close(clientChan)
+		sseServer.mu.Unlock()
+	}()
+
+	logger.Info("Fax WebSocket client connected", zap.String("remote", r.RemoteAddr))
+
+	write := func(data []byte) error {
+		conn.SetWriteDeadline(time.Now().Add(faxWSWriteTimeout))
+		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+			return err
+		}
+		client.touch()
+		return nil
+	}
+
+	connected, _ := json.Marshal(map[string]interface{}{
+		"type":    "connected",
+		"last_id": faxmanager.LatestHistorySeq(),
+	})
+	if err := write(connected); err != nil {
+		return
+	}
+
+	replayed := int64(0)
+	if since, resume := sseResumePosition(r); resume {
+		replayed = replayFaxes(since, func(seq int64, data []byte) error {
+			return write(data)
+		})
+	}
+
+	// クライアントからのメッセージは使わないが、切断（close / pong）を検知するために読み続ける
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(clientHeartbeatInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case msg := <-clientChan:
+			if msg.id > 0 && msg.id <= replayed {
+				// 再送済み
+				continue
+			}
+			if err := write([]byte(msg.data)); err != nil {
+				logger.Info("Fax WebSocket client disconnected", zap.String("remote", r.RemoteAddr), zap.Error(err))
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(faxWSWriteTimeout)); err != nil {
+				return
+			}
+			client.touch()
+		case <-client.stop:
+			logger.Info("Fax WebSocket client pruned", zap.String("remote", r.RemoteAddr))
+			return
+		case <-closed:
+			logger.Info("Fax WebSocket client disconnected", zap.String("remote", r.RemoteAddr))
+			return
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package webserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/fontmanager"
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
+	"github.com/nantokaworks/twitch-overlay/internal/twitchtoken"
+)
+
+// healthCheckTimeout は DB への ping の上限（監視から頻繁に呼ばれるので短くする）
+const healthCheckTimeout = 2 * time.Second
+
+// componentHealth is the status of one subsystem in /healthz
+type componentHealth struct {
+	Healthy bool   `json:"healthy"`
+	Message string `json:"message,omitempty"`
+}
+
+// handleHealthz reports whether the core subsystems are usable (GET /healthz).
+// 全て正常なら 200、一つでも異常なら 503。状態は読むだけで、トークンの更新などは行わない
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	setAllowOrigin(w, r)
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	components := map[string]componentHealth{
+		"database": checkDatabaseHealth(r.Context()),
+		"font":     checkFontHealth(),
+	}
+	// トークンは DB から読むので、DB が使えないときは確認できない
+	if components["database"].Healthy {
+		components["twitch_token"] = checkTwitchTokenHealth()
+	} else {
+		components["twitch_token"] = componentHealth{Message: "database unavailable"}
+	}
+
+	healthy := true
+	for _, c := range components {
+		healthy = healthy && c.Healthy
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if !healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"healthy":    healthy,
+		"components": components,
+		"timestamp":  time.Now(),
+	})
+}
+
+func checkDatabaseHealth(ctx context.Context) componentHealth {
+	db := localdb.GetDB()
+	if db == nil {
+		return componentHealth{Message: "database not initialized"}
+	}
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return componentHealth{Message: err.Error()}
+	}
+	return componentHealth{Healthy: true}
+}
+
+func checkFontHealth() componentHealth {
+	info := fontmanager.GetCurrentFontInfo()
+	if hasFont, _ := info["hasCustomFont"].(bool); !hasFont {
+		// ALLOW_SYSTEM_FONT でOSのフォントを使える場合は正常
+		if fontmanager.SystemFontAvailable() {
+			return componentHealth{Healthy: true, Message: "system font"}
+		}
+		return componentHealth{Message: "no font uploaded"}
+	}
+	if invalid, _ := info["invalid"].(bool); invalid {
+		msg, _ := info["error"].(string)
+		return componentHealth{Message: "font is invalid: " + msg}
+	}
+	filename, _ := info["filename"].(string)
+	return componentHealth{Healthy: true, Message: filename}
+}
+
+func checkTwitchTokenHealth() componentHealth {
+	token, valid, err := twitchtoken.GetLatestToken()
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return componentHealth{Message: "no token (authorize at /auth)"}
+	case err != nil:
+		return componentHealth{Message: err.Error()}
+	case !valid:
+		return componentHealth{Message: "token expired at " + time.Unix(token.ExpiresAt, 0).Format(time.RFC3339)}
+	}
+	return componentHealth{Healthy: true, Message: "expires at " + time.Unix(token.ExpiresAt, 0).Format(time.RFC3339)}
+}
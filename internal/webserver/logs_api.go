@@ -5,33 +5,94 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
+	"github.com/nantokaworks/twitch-overlay/internal/settings"
 	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
 	"go.uber.org/zap"
 )
 
 var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		// 開発環境では全てのオリジンを許可
-		// TODO: 本番環境では適切なオリジンチェックを実装
+	// ALLOWED_ORIGINS で許可されたオリジン（と同一オリジン）のみ接続を受け付ける
+	CheckOrigin: isOriginAllowed,
+}
+
+// logFilter selects log entries by level and substring (?level=error,warn&contains=printer)
+type logFilter struct {
+	levels   map[string]bool // 空なら全レベル
+	contains string          // 小文字化済み。メッセージとフィールドの値から探す
+}
+
+func parseLogFilter(r *http.Request) logFilter {
+	f := logFilter{contains: strings.ToLower(r.URL.Query().Get("contains"))}
+	for _, level := range strings.Split(r.URL.Query().Get("level"), ",") {
+		level = strings.ToLower(strings.TrimSpace(level))
+		if level == "" || level == "all" {
+			continue
+		}
+		if level == "warning" {
+			level = "warn"
+		}
+		if f.levels == nil {
+			f.levels = make(map[string]bool)
+		}
+		f.levels[level] = true
+	}
+	return f
+}
+
+func (f logFilter) matches(entry logger.LogEntry) bool {
+	if f.levels != nil && !f.levels[strings.ToLower(entry.Level)] {
+		return false
+	}
+	if f.contains == "" || strings.Contains(strings.ToLower(entry.Message), f.contains) {
 		return true
-	},
+	}
+	for _, v := range entry.Fields {
+		if strings.Contains(strings.ToLower(fmt.Sprint(v)), f.contains) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterRecent returns the last limit entries that match the filter
+func (f logFilter) filterRecent(entries []logger.LogEntry, limit int) []logger.LogEntry {
+	result := make([]logger.LogEntry, 0, limit)
+	for i := len(entries) - 1; i >= 0 && len(result) < limit; i-- {
+		if f.matches(entries[i]) {
+			result = append(result, entries[i])
+		}
+	}
+	// 古い順に戻す
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
+
+// logSubscription is a WebSocket client and the entries it wants to receive
+type logSubscription struct {
+	conn   *websocket.Conn
+	filter logFilter
 }
 
 // WebSocket接続を管理
 type LogStreamer struct {
-	clients map[*websocket.Conn]bool
+	clients map[*websocket.Conn]logFilter
 	broadcast chan logger.LogEntry
-	register chan *websocket.Conn
+	register chan logSubscription
 	unregister chan *websocket.Conn
 }
 
 var logStreamer = &LogStreamer{
-	clients:    make(map[*websocket.Conn]bool),
+	clients:    make(map[*websocket.Conn]logFilter),
 	broadcast:  make(chan logger.LogEntry),
-	register:   make(chan *websocket.Conn),
+	register:   make(chan logSubscription),
 	unregister: make(chan *websocket.Conn),
 }
 
@@ -47,8 +108,8 @@ func init() {
 func (ls *LogStreamer) run() {
 	for {
 		select {
-		case client := <-ls.register:
-			ls.clients[client] = true
+		case sub := <-ls.register:
+			ls.clients[sub.conn] = sub.filter
 			logger.Info("WebSocket client connected for logs")
 
 		case client := <-ls.unregister:
@@ -59,7 +120,10 @@ func (ls *LogStreamer) run() {
 			}
 
 		case entry := <-ls.broadcast:
-			for client := range ls.clients {
+			for client, filter := range ls.clients {
+				if !filter.matches(entry) {
+					continue
+				}
 				err := client.WriteJSON(entry)
 				if err != nil {
 					client.Close()
@@ -79,7 +143,7 @@ func BroadcastLog(entry logger.LogEntry) {
 	}
 }
 
-// handleLogs returns recent logs
+// handleLogs returns recent logs (?limit=, ?level=, ?contains= で絞り込み)
 func handleLogs(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -95,9 +159,9 @@ func handleLogs(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// ログバッファから取得
+	// ログバッファから取得（絞り込んでから最新 limit 件）
 	buffer := logger.GetLogBuffer()
-	logs := buffer.GetRecent(limit)
+	logs := parseLogFilter(r).filterRecent(buffer.GetAll(), limit)
 
 	// レスポンス
 	response := map[string]interface{}{
@@ -148,8 +212,10 @@ func handleLogsDownload(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleLogsStream provides real-time log streaming via WebSocket
+// handleLogsStream provides real-time log streaming via WebSocket.
+// ?level= と ?contains= を指定すると一致するログだけを送る
 func handleLogsStream(w http.ResponseWriter, r *http.Request) {
+	filter := parseLogFilter(r)
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		logger.Error("Failed to upgrade to WebSocket", zap.Error(err))
@@ -157,11 +223,11 @@ func handleLogsStream(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// クライアントを登録
-	logStreamer.register <- conn
+	logStreamer.register <- logSubscription{conn: conn, filter: filter}
 
 	// 最近のログを送信
 	buffer := logger.GetLogBuffer()
-	recentLogs := buffer.GetRecent(50)
+	recentLogs := filter.filterRecent(buffer.GetAll(), 50)
 	for _, log := range recentLogs {
 		if err := conn.WriteJSON(log); err != nil {
 			break
@@ -201,4 +267,44 @@ func handleLogsClear(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
-}
\ No newline at end of file
+}
+// handleLogsLevel returns (GET) or changes (POST {"level": "debug"}) the log level.
+// 変更は LOG_LEVEL 設定として保存されるので再起動後も維持される
+func handleLogsLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var req struct {
+			Level string `json:"level"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		level := strings.ToLower(strings.TrimSpace(req.Level))
+		if err := settings.ValidateSetting("LOG_LEVEL", level); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		settingsManager := settings.NewSettingsManager(localdb.GetDB())
+		if err := settingsManager.SetSetting("LOG_LEVEL", level); err != nil {
+			logger.Error("Failed to save log level", zap.Error(err))
+			http.Error(w, "Failed to save log level", http.StatusInternalServerError)
+			return
+		}
+		// env.Subscribe したハンドラーがロガーに反映する
+		if err := env.ReloadFromDatabase(); err != nil {
+			logger.Warn("Failed to reload env values from database", zap.Error(err))
+		}
+		logger.Info("Log level changed", zap.String("level", logger.GetLevel()))
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"level": logger.GetLevel(),
+	})
+}
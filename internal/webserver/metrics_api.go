@@ -0,0 +1,68 @@
+package webserver
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/nantokaworks/twitch-overlay/internal/output"
+	"github.com/nantokaworks/twitch-overlay/internal/twitchapi"
+)
+
+// metric is one sample in the Prometheus text exposition format
+type metric struct {
+	name   string
+	kind   string // counter / gauge
+	help   string
+	labels string // 例: `transport="sse"`
+	value  int64
+}
+
+// handleMetrics exposes counters for monitoring in Prometheus text format (GET /metrics)
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	fax := output.GetFaxCounters()
+	emoteHits, emoteMisses := output.EmoteCacheStats()
+	apiCalls, apiErrors := twitchapi.APICallCounts()
+
+	events, musicControl := connectedClients()
+	clientCounts := map[string]int64{}
+	for _, c := range append(events, musicControl...) {
+		clientCounts[c.transport]++
+	}
+
+	metrics := []metric{
+		{"twitch_overlay_faxes_printed_total", "counter", "Faxes printed since startup.", "", fax.Printed},
+		{"twitch_overlay_prints_failed_total", "counter", "Prints that failed (setup, connect or print error).", "", fax.Failed},
+		{"twitch_overlay_faxes_dropped_total", "counter", "Faxes dropped because the print queue was full.", "", fax.Dropped},
+		{"twitch_overlay_faxes_blocked_total", "counter", "Faxes not printed because of the settings.", "", fax.Blocked},
+		{"twitch_overlay_print_queue_depth", "gauge", "Jobs waiting in the print queue.", "", int64(output.GetPrintQueueSize())},
+		{"twitch_overlay_emote_cache_hits_total", "counter", "Emote cache hits.", "", emoteHits},
+		{"twitch_overlay_emote_cache_misses_total", "counter", "Emote cache misses.", "", emoteMisses},
+		{"twitch_overlay_connected_clients", "gauge", "Connected overlay clients by transport.", `transport="sse"`, clientCounts[transportSSE]},
+		{"twitch_overlay_connected_clients", "gauge", "", `transport="websocket"`, clientCounts[transportWebSocket]},
+		{"twitch_overlay_connected_clients", "gauge", "", `transport="music_control"`, clientCounts[transportMusicControl]},
+		{"twitch_overlay_twitch_api_calls_total", "counter", "Twitch Helix API requests sent.", "", apiCalls},
+		{"twitch_overlay_twitch_api_errors_total", "counter", "Twitch Helix API requests that failed.", "", apiErrors},
+	}
+
+	var b strings.Builder
+	for _, m := range metrics {
+		// 同じ名前のラベル違いは HELP / TYPE を一度だけ書く
+		if m.help != "" {
+			fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		}
+		if m.labels != "" {
+			fmt.Fprintf(&b, "%s{%s} %d\n", m.name, m.labels, m.value)
+		} else {
+			fmt.Fprintf(&b, "%s %d\n", m.name, m.value)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
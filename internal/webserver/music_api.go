@@ -2,10 +2,12 @@ package webserver
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/nantokaworks/twitch-overlay/internal/music"
@@ -19,9 +21,16 @@ func handleMusicUpload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse multipart form (50MB limit)
-	err := r.ParseMultipartForm(50 << 20)
+	// Parse multipart form (MUSIC_MAX_FILE_MB + フォームの余白分まで受け付ける)
+	maxSize := music.MaxFileSize()
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+(1<<20))
+	err := r.ParseMultipartForm(maxSize)
 	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			http.Error(w, fmt.Sprintf("File too large (max %dMB)", maxSize>>20), http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, "Failed to parse form", http.StatusBadRequest)
 		return
 	}
@@ -37,14 +46,24 @@ func handleMusicUpload(w http.ResponseWriter, r *http.Request) {
 	// Save the track
 	manager := music.GetManager()
 	track, err := manager.SaveTrack(header.Filename, file, header.Size)
+	if errors.Is(err, music.ErrDuplicate) {
+		// 同じ内容のファイルは既に登録済み。既存の曲を返す
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Track already exists",
+			"track": track,
+		})
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to save track", zap.Error(err))
 		
 		switch err {
 		case music.ErrFileTooLarge:
-			http.Error(w, "File too large (max 50MB)", http.StatusRequestEntityTooLarge)
+			http.Error(w, fmt.Sprintf("File too large (max %dMB)", music.MaxFileSize()>>20), http.StatusRequestEntityTooLarge)
 		case music.ErrInvalidFormat:
-			http.Error(w, "Invalid audio format (only MP3/WAV/M4A/OGG supported)", http.StatusBadRequest)
+			http.Error(w, fmt.Sprintf("Invalid audio format (only %s supported)", music.AllowedFormatNames()), http.StatusBadRequest)
 		default:
 			http.Error(w, "Failed to save track", http.StatusInternalServerError)
 		}
@@ -94,6 +113,47 @@ func handleGetTracks(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// GET /api/music/tracks/search?q=&artist=&album=&limit=&offset=
+func handleSearchTracks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	search := music.TrackSearch{
+		Query:  strings.TrimSpace(query.Get("q")),
+		Artist: strings.TrimSpace(query.Get("artist")),
+		Album:  strings.TrimSpace(query.Get("album")),
+		Limit:  50, // デフォルト50件
+	}
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 {
+		search.Limit = l
+	}
+	if search.Limit > 500 {
+		search.Limit = 500
+	}
+	if o, err := strconv.Atoi(query.Get("offset")); err == nil && o > 0 {
+		search.Offset = o
+	}
+
+	tracks, total, err := music.GetManager().SearchTracks(search)
+	if err != nil {
+		logger.Error("Failed to search tracks", zap.Error(err))
+		http.Error(w, "Failed to search tracks", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"tracks": tracks,
+		"count":  len(tracks),
+		"total":  total,
+		"limit":  search.Limit,
+		"offset": search.Offset,
+	})
+}
+
 func handleGetTrack(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -115,7 +175,7 @@ func handleGetTrack(w http.ResponseWriter, r *http.Request) {
 		switch pathParts[1] {
 		case "audio":
 			// Serve audio file
-			trackPath, err := manager.GetTrackPath(trackID)
+			trackPath, err := manager.GetPlaybackPath(trackID)
 			if err != nil {
 				http.Error(w, "Track not found", http.StatusNotFound)
 				return
@@ -134,15 +194,7 @@ func handleGetTrack(w http.ResponseWriter, r *http.Request) {
 			
 			// Determine content type
 			ext := strings.ToLower(trackPath[strings.LastIndex(trackPath, "."):])
-			contentType := "audio/mpeg"
-			switch ext {
-			case ".wav":
-				contentType = "audio/wav"
-			case ".ogg":
-				contentType = "audio/ogg"
-			case ".m4a":
-				contentType = "audio/mp4"
-			}
+			contentType := music.ContentTypeFor(ext)
 
 			// Set headers for audio streaming
 			w.Header().Set("Content-Type", contentType)
@@ -172,6 +224,31 @@ func handleGetTrack(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Cache-Control", "public, max-age=86400")
 			http.ServeFile(w, r, artworkPath)
 
+		case "waveform":
+			// 波形のピークデータ（初回にデコードしてキャッシュする）
+			buckets := music.DefaultWaveformBuckets
+			if n, err := strconv.Atoi(r.URL.Query().Get("buckets")); err == nil && n > 0 {
+				buckets = n
+			}
+			data, err := manager.WaveformJSON(trackID, buckets)
+			if errors.Is(err, music.ErrNotFound) {
+				http.Error(w, "Track not found", http.StatusNotFound)
+				return
+			}
+			if errors.Is(err, music.ErrFFmpegUnavailable) {
+				http.Error(w, "Waveform for this format requires ffmpeg", http.StatusNotImplemented)
+				return
+			}
+			if err != nil {
+				logger.Error("Failed to generate waveform", zap.String("id", trackID), zap.Error(err))
+				http.Error(w, "Failed to generate waveform", http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Cache-Control", "public, max-age=86400")
+			w.Write(data)
+
 		default:
 			http.Error(w, "Invalid resource type", http.StatusBadRequest)
 		}
@@ -188,6 +265,49 @@ func handleGetTrack(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// PUT /api/music/track/{id}
+// body: {"title": "...", "artist": "...", "album": "..."}（省略・空文字のフィールドは変更しない）
+func handleUpdateTrack(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	trackID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/music/track/"), "/")
+	if trackID == "" || strings.Contains(trackID, "/") {
+		http.Error(w, "Track ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Title  string `json:"title"`
+		Artist string `json:"artist"`
+		Album  string `json:"album"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Title) == "" && strings.TrimSpace(req.Artist) == "" && strings.TrimSpace(req.Album) == "" {
+		http.Error(w, "At least one of title, artist, album is required", http.StatusBadRequest)
+		return
+	}
+
+	track, err := music.GetManager().UpdateTrackMetadata(trackID, req.Title, req.Artist, req.Album)
+	if errors.Is(err, music.ErrNotFound) {
+		http.Error(w, "Track not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to update track metadata", zap.Error(err))
+		http.Error(w, "Failed to update track", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(track)
+}
+
 func handleDeleteTrack(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -304,6 +424,27 @@ func handleCreatePlaylist(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(playlist)
 }
 
+// POST /api/music/playlists/repair
+func handleRepairPlaylists(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	result, err := music.GetManager().RepairPlaylists()
+	if err != nil {
+		logger.Error("Failed to repair playlists", zap.Error(err))
+		http.Error(w, "Failed to repair playlists", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "ok",
+		"result": result,
+	})
+}
+
 func handleGetPlaylist(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -331,6 +472,17 @@ func handleGetPlaylist(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if len(pathParts) >= 2 && pathParts[1] == "export" {
+		handleExportPlaylist(w, playlist)
+		return
+	}
+
+	// 次/前の曲を解決（オーバーレイとサーバー側の送りで順序ロジックを共有する）
+	if len(pathParts) >= 2 && (pathParts[1] == "next" || pathParts[1] == "previous") {
+		handlePlaylistNeighbor(w, r, playlist, pathParts[1])
+		return
+	}
+
 	// Check if requesting tracks
 	if len(pathParts) >= 2 && pathParts[1] == "tracks" {
 		tracks, err := manager.GetPlaylistTracks(playlist.ID)
@@ -351,6 +503,96 @@ func handleGetPlaylist(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// GET /api/music/playlist/{id}/export
+func handleExportPlaylist(w http.ResponseWriter, playlist *music.Playlist) {
+	export, err := music.GetManager().ExportPlaylist(playlist.ID)
+	if err != nil {
+		logger.Error("Failed to export playlist", zap.Error(err))
+		http.Error(w, "Failed to export playlist", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "playlist-"+playlist.ID+".json"))
+	json.NewEncoder(w).Encode(export)
+}
+
+// POST /api/music/playlist/import?name=
+// body はエクスポートした JSON。name を指定すると別名で取り込む
+func handleImportPlaylist(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var export music.PlaylistExport
+	if err := json.NewDecoder(r.Body).Decode(&export); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := music.GetManager().ImportPlaylist(&export, strings.TrimSpace(r.URL.Query().Get("name")))
+	if errors.Is(err, music.ErrPlaylistExists) {
+		http.Error(w, "Playlist with the same name already exists", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to import playlist", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GET /api/music/playlist/{id}/next?after={trackId}&shuffle=true&repeat=all
+// GET /api/music/playlist/{id}/previous?after={trackId}
+// shuffle/repeat を省略した場合は /api/music/control/shuffle, /repeat で設定した現在のモードを使うので、
+// 複数のオーバーレイが同じ順序で曲を送れる
+func handlePlaylistNeighbor(w http.ResponseWriter, r *http.Request, playlist *music.Playlist, direction string) {
+	query := r.URL.Query()
+	mode := currentSequenceMode()
+	if query.Has("shuffle") {
+		mode.Shuffle = query.Get("shuffle") == "true"
+	}
+	if query.Has("repeat") {
+		mode.Repeat = query.Get("repeat")
+	}
+	switch mode.Repeat {
+	case "":
+		mode.Repeat = music.RepeatAll
+	case repeatOff:
+		mode.Repeat = music.RepeatNone
+	case music.RepeatNone, music.RepeatAll, music.RepeatOne:
+	default:
+		http.Error(w, "Invalid repeat mode (off, none, all, one)", http.StatusBadRequest)
+		return
+	}
+
+	step := 1
+	if direction == "previous" {
+		step = -1
+	}
+
+	manager := music.GetManager()
+	tracks, err := manager.PlaylistTrackList(playlist.ID)
+	if err != nil {
+		logger.Error("Failed to get playlist tracks", zap.Error(err))
+		http.Error(w, "Failed to get playlist tracks", http.StatusInternalServerError)
+		return
+	}
+
+	track := music.NeighborTrack(tracks, query.Get("after"), step, mode)
+	if track == nil {
+		http.Error(w, "No more tracks", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(track)
+}
+
 func handleUpdatePlaylist(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -454,10 +696,13 @@ func RegisterMusicRoutes(mux *http.ServeMux) {
 	// Track endpoints
 	mux.HandleFunc("/api/music/upload", corsMiddleware(handleMusicUpload))
 	mux.HandleFunc("/api/music/tracks", corsMiddleware(handleGetTracks))
+	mux.HandleFunc("/api/music/tracks/search", corsMiddleware(handleSearchTracks))
 	mux.HandleFunc("/api/music/track/", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
 			handleGetTrack(w, r)
+		case http.MethodPut:
+			handleUpdateTrack(w, r)
 		case http.MethodDelete:
 			handleDeleteTrack(w, r)
 		default:
@@ -467,7 +712,9 @@ func RegisterMusicRoutes(mux *http.ServeMux) {
 
 	// Playlist endpoints
 	mux.HandleFunc("/api/music/playlists", corsMiddleware(handleGetPlaylists))
+	mux.HandleFunc("/api/music/playlists/repair", corsMiddleware(handleRepairPlaylists))
 	mux.HandleFunc("/api/music/playlist", corsMiddleware(handleCreatePlaylist))
+	mux.HandleFunc("/api/music/playlist/import", corsMiddleware(handleImportPlaylist))
 	mux.HandleFunc("/api/music/playlist/", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
@@ -4,18 +4,25 @@ import (
 	"encoding/json"
 	"net/http"
 	"sync"
+	"time"
 
+	"github.com/nantokaworks/twitch-overlay/internal/music"
 	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
 	"go.uber.org/zap"
 )
 
 type MusicControlCommand struct {
-	Type     string  `json:"type"`     // play, pause, stop, toggle, next, previous, volume, seek, load_playlist
+	Type     string  `json:"type"`     // play, pause, stop, toggle, next, previous, volume, seek, load_playlist, shuffle, repeat
 	Value    int     `json:"value,omitempty"`
 	Time     float64 `json:"time,omitempty"`
 	Playlist string  `json:"playlist,omitempty"`
+	Enabled  *bool   `json:"enabled,omitempty"` // shuffle: 切り替え後の状態
+	Mode     string  `json:"mode,omitempty"`    // repeat: off, one, all
 }
 
+// repeatOff はコントロールAPIでのリピートなし（music.RepeatNone に対応）。one/all は music と同じ値
+const repeatOff = "off"
+
 type MusicStatusUpdate struct {
 	PlaybackStatus string  `json:"playback_status,omitempty"` // playing, paused, stopped
 	IsPlaying      bool    `json:"is_playing"` // 互換性のため残す
@@ -25,6 +32,9 @@ type MusicStatusUpdate struct {
 	Duration       float64 `json:"duration"`
 	Volume         int     `json:"volume"`
 	PlaylistName   *string `json:"playlist_name,omitempty"`
+	GainDB         float64 `json:"gain_db"` // 現在の曲に適用する音量補正（サーバー側でクランプ済み）
+	ShuffleEnabled bool    `json:"shuffle_enabled"`
+	RepeatMode     string  `json:"repeat_mode"` // off, one, all
 }
 
 type Track struct {
@@ -34,28 +44,32 @@ type Track struct {
 	Album    string `json:"album,omitempty"`
 	Duration int    `json:"duration"`
 	HasArtwork bool `json:"has_artwork"`
+	GainDB   float64 `json:"gain_db"`
 }
 
 var (
-	musicControlClients = make(map[chan MusicControlCommand]bool)
+	musicControlClients = make(map[chan MusicControlCommand]*streamClient)
 	musicControlMutex   sync.RWMutex
 	
 	musicStatusClients = make(map[chan MusicStatusUpdate]bool)
 	musicStatusMutex   sync.RWMutex
 	
 	// 現在の音楽再生状態
+	// シャッフル・リピートはサーバー側で管理する（従来の動作に合わせ、初期値はシャッフル＋全曲リピート）
 	currentMusicState = MusicStatusUpdate{
-		IsPlaying: false,
-		Volume:    70,
+		IsPlaying:      false,
+		Volume:         70,
+		ShuffleEnabled: true,
+		RepeatMode:     music.RepeatAll,
 	}
 	musicStateMutex sync.RWMutex
 )
 
 // SSEクライアントを登録
-func addMusicControlClient(client chan MusicControlCommand) {
+func addMusicControlClient(client chan MusicControlCommand, info *streamClient) {
 	musicControlMutex.Lock()
 	defer musicControlMutex.Unlock()
-	musicControlClients[client] = true
+	musicControlClients[client] = info
 	logger.Debug("Music control SSE client connected", zap.Int("total_clients", len(musicControlClients)))
 }
 
@@ -317,22 +331,144 @@ func handleMusicLoad(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
 }
 
+// POST /api/music/control/shuffle
+// body の enabled を省略した場合は現在の状態を切り替える
+func handleMusicShuffle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	musicStateMutex.Lock()
+	enabled := !currentMusicState.ShuffleEnabled
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	currentMusicState.ShuffleEnabled = enabled
+	status := currentMusicState
+	musicStateMutex.Unlock()
+
+	broadcastMusicCommand(MusicControlCommand{Type: "shuffle", Enabled: &enabled})
+	broadcastMusicStatus(status)
+	logger.Info("Music shuffle command sent", zap.Bool("enabled", enabled))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":          "ok",
+		"shuffle_enabled": enabled,
+	})
+}
+
+// POST /api/music/control/repeat
+// body の mode を省略した場合は off → all → one の順に切り替える
+func handleMusicRepeat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Mode string `json:"mode"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+	switch req.Mode {
+	case "", repeatOff, music.RepeatOne, music.RepeatAll:
+	default:
+		http.Error(w, "Mode must be one of off, one, all", http.StatusBadRequest)
+		return
+	}
+
+	musicStateMutex.Lock()
+	mode := req.Mode
+	if mode == "" {
+		mode = nextRepeatMode(currentMusicState.RepeatMode)
+	}
+	currentMusicState.RepeatMode = mode
+	status := currentMusicState
+	musicStateMutex.Unlock()
+
+	broadcastMusicCommand(MusicControlCommand{Type: "repeat", Mode: mode})
+	broadcastMusicStatus(status)
+	logger.Info("Music repeat command sent", zap.String("mode", mode))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":      "ok",
+		"repeat_mode": mode,
+	})
+}
+
+// currentSequenceMode returns the shuffle/repeat mode for choosing the next track on the server
+func currentSequenceMode() music.SequenceMode {
+	state := getCurrentMusicState()
+	mode := music.SequenceMode{Shuffle: state.ShuffleEnabled, Repeat: state.RepeatMode}
+	if mode.Repeat == repeatOff {
+		mode.Repeat = music.RepeatNone
+	}
+	return mode
+}
+
+// nextRepeatMode cycles off → all → one → off
+func nextRepeatMode(mode string) string {
+	switch mode {
+	case repeatOff:
+		return music.RepeatAll
+	case music.RepeatAll:
+		return music.RepeatOne
+	default:
+		return repeatOff
+	}
+}
+
 // SSE: /api/music/control/events
 func handleMusicControlEvents(w http.ResponseWriter, r *http.Request) {
 	// SSEヘッダー設定
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 
 	// クライアントチャンネル作成
 	client := make(chan MusicControlCommand)
-	addMusicControlClient(client)
+	info := newStreamClient(r, transportMusicControl)
+	addMusicControlClient(client, info)
 	defer removeMusicControlClient(client)
 
+	// 接続したプレイヤーに現在のシャッフル・リピート設定を送る
+	state := getCurrentMusicState()
+	shuffle := state.ShuffleEnabled
+	for _, cmd := range []MusicControlCommand{
+		{Type: "shuffle", Enabled: &shuffle},
+		{Type: "repeat", Mode: state.RepeatMode},
+	} {
+		data, _ := json.Marshal(cmd)
+		w.Write([]byte("data: " + string(data) + "\n\n"))
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+
 	// クライアント切断検知
 	ctx := r.Context()
 
+	heartbeat := time.NewTicker(clientHeartbeatInterval)
+	defer heartbeat.Stop()
+
 	for {
 		select {
 		case cmd := <-client:
@@ -353,6 +489,22 @@ func handleMusicControlEvents(w http.ResponseWriter, r *http.Request) {
 			if f, ok := w.(http.Flusher); ok {
 				f.Flush()
 			}
+			info.touch()
+
+		case <-heartbeat.C:
+			// 生存確認（書き込めなければ切断されたとみなす）
+			if _, err := w.Write([]byte(": heartbeat\n\n")); err != nil {
+				logger.Debug("Client disconnected from music control SSE")
+				return
+			}
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+			info.touch()
+
+		case <-info.stop:
+			logger.Debug("Music control SSE client pruned")
+			return
 			
 		case <-ctx.Done():
 			logger.Debug("Music control SSE connection closed")
@@ -374,8 +526,11 @@ func handleMusicStatusUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 曲ごとの音量補正を付与（曲が切り替わればここで更新される）
+	applyTrackGain(&status)
+
 	// 現在の状態を更新
-	updateCurrentMusicState(status)
+	updateCurrentMusicState(&status)
 
 	// 全クライアントに状態を配信
 	broadcastMusicStatus(status)
@@ -385,11 +540,24 @@ func handleMusicStatusUpdate(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
 }
 
+// applyTrackGain sets the clamped per-track gain on a status update
+func applyTrackGain(status *MusicStatusUpdate) {
+	status.GainDB = 0
+	if status.CurrentTrack == nil || status.CurrentTrack.ID == "" {
+		return
+	}
+	status.GainDB = music.GetManager().TrackGain(status.CurrentTrack.ID)
+	status.CurrentTrack.GainDB = status.GainDB
+}
+
 // 現在の音楽状態を更新
-func updateCurrentMusicState(status MusicStatusUpdate) {
+// シャッフル・リピートはサーバー側の値を優先する（status にも反映して配信する）
+func updateCurrentMusicState(status *MusicStatusUpdate) {
 	musicStateMutex.Lock()
 	defer musicStateMutex.Unlock()
-	currentMusicState = status
+	status.ShuffleEnabled = currentMusicState.ShuffleEnabled
+	status.RepeatMode = currentMusicState.RepeatMode
+	currentMusicState = *status
 }
 
 // 現在の音楽状態を取得
@@ -405,7 +573,7 @@ func handleMusicStatusEvents(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 
 	// クライアントチャンネル作成
 	client := make(chan MusicStatusUpdate)
@@ -455,6 +623,8 @@ func RegisterMusicControlRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/music/control/volume", corsMiddleware(handleMusicVolume))
 	mux.HandleFunc("/api/music/control/seek", corsMiddleware(handleMusicSeek))
 	mux.HandleFunc("/api/music/control/load", corsMiddleware(handleMusicLoad))
+	mux.HandleFunc("/api/music/control/shuffle", corsMiddleware(handleMusicShuffle))
+	mux.HandleFunc("/api/music/control/repeat", corsMiddleware(handleMusicRepeat))
 	
 	// SSEエンドポイント
 	mux.HandleFunc("/api/music/control/events", corsMiddleware(handleMusicControlEvents))
@@ -171,6 +171,7 @@ func handlePlaybackStateUpdate(w http.ResponseWriter, r *http.Request) {
 func RegisterPlaybackRoutes(mux *http.ServeMux) {
 	// Initialize playback state on startup
 	InitPlaybackState()
+	startMusicSequencer()
 	
 	// Register routes
 	mux.HandleFunc("/api/music/state/update", corsMiddleware(handlePlaybackStateUpdate))
@@ -0,0 +1,126 @@
+package webserver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/music"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	sequencerInterval = time.Second
+	// sequencerGrace はオーバーレイ自身が次の曲に進むのを待つ猶予
+	// （オーバーレイが開いている場合はそちらが先に状態を更新するため、サーバーは何もしない）
+	sequencerGrace = 3 * time.Second
+)
+
+var sequencerOnce sync.Once
+
+// startMusicSequencer starts the server-side playlist auto-advance (MUSIC_SERVER_SEQUENCING).
+// オーバーレイが閉じていても、曲の長さが経過したら再生状態を次の曲へ進める（ヘッドレスの「ラジオ」モード）
+func startMusicSequencer() {
+	sequencerOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(sequencerInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				if env.Value.MusicServerSequencing {
+					advanceIfTrackEnded()
+				}
+			}
+		}()
+	})
+}
+
+// advanceIfTrackEnded moves the playback state to the next track once the current one has elapsed
+func advanceIfTrackEnded() {
+	playbackStateMutex.RLock()
+	state := currentPlaybackState
+	playbackStateMutex.RUnlock()
+
+	if state == nil || state.TrackID == "" {
+		return
+	}
+	// playback_status がない古いクライアントは is_playing で判定する
+	if state.PlaybackStatus != "playing" && !(state.PlaybackStatus == "" && state.IsPlaying) {
+		return
+	}
+	// 古すぎる状態（handlePlaybackStateGet と同じく24時間）からは再開しない
+	if time.Since(state.UpdatedAt) > 24*time.Hour {
+		return
+	}
+
+	duration := time.Duration(state.Duration * float64(time.Second))
+	elapsed := time.Duration(state.Position*float64(time.Second)) + time.Since(state.UpdatedAt)
+	if duration <= 0 || elapsed < duration+sequencerGrace {
+		return
+	}
+
+	manager := music.GetManager()
+	playlistID := ""
+	if state.PlaylistName != nil && *state.PlaylistName != "" {
+		playlist, err := manager.GetPlaylistByName(*state.PlaylistName)
+		if err != nil {
+			logger.Warn("Sequencer: playlist not found", zap.String("playlist", *state.PlaylistName), zap.Error(err))
+			return
+		}
+		playlistID = playlist.ID
+	}
+
+	tracks, err := manager.PlaylistTrackList(playlistID)
+	if err != nil {
+		logger.Warn("Sequencer: failed to get playlist tracks", zap.Error(err))
+		return
+	}
+	next := music.NeighborTrack(tracks, state.TrackID, 1, currentSequenceMode())
+	if next == nil {
+		return
+	}
+
+	newState := *state
+	newState.TrackID = next.ID
+	newState.Position = 0
+	newState.Duration = float64(next.Duration)
+	newState.UpdatedAt = time.Now()
+
+	playbackStateMutex.Lock()
+	// ティック中にオーバーレイが状態を更新していたら上書きしない
+	if currentPlaybackState != state {
+		playbackStateMutex.Unlock()
+		return
+	}
+	currentPlaybackState = &newState
+	playbackStateMutex.Unlock()
+
+	if err := savePlaybackStateDB(&newState); err != nil {
+		logger.Warn("Sequencer: failed to save playback state", zap.Error(err))
+	}
+
+	status := getCurrentMusicState()
+	status.PlaybackStatus = "playing"
+	status.IsPlaying = true
+	status.CurrentTrack = &Track{
+		ID:         next.ID,
+		Title:      next.Title,
+		Artist:     next.Artist,
+		Album:      next.Album,
+		Duration:   next.Duration,
+		HasArtwork: next.HasArtwork,
+		GainDB:     music.ClampGain(next.GainDB),
+	}
+	status.CurrentTime = 0
+	status.Progress = 0
+	status.Duration = float64(next.Duration)
+	status.PlaylistName = state.PlaylistName
+	status.GainDB = music.ClampGain(next.GainDB)
+	updateCurrentMusicState(&status)
+	broadcastMusicStatus(status)
+
+	logger.Info("Sequencer advanced to next track",
+		zap.String("from", state.TrackID),
+		zap.String("to", next.ID),
+		zap.String("title", next.Title))
+}
@@ -226,7 +226,7 @@ func handleOverlaySettingsEvents(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 
 	// Create client channel
 	clientChan := make(chan string, 10)
@@ -0,0 +1,88 @@
+package webserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	twitch "github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/output"
+	"github.com/nantokaworks/twitch-overlay/internal/settings"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// handlePreviewDiff returns before/after previews of the same sample text.
+// "before" uses the current settings, "after" the proposed values (not saved)
+func handlePreviewDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Text     string            `json:"text"`
+		Settings map[string]string `json:"settings"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Text == "" {
+		req.Text = "サンプルテキスト Sample Text 123"
+	}
+
+	// 指定されなかった項目は現在の設定のまま（FontSize は 0 なら現在の FONT_SIZE）
+	after := output.PreviewOptions{
+		Dither:     env.Value.Dither,
+		BlackPoint: env.Value.BlackPoint,
+	}
+	for key, value := range req.Settings {
+		if err := settings.ValidateSetting(key, value); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid value for %s: %v", key, err), http.StatusBadRequest)
+			return
+		}
+
+		switch key {
+		case "DITHER":
+			after.Dither = value == "true"
+		case "BLACK_POINT":
+			bp, err := strconv.ParseFloat(value, 32)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Invalid value for %s: %v", key, err), http.StatusBadRequest)
+				return
+			}
+			after.BlackPoint = float32(bp)
+		case "FONT_SIZE":
+			size, err := strconv.Atoi(value)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Invalid value for %s: %v", key, err), http.StatusBadRequest)
+				return
+			}
+			after.FontSize = size
+		default:
+			http.Error(w, fmt.Sprintf("Setting %s cannot be previewed", key), http.StatusBadRequest)
+			return
+		}
+	}
+
+	fragments := []twitch.ChatMessageFragment{
+		{Type: "text", Text: req.Text},
+	}
+
+	before, afterImg, err := output.GeneratePreviewDiff("プレビュー", fragments, after)
+	if err != nil {
+		logger.Error("Failed to generate preview diff", zap.Error(err))
+		http.Error(w, "Failed to generate preview", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"before": before,
+		"after":  afterImg,
+	})
+}
@@ -0,0 +1,117 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/nantokaworks/twitch-overlay/internal/printrules"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// handlePrintRules handles listing (GET) and creating (POST) print routing rules
+func handlePrintRules(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		rules, err := printrules.GetAllRules()
+		if err != nil {
+			logger.Error("Failed to get print rules", zap.Error(err))
+			http.Error(w, "Failed to get print rules", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"rules": rules,
+			"count": len(rules),
+		})
+
+	case http.MethodPost:
+		// enabled を省略した場合は有効なルールとして作成する
+		rule := printrules.Rule{Enabled: true}
+		if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		created, err := printrules.CreateRule(&rule)
+		if err != nil {
+			writePrintRuleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(created)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handlePrintRule handles GET/PUT/DELETE for a single rule: /api/printer/rules/{id}
+func handlePrintRule(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/printer/rules/")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid rule ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		rule, err := printrules.GetRule(id)
+		if err != nil {
+			writePrintRuleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rule)
+
+	case http.MethodPut:
+		var rule printrules.Rule
+		if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		updated, err := printrules.UpdateRule(id, &rule)
+		if err != nil {
+			writePrintRuleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+
+	case http.MethodDelete:
+		if err := printrules.DeleteRule(id); err != nil {
+			writePrintRuleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"status":  "ok",
+			"message": "Rule deleted successfully",
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func writePrintRuleError(w http.ResponseWriter, err error) {
+	switch err {
+	case printrules.ErrNotFound:
+		http.Error(w, "Rule not found", http.StatusNotFound)
+	case printrules.ErrInvalidEventType, printrules.ErrInvalidCopies, printrules.ErrInvalidMinAmount:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		logger.Error("Print rule operation failed", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -1,14 +1,26 @@
 package webserver
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
 	"github.com/nantokaworks/twitch-overlay/internal/output"
+	"github.com/nantokaworks/twitch-overlay/internal/settings"
 	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"github.com/nantokaworks/twitch-overlay/internal/status"
+	"github.com/nantokaworks/twitch-overlay/internal/twitchapi"
 	"go.uber.org/zap"
 )
 
@@ -30,6 +42,15 @@ type TestResponse struct {
 	Message string `json:"message"`
 }
 
+// printErrorStatus maps an error from the output package to an HTTP status.
+// キューが満杯（プリンターが詰まっている）場合は 503 を返し、クライアントが後で再試行できるようにする
+func printErrorStatus(err error) int {
+	if errors.Is(err, output.ErrQueueFull) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
 // handlePrinterScan プリンターデバイスのスキャンを実行
 func handlePrinterScan(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -39,21 +60,8 @@ func handlePrinterScan(w http.ResponseWriter, r *http.Request) {
 
 	logger.Info("Starting printer scan")
 
-	// プリンタースキャンを実行
-	c, err := output.SetupPrinter()
-	if err != nil {
-		logger.Error("Failed to setup scanner", zap.Error(err))
-		http.Error(w, "Failed to setup scanner", http.StatusInternalServerError)
-		return
-	}
-	defer c.Stop()
-
-	// デバッグログを有効にする（find-faxと同じ設定）
-	c.Debug.Log = true
-
-	// 10秒間スキャン
-	c.Timeout = 10 * time.Second
-	devices, err := c.ScanDevices("")
+	// 10秒間スキャン（クライアント切断時は中断）
+	devices, err := output.ScanForPrinters(r.Context(), 10*time.Second)
 
 	response := ScanResponse{
 		Devices: []BluetoothDevice{},
@@ -66,14 +74,77 @@ func handlePrinterScan(w http.ResponseWriter, r *http.Request) {
 		response.Message = err.Error()
 	} else {
 		logger.Info("Device scan completed", zap.Int("device_count", len(devices)))
-		for mac, name := range devices {
+		for _, d := range devices {
 			device := BluetoothDevice{
-				MACAddress: mac,
-				Name:       string(name),
-				LastSeen:   time.Now(),
+				MACAddress:     d.MACAddress,
+				Name:           d.Name,
+				SignalStrength: d.RSSI,
+				LastSeen:       time.Now(),
 			}
 			response.Devices = append(response.Devices, device)
-			logger.Debug("Found device", zap.String("mac", mac), zap.String("name", string(name)))
+			logger.Debug("Found device", zap.String("mac", d.MACAddress), zap.String("name", d.Name))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// printerValidateScanTimeout はアドレス検証時のスキャン時間（設定画面を長く待たせない）
+const printerValidateScanTimeout = 5 * time.Second
+
+// handlePrinterValidate 設定されたプリンターアドレスが実際にスキャンで見えるかを確認する
+// (POST /api/settings/printer/validate)。ボディの mac_address を省略すると保存済みの PRINTER_ADDRESS を使う
+func handlePrinterValidate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		MACAddress string `json:"mac_address"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+	}
+
+	address := req.MACAddress
+	if address == "" {
+		address, _ = settings.NewSettingsManager(localdb.GetDB()).GetSetting("PRINTER_ADDRESS")
+	}
+	if address == "" {
+		http.Error(w, "PRINTER_ADDRESS is not configured", http.StatusBadRequest)
+		return
+	}
+	if err := settings.ValidateSetting("PRINTER_ADDRESS", address); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// スキャン本体に加え、印刷ロック待ちの分も含めて上限を設ける
+	ctx, cancel := context.WithTimeout(r.Context(), printerValidateScanTimeout+5*time.Second)
+	defer cancel()
+
+	logger.Info("Validating printer address", zap.String("address", address))
+	reachable, err := output.FindPrinter(ctx, address, printerValidateScanTimeout)
+
+	response := map[string]interface{}{
+		"address":   address,
+		"reachable": reachable,
+	}
+	if err != nil {
+		logger.Warn("Printer validation failed", zap.String("address", address), zap.Error(err))
+		response["error"] = err.Error()
+	} else {
+		// スキャンできた場合のみ結果を記録する（失敗時は前回の結果を残す）
+		status.SetPrinterReachable(address, reachable)
+		if reachable {
+			response["message"] = "プリンターが見つかりました"
+		} else {
+			response["message"] = "プリンターが見つかりません（電源や距離を確認してください）"
 		}
 	}
 
@@ -207,8 +278,8 @@ func handlePrinterStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get printer connection status
-	isConnected := output.IsConnected()
+	// Get printer connection status（再接続中も接続扱いを維持するステータスを使う）
+	isConnected := status.IsPrinterConnected()
 	
 	// Get dry-run mode from environment
 	dryRunMode := env.Value.DryRunMode
@@ -224,11 +295,182 @@ func handlePrinterStatus(w http.ResponseWriter, r *http.Request) {
 		"dry_run_mode":     dryRunMode,
 		"printer_address":  printerAddress,
 		"configured":       printerAddress != "",
-		// Additional fields can be added as needed
-		"last_print":      nil,  // This would need to be tracked separately
-		"print_queue":     0,    // This would need queue implementation
+		"color_print":      env.Value.ColorPrint,
+		"capabilities":     output.GetPrinterCapabilities(),
+		"fax_counters":     output.GetFaxCounters(),
+		"paused":           output.IsPrintingPaused(),
+		"dry_run_active":   output.IsDryRunActive(), // AUTO_DRY_RUN_WHEN_OFFLINE を含めた実際の状態
+		"quiet_hours":      output.IsQuietHours(),
+		"last_print":       output.GetLastPrintTime(),
+		"print_queue":      output.GetPrintQueueSize(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
-}
\ No newline at end of file
+}
+
+// maxPrintImageSize is the upload limit for POST /api/printer/print-image
+const maxPrintImageSize = 20 << 20
+
+// handlePrintImage prints an uploaded PNG/JPEG/GIF image (multipart field "image", optional "title")
+func handlePrintImage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxPrintImageSize)
+	if err := r.ParseMultipartForm(maxPrintImageSize); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			http.Error(w, fmt.Sprintf("Image too large (max %dMB)", maxPrintImageSize>>20), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		http.Error(w, "No image uploaded", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	img, format, err := image.Decode(file)
+	if err != nil {
+		http.Error(w, "Unsupported image format (PNG/JPEG/GIF)", http.StatusBadRequest)
+		return
+	}
+
+	title := r.FormValue("title")
+	if title == "" {
+		title = header.Filename
+	}
+
+	if err := output.PrintImage(title, img); err != nil {
+		logger.Error("Failed to print uploaded image", zap.Error(err))
+		http.Error(w, fmt.Sprintf("Failed to print image: %v", err), printErrorStatus(err))
+		return
+	}
+
+	logger.Info("Uploaded image queued for printing",
+		zap.String("filename", header.Filename),
+		zap.String("format", format),
+		zap.Int("width", img.Bounds().Dx()),
+		zap.Int("height", img.Bounds().Dy()))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": "Image queued for printing",
+	})
+}
+
+// handlePrintLeaderboard prints the monthly Cheer leaderboard as its own fax (POST /api/printer/print-leaderboard).
+// ボディは省略可。emptyLeaderboard / refreshLeaderboard はデバッグ用の時計と同じ意味
+func handlePrintLeaderboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		EmptyLeaderboard   bool `json:"emptyLeaderboard"`
+		RefreshLeaderboard bool `json:"refreshLeaderboard"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if req.RefreshLeaderboard {
+		twitchapi.InvalidateBitsLeaderboardCache()
+	}
+
+	if err := output.PrintLeaderboard(req.EmptyLeaderboard); err != nil {
+		logger.Error("Failed to print leaderboard", zap.Error(err))
+		http.Error(w, fmt.Sprintf("Failed to print leaderboard: %v", err), printErrorStatus(err))
+		return
+	}
+
+	logger.Info("Leaderboard queued for printing", zap.Bool("emptyLeaderboard", req.EmptyLeaderboard))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": "Leaderboard queued for printing",
+	})
+}
+
+// handlePrinterPause pauses printing; faxes are held in the queue until resumed
+func handlePrinterPause(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"status":  output.PausePrinting(),
+	})
+}
+
+// handlePrinterResume resumes printing and prints the held faxes in order
+func handlePrinterResume(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"status":  output.ResumePrinting(),
+	})
+}
+
+// handlePrinterDryRun switches DRY_RUN_MODE without going through the full settings update (POST /api/printer/dry-run).
+// ボディの enabled を省略した場合は現在の値を反転する
+func handlePrinterDryRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+	}
+
+	enabled := !env.Value.DryRunMode
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	settingsManager := settings.NewSettingsManager(localdb.GetDB())
+	if err := settingsManager.SetSetting("DRY_RUN_MODE", strconv.FormatBool(enabled)); err != nil {
+		logger.Error("Failed to update DRY_RUN_MODE", zap.Error(err))
+		http.Error(w, "Failed to update DRY_RUN_MODE", http.StatusInternalServerError)
+		return
+	}
+	if err := env.ReloadFromDatabase(); err != nil {
+		logger.Warn("Failed to reload env values from database", zap.Error(err))
+	}
+
+	logger.Info("Dry-run mode toggled", zap.Bool("enabled", enabled), zap.Bool("active", output.IsDryRunActive()))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":                   true,
+		"dry_run_mode":              env.Value.DryRunMode,
+		"auto_dry_run_when_offline": env.Value.AutoDryRunWhenOffline,
+		"dry_run_active":            output.IsDryRunActive(), // 配信オフラインによる自動 DRY-RUN も含めた実際の状態
+	})
+}
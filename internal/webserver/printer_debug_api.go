@@ -0,0 +1,71 @@
+package webserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/output"
+)
+
+// handlePrinterDebug enables/disables the verbose BLE debug capture or returns its status.
+// POST {"duration_seconds": 300} で有効化、{"enabled": false} で停止、GET で状態を返す
+func handlePrinterDebug(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeBLEDebugStatus(w, output.GetBLEDebugStatus())
+
+	case http.MethodPost:
+		var req struct {
+			Enabled         *bool `json:"enabled"`
+			DurationSeconds int   `json:"duration_seconds"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if req.Enabled != nil && !*req.Enabled {
+			output.DisableBLEDebug()
+			writeBLEDebugStatus(w, output.GetBLEDebugStatus())
+			return
+		}
+
+		maxSeconds := int(output.BLEDebugMaxDuration / time.Second)
+		if req.DurationSeconds <= 0 || req.DurationSeconds > maxSeconds {
+			http.Error(w, fmt.Sprintf("duration_seconds must be between 1 and %d", maxSeconds), http.StatusBadRequest)
+			return
+		}
+		writeBLEDebugStatus(w, output.EnableBLEDebug(time.Duration(req.DurationSeconds)*time.Second))
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handlePrinterDebugDownload downloads the captured BLE scan/connect log as a text file
+func handlePrinterDebugDownload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data := output.BLEDebugLog()
+	if len(data) == 0 {
+		http.Error(w, "No BLE debug log captured yet", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=twitch-overlay-ble-debug-%s.txt", time.Now().Format("20060102-150405")))
+	w.Write(data)
+}
+
+func writeBLEDebugStatus(w http.ResponseWriter, s output.BLEDebugStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"debug":   s,
+	})
+}
@@ -1,9 +1,12 @@
 package webserver
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/nantokaworks/twitch-overlay/internal/env"
 	"github.com/nantokaworks/twitch-overlay/internal/output"
@@ -11,6 +14,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// printerReconnectTimeout は再接続リクエストが結果を待つ上限
+const printerReconnectTimeout = 20 * time.Second
+
 // handlePrinterReconnect プリンターへの再接続を強制的に実行
 func handlePrinterReconnect(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -36,29 +42,25 @@ func handlePrinterReconnect(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 常に完全リセットを実行（SetupPrinterが内部で完全リセットするように修正済み）
-	logger.Info("[Reconnect] Performing complete printer reset")
-	
-	// Setup printer (内部で完全リセットを実行)
-	c, err := output.SetupPrinter()
-	if err != nil {
-		logger.Error("Failed to setup printer", zap.Error(err))
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": false,
-			"error":   fmt.Sprintf("プリンターセットアップエラー: %v", err),
-		})
-		return
-	}
+	// keep-alive を止めて切断・再接続し、結果が出るまで（上限あり）待つ
+	ctx, cancel := context.WithTimeout(r.Context(), printerReconnectTimeout)
+	defer cancel()
 
-	// Connect to printer
-	err = output.ConnectPrinter(c, printerAddress)
-	if err != nil {
+	started := time.Now()
+	if err := output.Reconnect(ctx, printerAddress); err != nil {
 		logger.Error("Failed to reconnect", zap.String("address", printerAddress), zap.Error(err))
+		message := fmt.Sprintf("接続エラー: %v", err)
+		if errors.Is(err, output.ErrReconnectTimeout) {
+			message = "再接続がタイムアウトしました（バックグラウンドで接続を継続しています）"
+		}
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": false,
-			"error":   fmt.Sprintf("接続エラー: %v", err),
+			"success":         false,
+			"connected":       output.IsConnected(),
+			"printer_address": printerAddress,
+			"error":           message,
+			"detail":          err.Error(),
+			"elapsed_ms":      time.Since(started).Milliseconds(),
 		})
 		return
 	}
@@ -71,6 +73,7 @@ func handlePrinterReconnect(w http.ResponseWriter, r *http.Request) {
 		"connected":       output.IsConnected(),
 		"printer_address": printerAddress,
 		"message":         "プリンターに再接続しました",
+		"elapsed_ms":      time.Since(started).Milliseconds(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
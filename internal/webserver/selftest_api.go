@@ -0,0 +1,30 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/nantokaworks/twitch-overlay/internal/selftest"
+)
+
+// handleSelfTest returns the last self-test report (GET) or runs it again (POST)
+func handleSelfTest(w http.ResponseWriter, r *http.Request) {
+	var report *selftest.Report
+
+	switch r.Method {
+	case http.MethodGet:
+		report = selftest.GetLastReport()
+		if report == nil {
+			http.Error(w, "Self-test has not been run yet", http.StatusNotFound)
+			return
+		}
+	case http.MethodPost:
+		report = selftest.Run()
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -8,12 +8,14 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	twitch "github.com/joeyak/go-twitch-eventsub/v3"
 	"github.com/nantokaworks/twitch-overlay/internal/broadcast"
+	"github.com/nantokaworks/twitch-overlay/internal/env"
 	"github.com/nantokaworks/twitch-overlay/internal/faxmanager"
 	"github.com/nantokaworks/twitch-overlay/internal/fontmanager"
 	"github.com/nantokaworks/twitch-overlay/internal/output"
@@ -26,10 +28,20 @@ import (
 )
 
 type SSEServer struct {
-	clients map[chan string]bool
+	clients map[chan sseEvent]*streamClient
 	mu      sync.RWMutex
 }
 
+// sseEvent is a message sent to an SSE client. FAX には履歴の通し番号を id として付け、
+// 再接続時に Last-Event-ID (または ?since=) から取りこぼした FAX を再送できるようにする
+type sseEvent struct {
+	id   int64 // 0 の場合は id を送らない
+	data string
+}
+
+// sseReplayLimit は再接続時に再送する FAX の最大件数
+const sseReplayLimit = 50
+
 // broadcast sends data to all connected clients
 func (s *SSEServer) broadcast(data []byte) {
 	s.mu.RLock()
@@ -37,7 +49,7 @@ func (s *SSEServer) broadcast(data []byte) {
 
 	for client := range s.clients {
 		select {
-		case client <- string(data):
+		case client <- sseEvent{data: string(data)}:
 		default:
 			// Client is not ready to receive, skip
 		}
@@ -46,7 +58,7 @@ func (s *SSEServer) broadcast(data []byte) {
 
 var (
 	sseServer = &SSEServer{
-		clients: make(map[chan string]bool),
+		clients: make(map[chan sseEvent]*streamClient),
 	}
 	httpServer *http.Server
 )
@@ -54,12 +66,12 @@ var (
 // corsMiddleware adds CORS headers to HTTP handlers
 func corsMiddleware(handler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
+		setAllowOrigin(w, r)
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
 		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusOK)
+			writePreflight(w, r)
 			return
 		}
 
@@ -67,6 +79,18 @@ func corsMiddleware(handler http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// debugOnly rejects requests with 403 unless DEBUG_MODE is enabled.
+// 公開環境で誰でも偽のレイドなどを発生させられないよう、全てのデバッグ用エンドポイントに適用する
+func debugOnly(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !env.Value.DebugMode {
+			http.Error(w, "Debug mode not enabled", http.StatusForbidden)
+			return
+		}
+		handler(w, r)
+	}
+}
+
 // StartWebServer starts the HTTP server
 // BroadcastMessage sends a message to all connected SSE clients
 func (s *SSEServer) BroadcastMessage(message interface{}) {
@@ -89,6 +113,9 @@ func StartWebServer(port int) {
 	// Register SSE server as the global broadcaster
 	broadcast.SetBroadcaster(sseServer)
 
+	// 応答しなくなったオーバーレイの接続を掃除する
+	go pruneStaleClientsPeriodically()
+
 	// Register stream status change callback
 	status.RegisterStatusChangeCallback(func(streamStatus status.StreamStatus) {
 		BroadcastMessage(map[string]interface{}{
@@ -141,7 +168,12 @@ func StartWebServer(port int) {
 	mux.HandleFunc("/api/settings/v2", corsMiddleware(handleSettingsV2))
 	mux.HandleFunc("/api/settings/status", corsMiddleware(handleSettingsStatus))
 	mux.HandleFunc("/api/settings/bulk", corsMiddleware(handleBulkSettings))
+	mux.HandleFunc("/api/settings/export", corsMiddleware(handleSettingsExport))
+	mux.HandleFunc("/api/settings/import", corsMiddleware(handleSettingsImport))
+	mux.HandleFunc("/api/settings/history", corsMiddleware(handleSettingsHistory))
+	mux.HandleFunc("/api/settings/printer/validate", corsMiddleware(handlePrinterValidate))
 	mux.HandleFunc("/api/settings/font/preview", corsMiddleware(handleFontPreview))
+	mux.HandleFunc("/api/settings/preview/diff", corsMiddleware(handlePreviewDiff))
 	mux.HandleFunc("/api/settings/font", handleFontUpload) // handleFontUploadは独自のCORS処理を持つ
 	mux.HandleFunc("/api/settings/auth/status", corsMiddleware(handleAuthStatus))
 	mux.HandleFunc("/api/settings", corsMiddleware(handleSettings))
@@ -151,11 +183,23 @@ func StartWebServer(port int) {
 	mux.HandleFunc("/api/printer/test", corsMiddleware(handlePrinterTest))
 	mux.HandleFunc("/api/printer/status", corsMiddleware(handlePrinterStatus))
 	mux.HandleFunc("/api/printer/reconnect", corsMiddleware(handlePrinterReconnect))
-	mux.HandleFunc("/api/debug/printer-status", corsMiddleware(handleDebugPrinterStatus)) // デバッグ用
+	mux.HandleFunc("/api/printer/print-image", corsMiddleware(handlePrintImage))
+	mux.HandleFunc("/api/printer/print-leaderboard", corsMiddleware(handlePrintLeaderboard))
+	mux.HandleFunc("/api/printer/pause", corsMiddleware(handlePrinterPause))
+	mux.HandleFunc("/api/printer/resume", corsMiddleware(handlePrinterResume))
+	mux.HandleFunc("/api/printer/dry-run", corsMiddleware(handlePrinterDryRun))
+	mux.HandleFunc("/api/printer/debug", corsMiddleware(handlePrinterDebug))
+	mux.HandleFunc("/api/printer/debug/download", corsMiddleware(handlePrinterDebugDownload))
+	mux.HandleFunc("/api/printer/rules", corsMiddleware(handlePrintRules))
+	mux.HandleFunc("/api/printer/rules/", corsMiddleware(handlePrintRule))
+	mux.HandleFunc("/api/debug/printer-status", corsMiddleware(debugOnly(handleDebugPrinterStatus))) // デバッグ用
+	mux.HandleFunc("/api/debug/demo", corsMiddleware(debugOnly(handleDebugDemo)))                    // デモ用
 
 	// Server management API endpoints
 	mux.HandleFunc("/api/server/restart", corsMiddleware(handleServerRestart))
 	mux.HandleFunc("/api/server/status", corsMiddleware(handleServerStatus))
+	mux.HandleFunc("/api/server/selftest", corsMiddleware(handleSelfTest))
+	mux.HandleFunc("/api/cache/emotes/clear", corsMiddleware(handleClearEmoteCache))
 	mux.HandleFunc("/api/bluetooth/restart", corsMiddleware(handleBluetoothRestart))
 	mux.HandleFunc("/api/service/restart", corsMiddleware(handleServiceRestart))
 
@@ -164,29 +208,44 @@ func StartWebServer(port int) {
 	mux.HandleFunc("/api/logs/download", corsMiddleware(handleLogsDownload))
 	mux.HandleFunc("/api/logs/stream", handleLogsStream) // WebSocketは独自のUpgrade処理
 	mux.HandleFunc("/api/logs/clear", corsMiddleware(handleLogsClear))
+	mux.HandleFunc("/api/logs/level", corsMiddleware(handleLogsLevel))
 
 	// SSE endpoint
 	mux.HandleFunc("/events", handleSSE)
+	mux.HandleFunc("/ws/fax", handleFaxWebSocket) // SSE を扱いにくいクライアント向け（同じイベントを WebSocket で配信）
+	mux.HandleFunc("/api/clients", corsMiddleware(handleClients))
 
 	// Fax image endpoint
 	mux.HandleFunc("/fax/", handleFaxImage)
+	mux.HandleFunc("/api/faxes/", corsMiddleware(handleFaxes))
+	mux.HandleFunc("/api/fax/history", corsMiddleware(handleFaxHistory))
+	mux.HandleFunc("/api/stats/faxes", corsMiddleware(handleFaxStats))
 
 	// Status endpoint
 	mux.HandleFunc("/status", handleStatus)
+	mux.HandleFunc("/metrics", handleMetrics) // Prometheus 形式のメトリクス
+	mux.HandleFunc("/healthz", handleHealthz) // systemd などの監視用
 
 	// Debug endpoints
-	mux.HandleFunc("/debug/fax", handleDebugFax)
-	mux.HandleFunc("/debug/channel-points", handleDebugChannelPoints)
-	mux.HandleFunc("/debug/clock", handleDebugClock)
-	mux.HandleFunc("/debug/follow", handleDebugFollow)
-	mux.HandleFunc("/debug/cheer", handleDebugCheer)
-	mux.HandleFunc("/debug/subscribe", handleDebugSubscribe)
-	mux.HandleFunc("/debug/gift-sub", handleDebugGiftSub)
-	mux.HandleFunc("/debug/resub", handleDebugResub)
-	mux.HandleFunc("/debug/raid", handleDebugRaid)
-	mux.HandleFunc("/debug/shoutout", handleDebugShoutout)
-	mux.HandleFunc("/debug/stream-online", handleDebugStreamOnline)
-	mux.HandleFunc("/debug/stream-offline", handleDebugStreamOffline)
+	mux.HandleFunc("/debug/fax", debugOnly(handleDebugFax))
+	mux.HandleFunc("/debug/channel-points", debugOnly(handleDebugChannelPoints))
+	mux.HandleFunc("/debug/clock", debugOnly(handleDebugClock))
+	mux.HandleFunc("/debug/follow", debugOnly(handleDebugFollow))
+	mux.HandleFunc("/debug/cheer", debugOnly(handleDebugCheer))
+	mux.HandleFunc("/debug/subscribe", debugOnly(handleDebugSubscribe))
+	mux.HandleFunc("/debug/gift-sub", debugOnly(handleDebugGiftSub))
+	mux.HandleFunc("/debug/resub", debugOnly(handleDebugResub))
+	mux.HandleFunc("/debug/raid", debugOnly(handleDebugRaid))
+	mux.HandleFunc("/debug/shoutout", debugOnly(handleDebugShoutout))
+	mux.HandleFunc("/debug/hype-train-begin", debugOnly(handleDebugHypeTrainBegin))
+	mux.HandleFunc("/debug/hype-train-end", debugOnly(handleDebugHypeTrainEnd))
+	mux.HandleFunc("/debug/poll-begin", debugOnly(handleDebugPollBegin))
+	mux.HandleFunc("/debug/poll-end", debugOnly(handleDebugPollEnd))
+	mux.HandleFunc("/debug/prediction-begin", debugOnly(handleDebugPredictionBegin))
+	mux.HandleFunc("/debug/prediction-end", debugOnly(handleDebugPredictionEnd))
+	mux.HandleFunc("/debug/goal", debugOnly(handleDebugGoal))
+	mux.HandleFunc("/debug/stream-online", debugOnly(handleDebugStreamOnline))
+	mux.HandleFunc("/debug/stream-offline", debugOnly(handleDebugStreamOffline))
 
 	// OAuth endpoints
 	mux.HandleFunc("/auth", handleAuth)
@@ -195,6 +254,7 @@ func StartWebServer(port int) {
 	// Twitch API endpoints
 	mux.HandleFunc("/api/twitch/verify", corsMiddleware(handleTwitchVerify))
 	mux.HandleFunc("/api/twitch/refresh-token", corsMiddleware(handleTwitchRefreshToken))
+	mux.HandleFunc("/api/twitch/rate-limit", corsMiddleware(handleTwitchRateLimit))
 	mux.HandleFunc("/api/stream/status", corsMiddleware(handleStreamStatus))
 
 	// Create a custom file server that handles SPA routing
@@ -234,7 +294,7 @@ func StartWebServer(port int) {
 	// Create HTTP server instance
 	httpServer = &http.Server{
 		Addr:    addr,
-		Handler: mux, // Use our custom ServeMux
+		Handler: apiAuthMiddleware(mux), // Use our custom ServeMux (API_TOKEN 設定時は変更系APIを保護)
 	}
 
 	go func() {
@@ -263,13 +323,13 @@ func Shutdown() {
 // handleSSE handles Server-Sent Events connections
 func handleSSE(w http.ResponseWriter, r *http.Request) {
 	// Set CORS headers first
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 	// Handle OPTIONS request
 	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
+		writePreflight(w, r)
 		return
 	}
 
@@ -279,11 +339,13 @@ func handleSSE(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Connection", "keep-alive")
 
 	// Create client channel
-	clientChan := make(chan string)
+	// 再送中に届いたメッセージを落とさないよう少しバッファを持たせる
+	clientChan := make(chan sseEvent, 10)
+	client := newStreamClient(r, transportSSE)
 
 	// Register client
 	sseServer.mu.Lock()
-	sseServer.clients[clientChan] = true
+	sseServer.clients[clientChan] = client
 	sseServer.mu.Unlock()
 
 	// Remove client on disconnect
@@ -297,29 +359,59 @@ func handleSSE(w http.ResponseWriter, r *http.Request) {
 	logger.Info("SSE client connected", zap.String("remote", r.RemoteAddr))
 
 	// Send initial connection message
+	// 最新の通し番号を id として送り、FAX が届く前に切断されても再接続時に位置がわかるようにする
+	since, resume := sseResumePosition(r)
+	latest := faxmanager.LatestHistorySeq()
+	if latest > 0 && !resume {
+		fmt.Fprintf(w, "id: %d\n", latest)
+	}
 	fmt.Fprintf(w, "data: {\"type\":\"connected\"}\n\n")
+
+	// 切断中に届いた FAX を再送する（クライアント登録後に取得するので取りこぼしはない）
+	replayed := int64(0)
+	if resume {
+		replayed = replayMissedFaxes(w, since)
+	}
 	if f, ok := w.(http.Flusher); ok {
 		f.Flush()
 	}
 
 	// Create heartbeat ticker
-	heartbeat := time.NewTicker(30 * time.Second)
+	heartbeat := time.NewTicker(clientHeartbeatInterval)
 	defer heartbeat.Stop()
 
 	// Send messages to client
 	for {
 		select {
 		case msg := <-clientChan:
-			fmt.Fprintf(w, "data: %s\n\n", msg)
+			if msg.id > 0 {
+				if msg.id <= replayed {
+					// 再送済み
+					continue
+				}
+				fmt.Fprintf(w, "id: %d\n", msg.id)
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", msg.data); err != nil {
+				logger.Info("SSE client disconnected", zap.String("remote", r.RemoteAddr), zap.Error(err))
+				return
+			}
 			if f, ok := w.(http.Flusher); ok {
 				f.Flush()
 			}
+			client.touch()
 		case <-heartbeat.C:
-			// Send heartbeat
-			fmt.Fprintf(w, ": heartbeat\n\n")
+			// Send heartbeat（書き込めなければ切断されたとみなす）
+			if _, err := fmt.Fprintf(w, ": heartbeat\n\n"); err != nil {
+				logger.Info("SSE client disconnected", zap.String("remote", r.RemoteAddr), zap.Error(err))
+				return
+			}
 			if f, ok := w.(http.Flusher); ok {
 				f.Flush()
 			}
+			client.touch()
+		case <-client.stop:
+			logger.Info("SSE client pruned", zap.String("remote", r.RemoteAddr))
+			return
 		case <-r.Context().Done():
 			logger.Info("SSE client disconnected", zap.String("remote", r.RemoteAddr))
 			return
@@ -327,10 +419,71 @@ func handleSSE(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleFaxImage serves fax images
+// sseResumePosition returns the last fax seq the client has seen (Last-Event-ID header or ?since=)
+func sseResumePosition(r *http.Request) (int64, bool) {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("since")
+	}
+	if v == "" {
+		return 0, false
+	}
+	seq, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, false
+	}
+	return seq, true
+}
+
+// replayMissedFaxes writes faxes recorded after since and returns the last replayed seq
+func replayMissedFaxes(w http.ResponseWriter, since int64) int64 {
+	return replayFaxes(since, func(seq int64, data []byte) error {
+		_, err := fmt.Fprintf(w, "id: %d\ndata: %s\n\n", seq, data)
+		return err
+	})
+}
+
+// replayFaxes sends faxes recorded after since (SSE / WebSocket 共通) and returns the last replayed seq.
+// 画像が保持期間を過ぎて削除された FAX は表示できないので送らない
+func replayFaxes(since int64, send func(seq int64, data []byte) error) int64 {
+	entries, err := faxmanager.HistorySince(since, sseReplayLimit)
+	if err != nil {
+		logger.Warn("Failed to load missed faxes for replay", zap.Error(err))
+		return 0
+	}
+
+	last := since
+	count := 0
+	for i := range entries {
+		e := &entries[i]
+		last = e.Seq
+		if !e.ImageAvailable {
+			continue
+		}
+		data, err := json.Marshal(faxMessage(&e.Fax))
+		if err != nil {
+			continue
+		}
+		if err := send(e.Seq, data); err != nil {
+			break
+		}
+		count++
+	}
+	if count > 0 {
+		logger.Info("Replayed missed faxes", zap.Int64("since", since), zap.Int("count", count))
+	}
+	return last
+}
+
+// handleFaxImage serves fax images and the permalink page
 func handleFaxImage(w http.ResponseWriter, r *http.Request) {
 	// Parse URL: /fax/{id}/{type}
 	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/fax/"), "/")
+	// /fax/{id} は共有用のパーマリンクページ
+	if len(parts) == 1 || (len(parts) == 2 && parts[1] == "") {
+		handleFaxPage(w, r, parts[0])
+		return
+	}
 	if len(parts) != 2 {
 		http.Error(w, "Invalid request", http.StatusBadRequest)
 		return
@@ -360,19 +513,23 @@ func handleFaxImage(w http.ResponseWriter, r *http.Request) {
 	http.ServeFile(w, r, imagePath)
 }
 
-// BroadcastFax sends a fax notification to all connected SSE clients
-func (s *SSEServer) BroadcastFax(fax *faxmanager.Fax) {
-	msg := map[string]interface{}{
+// faxMessage builds the SSE "fax" event
+func faxMessage(fax *faxmanager.Fax) map[string]interface{} {
+	return map[string]interface{}{
 		"type":        "fax",
 		"id":          fax.ID,
+		"seq":         fax.Seq,                     // 再接続時の ?since= に使う通し番号（SSE の id と同じ）
 		"timestamp":   fax.Timestamp.Unix() * 1000, // JavaScriptのミリ秒に変換
 		"username":    fax.UserName,
 		"displayName": fax.UserName, // 表示名も同じにする
 		"message":     fax.Message,
 		"imageUrl":    fmt.Sprintf("/fax/%s/color", fax.ID), // カラー画像のURLを生成
 	}
+}
 
-	jsonData, err := json.Marshal(msg)
+// BroadcastFax sends a fax notification to all connected SSE clients
+func (s *SSEServer) BroadcastFax(fax *faxmanager.Fax) {
+	jsonData, err := json.Marshal(faxMessage(fax))
 	if err != nil {
 		logger.Error("Failed to marshal fax message", zap.Error(err))
 		return
@@ -383,7 +540,7 @@ func (s *SSEServer) BroadcastFax(fax *faxmanager.Fax) {
 
 	for client := range s.clients {
 		select {
-		case client <- string(jsonData):
+		case client <- sseEvent{id: fax.Seq, data: string(jsonData)}:
 		default:
 			// Client channel is full, skip
 		}
@@ -397,12 +554,15 @@ func (s *SSEServer) BroadcastFax(fax *faxmanager.Fax) {
 // handleStatus returns the current system status
 func handleStatus(w http.ResponseWriter, r *http.Request) {
 	// Set CORS headers
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 	w.Header().Set("Content-Type", "application/json")
 
+	streamStatus := status.GetStreamStatus()
 	statusData := map[string]interface{}{
 		"printerConnected": status.IsPrinterConnected(),
 		"timestamp":        time.Now().Format("2006-01-02T15:04:05Z"),
+		"stream":           streamStatus,
+		"uptime":           streamStatus.UptimeSeconds, // オーバーレイ再読み込み時も正しい経過時間を表示するため
 	}
 
 	jsonData, err := json.Marshal(statusData)
@@ -426,11 +586,7 @@ type DebugFaxRequest struct {
 func handleDebugFax(w http.ResponseWriter, r *http.Request) {
 	// Note: This endpoint is kept for backwards compatibility
 	// but the frontend now uses local mode by default
-	// Only allow in debug mode
-	if os.Getenv("DEBUG_MODE") != "true" {
-		http.Error(w, "Debug mode not enabled", http.StatusForbidden)
-		return
-	}
+	// (DEBUG_MODE のチェックは debugOnly で行う)
 
 	// Only accept POST
 	if r.Method != http.MethodPost {
@@ -481,13 +637,13 @@ func handleDebugFax(w http.ResponseWriter, r *http.Request) {
 	err = output.PrintOut(req.Username, fragments, time.Now())
 	if err != nil {
 		logger.Error("Failed to process debug fax", zap.Error(err))
-		http.Error(w, "Failed to process fax", http.StatusInternalServerError)
+		http.Error(w, "Failed to process fax", printErrorStatus(err))
 		return
 	}
 
 	// Return success
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 	json.NewEncoder(w).Encode(map[string]string{
 		"status":  "ok",
 		"message": "Debug fax queued successfully",
@@ -505,13 +661,13 @@ type DebugChannelPointsRequest struct {
 // handleDebugChannelPoints handles debug channel points redemption
 func handleDebugChannelPoints(w http.ResponseWriter, r *http.Request) {
 	// CORS headers
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 	// Handle OPTIONS
 	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
+		writePreflight(w, r)
 		return
 	}
 
@@ -563,7 +719,7 @@ func handleDebugChannelPoints(w http.ResponseWriter, r *http.Request) {
 	err = output.PrintOut(req.Username, fragments, time.Now())
 	if err != nil {
 		logger.Error("Failed to process debug channel points", zap.Error(err))
-		http.Error(w, "Failed to process channel points redemption", http.StatusInternalServerError)
+		http.Error(w, "Failed to process channel points redemption", printErrorStatus(err))
 		return
 	}
 
@@ -579,18 +735,20 @@ func handleDebugChannelPoints(w http.ResponseWriter, r *http.Request) {
 type DebugClockRequest struct {
 	WithStats        bool `json:"withStats"`
 	EmptyLeaderboard bool `json:"emptyLeaderboard"`
+	// RefreshLeaderboard はキャッシュを使わずにリーダーボードを取得し直す
+	RefreshLeaderboard bool `json:"refreshLeaderboard"`
 }
 
 // handleDebugClock handles debug clock print requests
 func handleDebugClock(w http.ResponseWriter, r *http.Request) {
 	// CORS headers
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 	// Handle OPTIONS
 	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
+		writePreflight(w, r)
 		return
 	}
 
@@ -621,7 +779,12 @@ func handleDebugClock(w http.ResponseWriter, r *http.Request) {
 	logger.Info("Processing debug clock print",
 		zap.String("time", timeStr),
 		zap.Bool("withStats", req.WithStats),
-		zap.Bool("emptyLeaderboard", req.EmptyLeaderboard))
+		zap.Bool("emptyLeaderboard", req.EmptyLeaderboard),
+		zap.Bool("refreshLeaderboard", req.RefreshLeaderboard))
+
+	if req.RefreshLeaderboard {
+		twitchapi.InvalidateBitsLeaderboardCache()
+	}
 
 	// Call PrintClock with options based on request
 	err = output.PrintClockWithOptions(timeStr, req.EmptyLeaderboard)
@@ -632,7 +795,7 @@ func handleDebugClock(w http.ResponseWriter, r *http.Request) {
 			zap.Bool("emptyLeaderboard", req.EmptyLeaderboard))
 		// Return more detailed error message
 		errorMsg := fmt.Sprintf("Failed to print clock: %v", err)
-		http.Error(w, errorMsg, http.StatusInternalServerError)
+		http.Error(w, errorMsg, printErrorStatus(err))
 		return
 	}
 
@@ -648,12 +811,12 @@ func handleDebugClock(w http.ResponseWriter, r *http.Request) {
 // handleDebugFollow handles debug follow event
 func handleDebugFollow(w http.ResponseWriter, r *http.Request) {
 	// CORS headers
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
+		writePreflight(w, r)
 		return
 	}
 
@@ -691,12 +854,12 @@ func handleDebugFollow(w http.ResponseWriter, r *http.Request) {
 
 // handleDebugCheer handles debug cheer event
 func handleDebugCheer(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
+		writePreflight(w, r)
 		return
 	}
 
@@ -737,12 +900,12 @@ func handleDebugCheer(w http.ResponseWriter, r *http.Request) {
 
 // handleDebugSubscribe handles debug subscribe event
 func handleDebugSubscribe(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
+		writePreflight(w, r)
 		return
 	}
 
@@ -780,12 +943,12 @@ func handleDebugSubscribe(w http.ResponseWriter, r *http.Request) {
 
 // handleDebugGiftSub handles debug gift sub event
 func handleDebugGiftSub(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
+		writePreflight(w, r)
 		return
 	}
 
@@ -825,12 +988,12 @@ func handleDebugGiftSub(w http.ResponseWriter, r *http.Request) {
 
 // handleDebugResub handles debug resub event
 func handleDebugResub(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
+		writePreflight(w, r)
 		return
 	}
 
@@ -878,12 +1041,12 @@ func handleDebugResub(w http.ResponseWriter, r *http.Request) {
 
 // handleDebugRaid handles debug raid event
 func handleDebugRaid(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
+		writePreflight(w, r)
 		return
 	}
 
@@ -922,14 +1085,414 @@ func handleDebugRaid(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
 }
 
+// handleDebugHypeTrainBegin handles debug hype train begin event
+func handleDebugHypeTrainBegin(w http.ResponseWriter, r *http.Request) {
+	setAllowOrigin(w, r)
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == http.MethodOptions {
+		writePreflight(w, r)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Username string `json:"username"`
+		Level    int    `json:"level"`
+		Total    int    `json:"total"`
+		Goal     int    `json:"goal"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if req.Username == "" {
+		req.Username = "DebugUser"
+	}
+	if req.Level == 0 {
+		req.Level = 1
+	}
+	if req.Total == 0 {
+		req.Total = 500
+	}
+	if req.Goal == 0 {
+		req.Goal = 1600
+	}
+
+	twitcheventsub.HandleHypeTrainBegin(twitch.EventChannelHypeTrainBegin{
+		Id:       "debug-hype-train",
+		Level:    req.Level,
+		Total:    req.Total,
+		Progress: req.Total,
+		Goal:     req.Goal,
+		LastContribution: twitch.HypeTrainContribution{
+			User: twitch.User{
+				UserID:    "debug-" + req.Username,
+				UserLogin: strings.ToLower(req.Username),
+				UserName:  req.Username,
+			},
+			Type:  "bits",
+			Total: req.Total,
+		},
+		StartedAt: time.Now(),
+		ExpiresAt: time.Now().Add(5 * time.Minute),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// handleDebugHypeTrainEnd handles debug hype train end event
+func handleDebugHypeTrainEnd(w http.ResponseWriter, r *http.Request) {
+	setAllowOrigin(w, r)
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == http.MethodOptions {
+		writePreflight(w, r)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Level        int      `json:"level"`
+		Total        int      `json:"total"`
+		Contributors []string `json:"contributors"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if req.Level == 0 {
+		req.Level = 3
+	}
+	if req.Total == 0 {
+		req.Total = 5000
+	}
+	if len(req.Contributors) == 0 {
+		req.Contributors = []string{"DebugUser1", "DebugUser2", "DebugUser3"}
+	}
+
+	// 貢献者は先頭から順に多い順として扱う（1人目はビッツ、以降はサブスク）
+	contributions := make([]twitch.HypeTrainContribution, len(req.Contributors))
+	for i, name := range req.Contributors {
+		contributions[i] = twitch.HypeTrainContribution{
+			User: twitch.User{
+				UserID:    "debug-" + name,
+				UserLogin: strings.ToLower(name),
+				UserName:  name,
+			},
+			Type:  "subscription",
+			Total: 500,
+		}
+	}
+	contributions[0].Type = "bits"
+	contributions[0].Total = req.Total / 2
+
+	now := time.Now()
+	twitcheventsub.HandleHypeTrainEnd(twitch.EventChannelHypeTrainEnd{
+		Id:               "debug-hype-train",
+		Level:            req.Level,
+		Total:            req.Total,
+		TopContributions: contributions,
+		StartedAt:        now.Add(-10 * time.Minute),
+		ExpiresAt:        now,
+		CooldownEndsAt:   now.Add(time.Hour),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// debugPollRequest is the body of /debug/poll-begin and /debug/poll-end
+type debugPollRequest struct {
+	Title   string   `json:"title"`
+	Choices []string `json:"choices"`
+	Votes   []int    `json:"votes"` // poll-end のみ。choices と同じ順
+}
+
+// toEvent builds a poll event from the request, filling in defaults
+func (req debugPollRequest) toEvent() twitch.EventChannelPollBegin {
+	if req.Title == "" {
+		req.Title = "デバッグ投票"
+	}
+	if len(req.Choices) == 0 {
+		req.Choices = []string{"はい", "いいえ"}
+	}
+
+	now := time.Now()
+	poll := twitch.EventChannelPollBegin{
+		ID:        "debug-poll",
+		Title:     req.Title,
+		StartedAt: now,
+		EndsAt:    now.Add(time.Minute),
+	}
+	for i, title := range req.Choices {
+		choice := twitch.PollChoice{ID: fmt.Sprintf("debug-choice-%d", i+1), Title: title}
+		if i < len(req.Votes) {
+			choice.Votes = req.Votes[i]
+		}
+		poll.Choices = append(poll.Choices, choice)
+	}
+	return poll
+}
+
+// handleDebugPollBegin handles debug poll begin event
+func handleDebugPollBegin(w http.ResponseWriter, r *http.Request) {
+	setAllowOrigin(w, r)
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == http.MethodOptions {
+		writePreflight(w, r)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req debugPollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	twitcheventsub.HandleChannelPollBegin(req.toEvent())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// handleDebugPollEnd handles debug poll end event
+func handleDebugPollEnd(w http.ResponseWriter, r *http.Request) {
+	setAllowOrigin(w, r)
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == http.MethodOptions {
+		writePreflight(w, r)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req debugPollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if len(req.Votes) == 0 {
+		req.Votes = []int{12, 5}
+	}
+
+	twitcheventsub.HandleChannelPollEnd(twitch.EventChannelPollEnd{
+		EventChannelPollBegin: req.toEvent(),
+		Status:                "completed",
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// debugPredictionRequest is the body of /debug/prediction-begin and /debug/prediction-end
+type debugPredictionRequest struct {
+	Title    string   `json:"title"`
+	Outcomes []string `json:"outcomes"`
+	Points   []int    `json:"points"`   // prediction-end のみ。outcomes と同じ順
+	Winner   int      `json:"winner"`   // prediction-end のみ。勝った outcome の番号（1始まり）
+	Canceled bool     `json:"canceled"` // prediction-end のみ
+}
+
+// outcomes builds the prediction outcomes from the request, filling in defaults
+func (req debugPredictionRequest) outcomes() (string, []twitch.PredictionOutcome) {
+	if req.Title == "" {
+		req.Title = "デバッグ予想"
+	}
+	if len(req.Outcomes) == 0 {
+		req.Outcomes = []string{"勝つ", "負ける"}
+	}
+
+	colors := []string{"blue", "pink"}
+	outcomes := make([]twitch.PredictionOutcome, len(req.Outcomes))
+	for i, title := range req.Outcomes {
+		outcomes[i] = twitch.PredictionOutcome{
+			ID:    fmt.Sprintf("debug-outcome-%d", i+1),
+			Title: title,
+			Color: colors[i%len(colors)],
+		}
+		if i < len(req.Points) {
+			outcomes[i].ChannelPoints = req.Points[i]
+			if req.Points[i] > 0 {
+				outcomes[i].Users = 1 + req.Points[i]/1000
+			}
+		}
+	}
+	return req.Title, outcomes
+}
+
+// handleDebugPredictionBegin handles debug prediction begin event
+func handleDebugPredictionBegin(w http.ResponseWriter, r *http.Request) {
+	setAllowOrigin(w, r)
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == http.MethodOptions {
+		writePreflight(w, r)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req debugPredictionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	title, outcomes := req.outcomes()
+	now := time.Now()
+	twitcheventsub.HandleChannelPredictionBegin(twitch.EventChannelPredictionBegin{
+		ID:        "debug-prediction",
+		Title:     title,
+		Outcomes:  outcomes,
+		StartedAt: now,
+		LocksAt:   now.Add(2 * time.Minute),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// handleDebugPredictionEnd handles debug prediction end event (resolved or canceled)
+func handleDebugPredictionEnd(w http.ResponseWriter, r *http.Request) {
+	setAllowOrigin(w, r)
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == http.MethodOptions {
+		writePreflight(w, r)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req debugPredictionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if len(req.Points) == 0 {
+		req.Points = []int{3000, 2000}
+	}
+
+	title, outcomes := req.outcomes()
+	evt := twitch.EventChannelPredictionEnd{
+		ID:        "debug-prediction",
+		Title:     title,
+		Outcomes:  outcomes,
+		Status:    "resolved",
+		StartedAt: time.Now().Add(-5 * time.Minute),
+		EndedAt:   time.Now(),
+	}
+	if req.Canceled {
+		evt.Status = "canceled"
+	} else {
+		winner := req.Winner
+		if winner < 1 || winner > len(outcomes) {
+			winner = 1
+		}
+		evt.WinningOutcomeID = outcomes[winner-1].ID
+	}
+	twitcheventsub.HandleChannelPredictionEnd(evt)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// handleDebugGoal handles debug goal event (simulates an achieved goal)
+func handleDebugGoal(w http.ResponseWriter, r *http.Request) {
+	setAllowOrigin(w, r)
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == http.MethodOptions {
+		writePreflight(w, r)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Type        string `json:"type"`
+		Description string `json:"description"`
+		Target      int    `json:"target"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if req.Type == "" {
+		req.Type = "follow"
+	}
+	if req.Target == 0 {
+		req.Target = 100
+	}
+
+	now := time.Now()
+	twitcheventsub.HandleChannelGoalEnd(twitch.EventChannelGoalEnd{
+		EventChannelGoalBegin: twitch.EventChannelGoalBegin{
+			ID:            "debug-goal",
+			Type:          req.Type,
+			Description:   req.Description,
+			CurrentAmount: req.Target,
+			TargetAmount:  req.Target,
+			StartedAt:     now.Add(-time.Hour),
+		},
+		IsAchieved: true,
+		EndedAt:    now,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
 // handleDebugShoutout handles debug shoutout event
 func handleDebugShoutout(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
+		writePreflight(w, r)
 		return
 	}
 
@@ -967,12 +1530,12 @@ func handleDebugShoutout(w http.ResponseWriter, r *http.Request) {
 
 // handleDebugStreamOnline handles debug stream online event
 func handleDebugStreamOnline(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
+		writePreflight(w, r)
 		return
 	}
 
@@ -998,12 +1561,12 @@ func handleDebugStreamOnline(w http.ResponseWriter, r *http.Request) {
 
 // handleDebugStreamOffline handles debug stream offline event
 func handleDebugStreamOffline(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
+		writePreflight(w, r)
 		return
 	}
 
@@ -1137,13 +1700,13 @@ func handleSettings(w http.ResponseWriter, r *http.Request) {
 // handleFontUpload handles font file upload
 func handleFontUpload(w http.ResponseWriter, r *http.Request) {
 	// Set CORS headers first
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	setAllowOrigin(w, r)
 	w.Header().Set("Access-Control-Allow-Methods", "POST, DELETE, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 	// Handle OPTIONS request
 	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
+		writePreflight(w, r)
 		return
 	}
 
@@ -1299,7 +1862,13 @@ func handleStreamStatus(w http.ResponseWriter, r *http.Request) {
 	if streamStatus.IsLive {
 		if streamInfo, err := twitchapi.GetStreamInfo(); err == nil && streamInfo.IsLive {
 			viewerCount = streamInfo.ViewerCount
-			status.UpdateViewerCount(viewerCount)
+			if !streamInfo.StartedAt.IsZero() {
+				// APIの実際の開始時刻で補正する（再起動直後などで開始時刻が不明な場合も経過時間が正しくなる）
+				status.UpdateStreamStatus(true, &streamInfo.StartedAt, viewerCount)
+				streamStatus = status.GetStreamStatus()
+			} else {
+				status.UpdateViewerCount(viewerCount)
+			}
 		}
 	}
 
@@ -1308,6 +1877,7 @@ func handleStreamStatus(w http.ResponseWriter, r *http.Request) {
 		"started_at":   streamStatus.StartedAt,
 		"viewer_count": viewerCount,
 		"last_checked": streamStatus.LastChecked,
+		"goals":        status.GetGoals(),
 	}
 
 	if streamStatus.IsLive && streamStatus.StartedAt != nil {
@@ -181,4 +181,25 @@ func handleServerStatus(w http.ResponseWriter, r *http.Request) {
 }
 
 // startTime はサーバーの起動時刻
-var startTime = time.Now()
\ No newline at end of file
+var startTime = time.Now()
+
+// handleClearEmoteCache はエモートのキャッシュを削除する
+func handleClearEmoteCache(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	removed, err := output.ClearEmoteCache()
+	if err != nil {
+		logger.Error("Failed to clear emote cache", zap.Error(err))
+		http.Error(w, "Failed to clear emote cache", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"removed": removed,
+	})
+}
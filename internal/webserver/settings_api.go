@@ -4,12 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
+	"strconv"
 
 	"github.com/nantokaworks/twitch-overlay/internal/env"
 	"github.com/nantokaworks/twitch-overlay/internal/fontmanager"
 	"github.com/nantokaworks/twitch-overlay/internal/localdb"
-	"github.com/nantokaworks/twitch-overlay/internal/output"
 	"github.com/nantokaworks/twitch-overlay/internal/settings"
 	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
 	"go.uber.org/zap"
@@ -44,6 +43,11 @@ func handleGetSettings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// API_TOKEN 設定時、トークンのないリクエストにはシークレットの値を返さない（has_value のみ）
+	if env.Value.APIToken != "" && !hasValidAPIToken(r) {
+		maskSecretSettings(allSettings)
+	}
+
 	featureStatus, err := settingsManager.CheckFeatureStatus()
 	if err != nil {
 		logger.Error("Failed to check feature status", zap.Error(err))
@@ -61,6 +65,18 @@ func handleGetSettings(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// maskSecretSettings clears the values of secret settings, keeping only has_value
+func maskSecretSettings(all map[string]settings.Setting) {
+	for key, s := range all {
+		if s.Type != settings.SettingTypeSecret {
+			continue
+		}
+		s.HasValue = s.Value != ""
+		s.Value = ""
+		all[key] = s
+	}
+}
+
 // handleUpdateSettings 設定を更新
 func handleUpdateSettings(w http.ResponseWriter, r *http.Request) {
 	var req map[string]string
@@ -96,55 +112,11 @@ func handleUpdateSettings(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 設定変更後にenv.Valueを再読み込み
+	// （プリンター再接続や時計/キープアライブの再起動は env.Subscribe した各サブシステムが行う）
 	if err := env.ReloadFromDatabase(); err != nil {
 		logger.Warn("Failed to reload env values from database", zap.Error(err))
 	}
 
-	// PRINTER_ADDRESSが変更された場合は再接続を試みる
-	if newAddress, hasPrinterAddress := req["PRINTER_ADDRESS"]; hasPrinterAddress && newAddress != "" {
-		logger.Info("Printer address changed, attempting reconnection", zap.String("new_address", newAddress))
-		
-		// 新しいアドレスで再接続（goroutineで非同期実行）
-		go func() {
-			// パニックからの回復処理
-			defer func() {
-				if r := recover(); r != nil {
-					logger.Error("Panic during printer reconnection", 
-						zap.Any("panic", r),
-						zap.String("address", newAddress))
-				}
-			}()
-			
-			// 既存の接続をリセット（Stop()でBLEデバイスごと解放）
-			func() {
-				defer func() {
-					if r := recover(); r != nil {
-						logger.Warn("Recovered from panic during stop", zap.Any("panic", r))
-					}
-				}()
-				output.Stop()
-			}()
-			
-			time.Sleep(500 * time.Millisecond) // 少し待機
-			
-			c, err := output.SetupPrinter()
-			if err != nil {
-				logger.Error("Failed to setup printer after settings change", zap.Error(err))
-				return
-			}
-			
-			err = output.ConnectPrinter(c, newAddress)
-			if err != nil {
-				logger.Error("Failed to reconnect to printer with new address", zap.String("address", newAddress), zap.Error(err))
-			} else {
-				logger.Info("Successfully reconnected to printer", zap.String("address", newAddress))
-			}
-		}()
-	}
-	
-	// Note: KeepAlive functionality has been removed for simplicity
-	// Reconnection can be done manually via the web interface
-
 	// 更新後の設定状態を返す
 	featureStatus, err := settingsManager.CheckFeatureStatus()
 	if err != nil {
@@ -277,4 +249,34 @@ func handleBulkSettings(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(result)
-}
\ No newline at end of file
+}
+// handleSettingsHistory 設定変更の履歴を取得（新しい順、シークレットはマスク済み）
+func handleSettingsHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	key := r.URL.Query().Get("key")
+	limit := 100 // デフォルト100件
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	settingsManager := settings.NewSettingsManager(localdb.GetDB())
+	history, err := settingsManager.GetHistory(key, limit)
+	if err != nil {
+		logger.Error("Failed to get settings history", zap.Error(err))
+		http.Error(w, "Failed to get settings history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"history": history,
+		"count":   len(history),
+	})
+}
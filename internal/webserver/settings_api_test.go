@@ -0,0 +1,84 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
+	"github.com/nantokaworks/twitch-overlay/internal/settings"
+)
+
+// useTestDB swaps the shared database for a temporary one during the test
+func useTestDB(t *testing.T) *settings.SettingsManager {
+	t.Helper()
+	prev := localdb.DBClient
+	localdb.DBClient = nil
+	db, err := localdb.SetupDB(filepath.Join(t.TempDir(), "local.db"))
+	if err != nil {
+		t.Fatalf("SetupDB: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		localdb.DBClient = prev
+	})
+	return settings.NewSettingsManager(db)
+}
+
+func TestGetSettingsMasksSecretsWithoutAPIToken(t *testing.T) {
+	const token = "s3cret-api-token"
+	sm := useTestDB(t)
+	if err := sm.SetSetting("API_TOKEN", token); err != nil {
+		t.Fatalf("SetSetting(API_TOKEN): %v", err)
+	}
+	if err := sm.SetSetting("CLIENT_SECRET", "client-secret"); err != nil {
+		t.Fatalf("SetSetting(CLIENT_SECRET): %v", err)
+	}
+
+	prevToken := env.Value.APIToken
+	env.Value.APIToken = token
+	t.Cleanup(func() { env.Value.APIToken = prevToken })
+
+	handler := apiAuthMiddleware(http.HandlerFunc(handleSettingsV2))
+
+	tests := []struct {
+		name       string
+		url        string
+		wantSecret bool
+	}{
+		{"without token", "/api/settings/v2", false},
+		{"with wrong token", "/api/settings/v2?token=wrong", false},
+		{"with token", "/api/settings/v2?token=" + token, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+
+			var resp struct {
+				Settings map[string]settings.Setting `json:"settings"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			for key, want := range map[string]string{"API_TOKEN": token, "CLIENT_SECRET": "client-secret"} {
+				s := resp.Settings[key]
+				if !s.HasValue {
+					t.Errorf("%s: has_value = false, want true", key)
+				}
+				if tt.wantSecret && s.Value != want {
+					t.Errorf("%s: value = %q, want %q", key, s.Value, want)
+				}
+				if !tt.wantSecret && s.Value != "" {
+					t.Errorf("%s: value = %q, want it masked", key, s.Value)
+				}
+			}
+		})
+	}
+}
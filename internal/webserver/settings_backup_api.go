@@ -0,0 +1,137 @@
+package webserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/nantokaworks/twitch-overlay/internal/env"
+	"github.com/nantokaworks/twitch-overlay/internal/localdb"
+	"github.com/nantokaworks/twitch-overlay/internal/settings"
+	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"go.uber.org/zap"
+)
+
+// settingsExportVersion is the version of the settings export format
+const settingsExportVersion = 1
+
+// settingsExport is a snapshot of all settings for backup / migration
+type settingsExport struct {
+	Version    int               `json:"version"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Settings   map[string]string `json:"settings"`
+	Masked     []string          `json:"masked,omitempty"` // 値を含めなかったシークレット（インポート時は変更されない）
+}
+
+// handleSettingsExport returns every setting (GET /api/settings/export).
+// シークレットはデフォルトで除外し、?include_secrets=true の場合のみ含める
+func handleSettingsExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	includeSecrets := r.URL.Query().Get("include_secrets") == "true"
+
+	settingsManager := settings.NewSettingsManager(localdb.GetDB())
+	allSettings, err := settingsManager.GetAllSettings()
+	if err != nil {
+		logger.Error("Failed to get settings", zap.Error(err))
+		http.Error(w, "Failed to get settings", http.StatusInternalServerError)
+		return
+	}
+
+	export := settingsExport{
+		Version:    settingsExportVersion,
+		ExportedAt: time.Now(),
+		Settings:   make(map[string]string),
+	}
+	for key, s := range allSettings {
+		if _, known := settings.DefaultSettings[key]; !known {
+			continue
+		}
+		if s.Type == settings.SettingTypeSecret && !includeSecrets {
+			export.Masked = append(export.Masked, key)
+			continue
+		}
+		export.Settings[key] = s.Value
+	}
+	sort.Strings(export.Masked)
+
+	logger.Info("Settings exported", zap.Int("count", len(export.Settings)), zap.Bool("include_secrets", includeSecrets))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="twitch-overlay-settings.json"`)
+	json.NewEncoder(w).Encode(export)
+}
+
+// handleSettingsImport applies an exported settings snapshot (POST /api/settings/import).
+// 全ての値を検証してから一括で反映する（不正な値が一つでもあれば何も変更しない）。未知のキーは無視する
+func handleSettingsImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req settingsExport
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Version > settingsExportVersion {
+		http.Error(w, fmt.Sprintf("Unsupported settings export version: %d", req.Version), http.StatusBadRequest)
+		return
+	}
+
+	values := make(map[string]string)
+	skipped := []string{}
+	invalid := map[string]string{}
+	for key, value := range req.Settings {
+		if _, known := settings.DefaultSettings[key]; !known {
+			skipped = append(skipped, key)
+			continue
+		}
+		if err := settings.ValidateSetting(key, value); err != nil {
+			invalid[key] = err.Error()
+			continue
+		}
+		values[key] = value
+	}
+	sort.Strings(skipped)
+
+	if len(invalid) > 0 {
+		logger.Warn("Settings import rejected", zap.Int("invalid", len(invalid)))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   "Invalid setting values",
+			"invalid": invalid,
+		})
+		return
+	}
+
+	settingsManager := settings.NewSettingsManager(localdb.GetDB())
+	if err := settingsManager.SetSettings(values); err != nil {
+		logger.Error("Failed to import settings", zap.Error(err))
+		http.Error(w, "Failed to import settings", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("Settings imported", zap.Int("applied", len(values)), zap.Strings("skipped", skipped))
+
+	// 各サブシステムに反映（env.Subscribe したものが再起動・再接続する）
+	if err := env.ReloadFromDatabase(); err != nil {
+		logger.Warn("Failed to reload env values from database", zap.Error(err))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"applied": len(values),
+		"skipped": skipped,
+		"message": fmt.Sprintf("Imported %d setting(s)", len(values)),
+	})
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/nantokaworks/twitch-overlay/internal/env"
 	"github.com/nantokaworks/twitch-overlay/internal/shared/logger"
+	"github.com/nantokaworks/twitch-overlay/internal/twitchapi"
 	"github.com/nantokaworks/twitch-overlay/internal/twitchtoken"
 	"go.uber.org/zap"
 )
@@ -150,4 +151,14 @@ func handleTwitchVerify(w http.ResponseWriter, r *http.Request) {
 		ProfileImageURL: userData.ProfileImageURL,
 		Verified:        true,
 	})
-}
\ No newline at end of file
+}
+// handleTwitchRateLimit returns the remaining Twitch API quota seen on the last request
+func handleTwitchRateLimit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(twitchapi.GetRateLimitStatus())
+}
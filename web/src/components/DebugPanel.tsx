@@ -425,6 +425,112 @@ const DebugPanel = ({}: DebugPanelProps) => {
               >
                 シャウトアウト
               </button>
+
+              <div className="flex gap-2">
+                <button
+                  onClick={() => handleTwitchEvent('hype-train-begin', { username })}
+                  disabled={isSubmitting}
+                  className={`flex-1 py-1.5 rounded transition-colors font-medium ${
+                    isSubmitting 
+                      ? 'bg-gray-600 text-gray-400 cursor-not-allowed' 
+                      : 'bg-orange-600 text-white hover:bg-orange-700'
+                  }`}
+                  style={{ fontSize: '13px' }}
+                >
+                  ハイプトレイン開始
+                </button>
+                <button
+                  onClick={() => handleTwitchEvent('hype-train-end', { contributors: [username, fromBroadcaster] })}
+                  disabled={isSubmitting}
+                  className={`flex-1 py-1.5 rounded transition-colors font-medium ${
+                    isSubmitting 
+                      ? 'bg-gray-600 text-gray-400 cursor-not-allowed' 
+                      : 'bg-orange-600 text-white hover:bg-orange-700'
+                  }`}
+                  style={{ fontSize: '13px' }}
+                >
+                  ハイプトレイン終了
+                </button>
+              </div>
+
+              <div className="flex gap-2">
+                <button
+                  onClick={() => handleTwitchEvent('poll-begin')}
+                  disabled={isSubmitting}
+                  className={`flex-1 py-1.5 rounded transition-colors font-medium ${
+                    isSubmitting 
+                      ? 'bg-gray-600 text-gray-400 cursor-not-allowed' 
+                      : 'bg-teal-600 text-white hover:bg-teal-700'
+                  }`}
+                  style={{ fontSize: '13px' }}
+                >
+                  投票開始
+                </button>
+                <button
+                  onClick={() => handleTwitchEvent('poll-end')}
+                  disabled={isSubmitting}
+                  className={`flex-1 py-1.5 rounded transition-colors font-medium ${
+                    isSubmitting 
+                      ? 'bg-gray-600 text-gray-400 cursor-not-allowed' 
+                      : 'bg-teal-600 text-white hover:bg-teal-700'
+                  }`}
+                  style={{ fontSize: '13px' }}
+                >
+                  投票結果
+                </button>
+              </div>
+
+              <div className="flex gap-2">
+                <button
+                  onClick={() => handleTwitchEvent('prediction-begin')}
+                  disabled={isSubmitting}
+                  className={`flex-1 py-1.5 rounded transition-colors font-medium ${
+                    isSubmitting 
+                      ? 'bg-gray-600 text-gray-400 cursor-not-allowed' 
+                      : 'bg-cyan-600 text-white hover:bg-cyan-700'
+                  }`}
+                  style={{ fontSize: '13px' }}
+                >
+                  予想開始
+                </button>
+                <button
+                  onClick={() => handleTwitchEvent('prediction-end')}
+                  disabled={isSubmitting}
+                  className={`flex-1 py-1.5 rounded transition-colors font-medium ${
+                    isSubmitting 
+                      ? 'bg-gray-600 text-gray-400 cursor-not-allowed' 
+                      : 'bg-cyan-600 text-white hover:bg-cyan-700'
+                  }`}
+                  style={{ fontSize: '13px' }}
+                >
+                  予想結果
+                </button>
+                <button
+                  onClick={() => handleTwitchEvent('prediction-end', { canceled: true })}
+                  disabled={isSubmitting}
+                  className={`flex-1 py-1.5 rounded transition-colors font-medium ${
+                    isSubmitting 
+                      ? 'bg-gray-600 text-gray-400 cursor-not-allowed' 
+                      : 'bg-cyan-600 text-white hover:bg-cyan-700'
+                  }`}
+                  style={{ fontSize: '13px' }}
+                >
+                  予想キャンセル
+                </button>
+              </div>
+
+              <button
+                onClick={() => handleTwitchEvent('goal')}
+                disabled={isSubmitting}
+                className={`w-full py-1.5 rounded transition-colors font-medium ${
+                  isSubmitting 
+                    ? 'bg-gray-600 text-gray-400 cursor-not-allowed' 
+                    : 'bg-lime-600 text-white hover:bg-lime-700'
+                }`}
+                style={{ fontSize: '13px' }}
+              >
+                ゴール達成
+              </button>
             </div>
           </div>
 
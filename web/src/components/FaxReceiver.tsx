@@ -89,9 +89,12 @@ const FaxReceiver = () => {
   useEffect(() => {
     let reconnectTimeout: NodeJS.Timeout | null = null;
     let eventSource: EventSource | null = null;
+    // 最後に受信したイベントID（FAXの通し番号）。再接続時に切断中のFAXを再送してもらう
+    let lastEventId = '';
 
     const connect = () => {
-      eventSource = new EventSource(buildEventSourceUrl('/events'));
+      const path = lastEventId ? `/events?since=${encodeURIComponent(lastEventId)}` : '/events';
+      eventSource = new EventSource(buildEventSourceUrl(path));
 
       eventSource.onopen = () => {
         setIsConnected(true);
@@ -103,6 +106,9 @@ const FaxReceiver = () => {
       };
 
       eventSource.onmessage = (event: MessageEvent) => {
+        if (event.lastEventId) {
+          lastEventId = event.lastEventId;
+        }
         try {
           const data: FaxData = JSON.parse(event.data);
           if (data.type === 'fax') {
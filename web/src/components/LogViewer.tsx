@@ -22,13 +22,14 @@ export const LogViewer: React.FC<LogViewerProps> = ({ embedded = false }) => {
   const [isStreaming, setIsStreaming] = useState(false);
   const [filter, setFilter] = useState('');
   const [levelFilter, setLevelFilter] = useState('all');
+  const [logLevel, setLogLevel] = useState('');
   const [autoScroll, setAutoScroll] = useState(true);
   const logsEndRef = useRef<HTMLDivElement>(null);
   const wsRef = useRef<WebSocket | null>(null);
 
-  // 初回ログ取得
+  // 初回取得（ログ本体は下の levelFilter の effect で取得する）
   useEffect(() => {
-    fetchLogs();
+    fetchLogLevel();
     return () => {
       if (wsRef.current) {
         wsRef.current.close();
@@ -36,6 +37,13 @@ export const LogViewer: React.FC<LogViewerProps> = ({ embedded = false }) => {
     };
   }, []);
 
+  // レベルを変えたら取り直す（ストリーミング中は受信済みのログをそのまま絞り込む）
+  useEffect(() => {
+    if (!isStreaming) {
+      fetchLogs();
+    }
+  }, [levelFilter]);
+
   // 自動スクロール
   useEffect(() => {
     if (autoScroll) {
@@ -43,9 +51,14 @@ export const LogViewer: React.FC<LogViewerProps> = ({ embedded = false }) => {
     }
   }, [logs, autoScroll]);
 
+  // レベルはサーバー側で絞り込む（一致する最新100件を取得）
   const fetchLogs = async () => {
     try {
-      const response = await fetch(buildApiUrl('/api/logs?limit=100'));
+      const params = new URLSearchParams({ limit: '100' });
+      if (levelFilter !== 'all') {
+        params.set('level', levelFilter);
+      }
+      const response = await fetch(buildApiUrl(`/api/logs?${params}`));
       if (!response.ok) throw new Error('Failed to fetch logs');
       
       const data = await response.json();
@@ -92,6 +105,35 @@ export const LogViewer: React.FC<LogViewerProps> = ({ embedded = false }) => {
     setIsStreaming(false);
   };
 
+  const fetchLogLevel = async () => {
+    try {
+      const response = await fetch(buildApiUrl('/api/logs/level'));
+      if (response.ok) {
+        const data = await response.json();
+        setLogLevel(data.level);
+      }
+    } catch (error) {
+      console.error('Failed to fetch log level:', error);
+    }
+  };
+
+  // サーバーが出力するログレベルを変更（LOG_LEVEL として保存される）
+  const changeLogLevel = async (level: string) => {
+    try {
+      const response = await fetch(buildApiUrl('/api/logs/level'), {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ level }),
+      });
+      if (response.ok) {
+        const data = await response.json();
+        setLogLevel(data.level);
+      }
+    } catch (error) {
+      console.error('Failed to change log level:', error);
+    }
+  };
+
   const clearLogs = async () => {
     try {
       const response = await fetch(buildApiUrl('/api/logs/clear'), {
@@ -199,6 +241,17 @@ export const LogViewer: React.FC<LogViewerProps> = ({ embedded = false }) => {
                 <SelectItem value="debug">Debug</SelectItem>
               </SelectContent>
             </Select>
+            <Select value={logLevel} onValueChange={changeLogLevel}>
+              <SelectTrigger className="w-40">
+                <SelectValue placeholder="出力レベル" />
+              </SelectTrigger>
+              <SelectContent>
+                <SelectItem value="debug">出力: Debug</SelectItem>
+                <SelectItem value="info">出力: Info</SelectItem>
+                <SelectItem value="warn">出力: Warn</SelectItem>
+                <SelectItem value="error">出力: Error</SelectItem>
+              </SelectContent>
+            </Select>
             <Button
               onClick={() => setAutoScroll(!autoScroll)}
               variant={autoScroll ? 'default' : 'outline'}
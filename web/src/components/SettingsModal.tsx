@@ -312,6 +312,22 @@ export const SettingsModal: React.FC<SettingsModalProps> = ({ onClose }) => {
                       />
                     </div>
                   )}
+
+                  {getBooleanValue('CLOCK_ENABLED') && (
+                    <div className="space-y-2">
+                      <Label htmlFor="clock_schedule">印刷スケジュール</Label>
+                      <Input
+                        id="clock_schedule"
+                        type="text"
+                        placeholder="60"
+                        value={getSettingValue('CLOCK_SCHEDULE')}
+                        onChange={(e) => handleSettingChange('CLOCK_SCHEDULE', e.target.value)}
+                      />
+                      <p className="text-sm text-gray-500">
+                        分単位の間隔（60 = 毎正時、30 = 30分ごと）または cron 形式（例: 0 9-23 * * *）
+                      </p>
+                    </div>
+                  )}
                 </CardContent>
               </Card>
             </TabsContent>
@@ -1,4 +1,4 @@
-import { Bluetooth, Bug, Eye, EyeOff, FileText, Layers, Monitor, Moon, Music, Pause, Play, Radio, RefreshCw, Server, Settings2, SkipBack, SkipForward, Square, Sun, Upload, Volume2, Wifi, X } from 'lucide-react';
+import { Bluetooth, Bug, Download, Eye, EyeOff, FileText, Layers, Monitor, Moon, Music, Pause, Play, Radio, RefreshCw, Server, Settings2, SkipBack, SkipForward, Square, Sun, Upload, Volume2, Wifi, X } from 'lucide-react';
 import React, { useEffect, useRef, useState } from 'react';
 import { toast } from 'sonner';
 import { useSettings } from '../contexts/SettingsContext';
@@ -7,6 +7,7 @@ import {
   AuthStatus,
   BluetoothDevice,
   FeatureStatus,
+  HealthStatus,
   PrinterStatusInfo,
   ScanResponse,
   SettingsResponse,
@@ -16,7 +17,7 @@ import {
   UpdateSettingsRequest
 } from '../types';
 import type { Playlist, Track } from '../types/music';
-import { buildApiUrl, buildEventSourceUrl } from '../utils/api';
+import { buildApiUrl, buildEventSourceUrl, getApiToken, setApiToken } from '../utils/api';
 import { LogViewer } from './LogViewer';
 import MusicManagerEmbed from './music/MusicManagerEmbed';
 import { Alert, AlertDescription } from './ui/alert';
@@ -49,12 +50,15 @@ export const SettingsPage: React.FC = () => {
   const [bluetoothDevices, setBluetoothDevices] = useState<BluetoothDevice[]>([]);
   const [scanning, setScanning] = useState(false);
   const [testing, setTesting] = useState(false);
+  const [validatingPrinter, setValidatingPrinter] = useState(false);
   const [unsavedChanges, setUnsavedChanges] = useState<UpdateSettingsRequest>({});
   const [showSecrets, setShowSecrets] = useState<Record<string, boolean>>({});
+  const [browserApiToken, setBrowserApiToken] = useState(getApiToken());
   const [uploadingFont, setUploadingFont] = useState(false);
   const [previewImage, setPreviewImage] = useState<string>('');
   const [previewText, setPreviewText] = useState<string>('サンプルテキスト Sample Text 123\nフォントプレビュー 🎨');
   const fileInputRef = useRef<HTMLInputElement>(null);
+  const settingsImportRef = useRef<HTMLInputElement>(null);
   const saveTimeoutRef = useRef<NodeJS.Timeout | undefined>(undefined);
   const [restarting, setRestarting] = useState(false);
   const [restartCountdown, setRestartCountdown] = useState(0);
@@ -62,7 +66,11 @@ export const SettingsPage: React.FC = () => {
   const [verifyingTwitch, setVerifyingTwitch] = useState(false);
   const [printerStatusInfo, setPrinterStatusInfo] = useState<PrinterStatusInfo | null>(null);
   const [reconnectingPrinter, setReconnectingPrinter] = useState(false);
+  const [togglingPause, setTogglingPause] = useState(false);
+  const [togglingDryRun, setTogglingDryRun] = useState(false);
+  const [printingLeaderboard, setPrintingLeaderboard] = useState(false);
   const [authStatus, setAuthStatus] = useState<AuthStatus | null>(null);
+  const [healthStatus, setHealthStatus] = useState<HealthStatus | null>(null);
   const [streamStatus, setStreamStatus] = useState<StreamStatus | null>(null);
   
   // 音楽プレイヤー制御用の状態
@@ -116,6 +124,7 @@ export const SettingsPage: React.FC = () => {
     fetchAllSettings();
     fetchAuthStatus();
     fetchStreamStatus();
+    fetchHealthStatus();
   }, []);
 
   // 配信状態の定期更新
@@ -199,6 +208,22 @@ export const SettingsPage: React.FC = () => {
             // プリンター状態を再取得
             fetchPrinterStatus();
           }
+
+          // 印刷の一時停止/再開
+          if (data.type === 'printer_paused' || data.type === 'printer_resumed') {
+            setPrinterStatusInfo((prev) => prev ? { ...prev, paused: data.type === 'printer_paused' } : prev);
+          }
+
+          // 印刷キューが満杯（プリンターが詰まっている）
+          if (data.type === 'printer_busy') {
+            toast.warning(data.data?.message || 'プリンターが混み合っています');
+            fetchPrinterStatus();
+          }
+
+          // FAXの処理件数の更新
+          if (data.type === 'fax_counters') {
+            setPrinterStatusInfo((prev) => prev ? { ...prev, fax_counters: data.data } : prev);
+          }
           
           // 配信状態変更イベントも処理
           if (data.type === 'stream_online' || data.type === 'stream_offline') {
@@ -321,6 +346,12 @@ export const SettingsPage: React.FC = () => {
       const data: any = await response.json();
       toast.success(`設定を保存しました: ${key}`);
       setFeatureStatus(data.status);
+
+      // API_TOKENを変更したブラウザでは以降のリクエストに新しいトークンを使う
+      if (key === 'API_TOKEN') {
+        setApiToken(value);
+        setBrowserApiToken(value);
+      }
       
       // 更新された設定をローカル状態に反映
       if (data.settings && data.settings[key]) {
@@ -393,6 +424,17 @@ export const SettingsPage: React.FC = () => {
     }
   };
 
+  const fetchHealthStatus = async () => {
+    try {
+      // 異常時は 503 だが本文に各コンポーネントの状態が入っている
+      const response = await fetch(buildApiUrl('/healthz'));
+      const data: HealthStatus = await response.json();
+      setHealthStatus(data);
+    } catch (err: any) {
+      console.error('Failed to fetch health status:', err);
+    }
+  };
+
   const fetchAuthStatus = async () => {
     try {
       const response = await fetch(buildApiUrl('/api/settings/auth/status'));
@@ -441,6 +483,47 @@ export const SettingsPage: React.FC = () => {
     }
   };
 
+  const handlePrinterPauseToggle = async () => {
+    const pausing = !printerStatusInfo?.paused;
+    setTogglingPause(true);
+    try {
+      const response = await fetch(buildApiUrl(pausing ? '/api/printer/pause' : '/api/printer/resume'), {
+        method: 'POST',
+      });
+      const data = await response.json();
+      if (data.success) {
+        toast.success(pausing ? '印刷を一時停止しました（FAXはキューに溜まります）' : `印刷を再開しました（待機中 ${data.status.queued}件）`);
+        await fetchPrinterStatus();
+      } else {
+        toast.error('印刷の一時停止/再開に失敗しました');
+      }
+    } catch (err: any) {
+      toast.error('印刷の一時停止/再開に失敗しました');
+    } finally {
+      setTogglingPause(false);
+    }
+  };
+
+  const handleDryRunToggle = async () => {
+    const enabling = !printerStatusInfo?.dry_run_mode;
+    setTogglingDryRun(true);
+    try {
+      const response = await fetch(buildApiUrl('/api/printer/dry-run'), {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ enabled: enabling }),
+      });
+      if (!response.ok) throw new Error(await response.text());
+      const data = await response.json();
+      toast.success(data.dry_run_mode ? 'DRY-RUNを有効にしました（実際には印刷しません）' : 'DRY-RUNを解除しました');
+      await Promise.all([fetchPrinterStatus(), fetchAllSettings()]);
+    } catch (err: any) {
+      toast.error('DRY-RUNの切り替えに失敗しました: ' + err.message);
+    } finally {
+      setTogglingDryRun(false);
+    }
+  };
+
   const handleScanDevices = async () => {
     setScanning(true);
     try {
@@ -478,6 +561,35 @@ export const SettingsPage: React.FC = () => {
     }
   };
 
+  const handleValidatePrinter = async () => {
+    setValidatingPrinter(true);
+    try {
+      const response = await fetch(buildApiUrl('/api/settings/printer/validate'), {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ mac_address: getSettingValue('PRINTER_ADDRESS') }),
+      });
+      if (!response.ok) {
+        throw new Error(await response.text());
+      }
+
+      const data = await response.json();
+      if (data.error) {
+        toast.error('アドレス確認に失敗しました: ' + data.error);
+      } else if (data.reachable) {
+        toast.success(data.message);
+        setFeatureStatus(prev => prev ? { ...prev, printer_connected: true } : prev);
+      } else {
+        toast.error(data.message);
+        setFeatureStatus(prev => prev ? { ...prev, printer_connected: false } : prev);
+      }
+    } catch (err: any) {
+      toast.error('アドレス確認でエラーが発生しました: ' + err.message);
+    } finally {
+      setValidatingPrinter(false);
+    }
+  };
+
   const handleTestConnection = async () => {
     const printerAddress = getSettingValue('PRINTER_ADDRESS');
     if (!printerAddress) {
@@ -506,6 +618,22 @@ export const SettingsPage: React.FC = () => {
     }
   };
 
+  // 今月のCheerランキングだけを印刷（月末のまとめ用）
+  const handlePrintLeaderboard = async () => {
+    setPrintingLeaderboard(true);
+    try {
+      const response = await fetch(buildApiUrl('/api/printer/print-leaderboard'), { method: 'POST' });
+      if (!response.ok) {
+        throw new Error(await response.text());
+      }
+      toast.success('Cheerランキングを印刷キューに追加しました');
+    } catch (err: any) {
+      toast.error('Cheerランキングの印刷に失敗しました: ' + err.message);
+    } finally {
+      setPrintingLeaderboard(false);
+    }
+  };
+
   const handleFontUpload = async (event: React.ChangeEvent<HTMLInputElement>) => {
     const file = event.target.files?.[0];
     if (!file) return;
@@ -558,6 +686,31 @@ export const SettingsPage: React.FC = () => {
     }
   };
 
+  // 設定のバックアップを読み込む（シークレットを含まないファイルの場合、シークレットは変更されない）
+  const handleImportSettings = async (file: File) => {
+    try {
+      const response = await fetch(buildApiUrl('/api/settings/import'), {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: await file.text(),
+      });
+      const result = await response.json().catch(() => null);
+      if (!response.ok) {
+        const invalid = result?.invalid ? Object.keys(result.invalid).join(', ') : '';
+        throw new Error(invalid ? `不正な値: ${invalid}` : (result?.error || response.statusText));
+      }
+
+      toast.success(`${result.applied}件の設定を読み込みました`);
+      await fetchAllSettings();
+    } catch (err: any) {
+      toast.error('設定の読み込みに失敗しました: ' + err.message);
+    } finally {
+      if (settingsImportRef.current) {
+        settingsImportRef.current.value = '';
+      }
+    }
+  };
+
   const handleDeleteFont = async () => {
     try {
       const response = await fetch(buildApiUrl('/api/settings/font'), {
@@ -885,7 +1038,10 @@ export const SettingsPage: React.FC = () => {
                       <div className="flex items-center space-x-2">
                         <span className="text-gray-600 dark:text-gray-300">
                           接続状態: {printerStatusInfo.connected ? '接続中' : '未接続'}
-                          {printerStatusInfo.dry_run_mode && ' (DRY-RUN)'}
+                          {printerStatusInfo.quiet_hours
+                            ? ' (静音時間帯)'
+                            : (printerStatusInfo.dry_run_active ?? printerStatusInfo.dry_run_mode) && ' (DRY-RUN)'}
+                          {printerStatusInfo.paused && ' (一時停止中)'}
                         </span>
                         <Button
                           size="sm"
@@ -896,7 +1052,44 @@ export const SettingsPage: React.FC = () => {
                         >
                           {reconnectingPrinter ? '再接続中...' : '再接続'}
                         </Button>
+                        <Button
+                          size="sm"
+                          variant="ghost"
+                          onClick={handlePrinterPauseToggle}
+                          disabled={togglingPause}
+                          className="h-6 px-2 text-xs"
+                        >
+                          {printerStatusInfo.paused ? '再開' : '一時停止'}
+                        </Button>
+                        <Button
+                          size="sm"
+                          variant="ghost"
+                          onClick={handleDryRunToggle}
+                          disabled={togglingDryRun}
+                          className="h-6 px-2 text-xs"
+                        >
+                          {printerStatusInfo.dry_run_mode ? 'DRY-RUN解除' : 'DRY-RUN'}
+                        </Button>
                       </div>
+                      {printerStatusInfo.fax_counters && (
+                        <div
+                          className="flex items-center space-x-2 text-xs text-gray-500 dark:text-gray-400"
+                          title={printerStatusInfo.fax_counters.last_failure ? `最後の失敗: ${printerStatusInfo.fax_counters.last_failure}` : undefined}
+                        >
+                          <span className={`w-2 h-2 rounded-full ${
+                            printerStatusInfo.fax_counters.failed > 0 || printerStatusInfo.fax_counters.dropped > 0 ? 'bg-yellow-500' : 'bg-green-500'
+                          }`} />
+                          <span>
+                            印刷 {printerStatusInfo.fax_counters.printed} / 破棄 {printerStatusInfo.fax_counters.dropped} / 抑止 {printerStatusInfo.fax_counters.blocked} / 失敗 {printerStatusInfo.fax_counters.failed}
+                          </span>
+                        </div>
+                      )}
+                      {(printerStatusInfo.last_print || (printerStatusInfo.print_queue ?? 0) > 0) && (
+                        <div className="text-xs text-gray-500 dark:text-gray-400">
+                          {printerStatusInfo.last_print && `最終印刷: ${new Date(printerStatusInfo.last_print).toLocaleString()}`}
+                          {(printerStatusInfo.print_queue ?? 0) > 0 && ` / 待機中: ${printerStatusInfo.print_queue}件`}
+                        </div>
+                      )}
                     </div>
                   )}
                 </div>
@@ -1373,6 +1566,22 @@ export const SettingsPage: React.FC = () => {
                   >
                     <span>{testing ? '接続テスト中...' : '接続テスト'}</span>
                   </Button>
+                  <Button 
+                    onClick={handleValidatePrinter} 
+                    disabled={validatingPrinter || scanning || !getSettingValue('PRINTER_ADDRESS')}
+                    variant="outline"
+                    className="flex items-center space-x-2"
+                  >
+                    <span>{validatingPrinter ? '確認中...' : 'アドレス確認'}</span>
+                  </Button>
+                  <Button
+                    onClick={handlePrintLeaderboard}
+                    disabled={printingLeaderboard}
+                    variant="outline"
+                    className="flex items-center space-x-2"
+                  >
+                    <span>{printingLeaderboard ? '送信中...' : 'Cheerランキング印刷'}</span>
+                  </Button>
                 </div>
 
                 {bluetoothDevices.length > 0 && (
@@ -1494,6 +1703,96 @@ export const SettingsPage: React.FC = () => {
                 </div>
               </CardContent>
             </Card>
+
+            <Card>
+              <CardHeader>
+                <CardTitle>印刷するイベント</CardTitle>
+                <CardDescription>
+                  オフにしたイベントはFAXを印刷しません（再起動不要）
+                </CardDescription>
+              </CardHeader>
+              <CardContent className="space-y-4">
+                <div className="grid grid-cols-1 md:grid-cols-2 gap-4">
+                  <div className="flex items-center justify-between">
+                    <Label>フォロー</Label>
+                    <Switch
+                      checked={getBooleanValue('PRINT_FOLLOW_ENABLED')}
+                      onCheckedChange={(checked) => handleSettingChange('PRINT_FOLLOW_ENABLED', checked)}
+                    />
+                  </div>
+                  <div className="flex items-center justify-between">
+                    <Label>Cheer（ビッツ）</Label>
+                    <Switch
+                      checked={getBooleanValue('PRINT_CHEER_ENABLED')}
+                      onCheckedChange={(checked) => handleSettingChange('PRINT_CHEER_ENABLED', checked)}
+                    />
+                  </div>
+                  <div className="flex items-center justify-between">
+                    <Label>レイド</Label>
+                    <Switch
+                      checked={getBooleanValue('PRINT_RAID_ENABLED')}
+                      onCheckedChange={(checked) => handleSettingChange('PRINT_RAID_ENABLED', checked)}
+                    />
+                  </div>
+                  <div className="flex items-center justify-between">
+                    <Label>シャウトアウト</Label>
+                    <Switch
+                      checked={getBooleanValue('PRINT_SHOUTOUT_ENABLED')}
+                      onCheckedChange={(checked) => handleSettingChange('PRINT_SHOUTOUT_ENABLED', checked)}
+                    />
+                  </div>
+                  <div className="flex items-center justify-between">
+                    <Label>サブスク</Label>
+                    <Switch
+                      checked={getBooleanValue('PRINT_SUBSCRIBE_ENABLED')}
+                      onCheckedChange={(checked) => handleSettingChange('PRINT_SUBSCRIBE_ENABLED', checked)}
+                    />
+                  </div>
+                  <div className="flex items-center justify-between">
+                    <Label>サブギフ</Label>
+                    <Switch
+                      checked={getBooleanValue('PRINT_GIFT_SUB_ENABLED')}
+                      onCheckedChange={(checked) => handleSettingChange('PRINT_GIFT_SUB_ENABLED', checked)}
+                    />
+                  </div>
+                  <div className="flex items-center justify-between">
+                    <Label>サブスクメッセージ</Label>
+                    <Switch
+                      checked={getBooleanValue('PRINT_RESUB_ENABLED')}
+                      onCheckedChange={(checked) => handleSettingChange('PRINT_RESUB_ENABLED', checked)}
+                    />
+                  </div>
+                  <div className="flex items-center justify-between">
+                    <Label>ハイプトレイン</Label>
+                    <Switch
+                      checked={getBooleanValue('PRINT_HYPE_TRAIN_ENABLED')}
+                      onCheckedChange={(checked) => handleSettingChange('PRINT_HYPE_TRAIN_ENABLED', checked)}
+                    />
+                  </div>
+                  <div className="flex items-center justify-between">
+                    <Label>投票</Label>
+                    <Switch
+                      checked={getBooleanValue('PRINT_POLL_ENABLED')}
+                      onCheckedChange={(checked) => handleSettingChange('PRINT_POLL_ENABLED', checked)}
+                    />
+                  </div>
+                  <div className="flex items-center justify-between">
+                    <Label>予想</Label>
+                    <Switch
+                      checked={getBooleanValue('PRINT_PREDICTION_ENABLED')}
+                      onCheckedChange={(checked) => handleSettingChange('PRINT_PREDICTION_ENABLED', checked)}
+                    />
+                  </div>
+                  <div className="flex items-center justify-between">
+                    <Label>ゴール達成</Label>
+                    <Switch
+                      checked={getBooleanValue('PRINT_GOAL_ENABLED')}
+                      onCheckedChange={(checked) => handleSettingChange('PRINT_GOAL_ENABLED', checked)}
+                    />
+                  </div>
+                </div>
+              </CardContent>
+            </Card>
           </TabsContent>
 
           {/* 音楽タブ */}
@@ -1799,6 +2098,34 @@ export const SettingsPage: React.FC = () => {
                   </div>
                 </div>
 
+                {/* ヘルスチェック（/healthz） */}
+                <div className="space-y-4">
+                  <div className="flex items-center justify-between">
+                    <h3 className="text-sm font-medium dark:text-gray-200">ヘルスチェック</h3>
+                    <Button size="sm" variant="ghost" onClick={fetchHealthStatus} className="h-6 px-2">
+                      <RefreshCw className="w-3 h-3" />
+                    </Button>
+                  </div>
+                  {healthStatus ? (
+                    <div className="space-y-2 text-sm dark:text-gray-300">
+                      {Object.entries({ database: 'データベース', font: 'フォント', twitch_token: 'Twitchトークン' }).map(([key, label]) => {
+                        const component = healthStatus.components[key];
+                        return (
+                          <div key={key} className="flex items-center space-x-2">
+                            <div className={`w-3 h-3 rounded-full ${component?.healthy ? 'bg-green-500' : 'bg-red-500'}`} />
+                            <span className="font-medium">{label}</span>
+                            {component?.message && (
+                              <span className="text-gray-500 dark:text-gray-400">{component.message}</span>
+                            )}
+                          </div>
+                        );
+                      })}
+                    </div>
+                  ) : (
+                    <p className="text-sm text-gray-500 dark:text-gray-400">取得中...</p>
+                  )}
+                </div>
+
                 {/* 再起動ボタン */}
                 <div className="space-y-4">
                   <h3 className="text-sm font-medium dark:text-gray-200">サービス管理</h3>
@@ -1864,6 +2191,101 @@ export const SettingsPage: React.FC = () => {
                   )}
                 </div>
 
+                {/* APIトークン */}
+                <div className="space-y-4">
+                  <h3 className="text-sm font-medium dark:text-gray-200">APIトークン</h3>
+                  <p className="text-sm text-gray-600 dark:text-gray-400">
+                    設定すると、設定変更や印刷などの操作系APIに Authorization: Bearer ヘッダーまたは ?token= が必要になります。オーバーレイの表示（/events, /fax/）はそのまま使えます。
+                  </p>
+                  <div className="space-y-2">
+                    <Label htmlFor="api_token">API_TOKEN（サーバー）</Label>
+                    <div className="relative">
+                      <Input
+                        id="api_token"
+                        type={showSecrets['API_TOKEN'] ? "text" : "password"}
+                        placeholder={settings['API_TOKEN']?.has_value ? "（設定済み）" : "未設定（認証なし）"}
+                        value={unsavedChanges['API_TOKEN'] !== undefined ? unsavedChanges['API_TOKEN'] : getSettingValue('API_TOKEN')}
+                        onChange={(e) => handleSettingChange('API_TOKEN', e.target.value)}
+                        className="pr-10"
+                      />
+                      <button
+                        type="button"
+                        onClick={() => setShowSecrets(prev => ({ ...prev, API_TOKEN: !prev.API_TOKEN }))}
+                        className="absolute right-2 top-1/2 -translate-y-1/2 text-gray-500 hover:text-gray-700 dark:text-gray-400 dark:hover:text-gray-300"
+                      >
+                        {showSecrets['API_TOKEN'] ? <EyeOff className="w-4 h-4" /> : <Eye className="w-4 h-4" />}
+                      </button>
+                    </div>
+                  </div>
+                  <div className="space-y-2">
+                    <Label htmlFor="browser_api_token">このブラウザで使うトークン</Label>
+                    <div className="flex space-x-2">
+                      <Input
+                        id="browser_api_token"
+                        type="password"
+                        value={browserApiToken}
+                        onChange={(e) => setBrowserApiToken(e.target.value)}
+                        placeholder="別のブラウザで設定したトークンを入力"
+                      />
+                      <Button
+                        variant="outline"
+                        onClick={() => {
+                          setApiToken(browserApiToken);
+                          toast.success(browserApiToken ? 'トークンを保存しました' : 'トークンを削除しました');
+                        }}
+                      >
+                        保存
+                      </Button>
+                    </div>
+                  </div>
+                  <div className="space-y-2">
+                    <Label htmlFor="allowed_origins">ALLOWED_ORIGINS</Label>
+                    <Input
+                      id="allowed_origins"
+                      value={getSettingValue('ALLOWED_ORIGINS')}
+                      onChange={(e) => handleSettingChange('ALLOWED_ORIGINS', e.target.value)}
+                      placeholder="*"
+                    />
+                    <p className="text-xs text-gray-500 dark:text-gray-400">
+                      ブラウザからのアクセスを許可するオリジン（カンマ区切り）。* はすべて許可します。
+                    </p>
+                  </div>
+                </div>
+
+                {/* 設定のバックアップ */}
+                <div className="space-y-4">
+                  <h3 className="text-sm font-medium dark:text-gray-200">設定のバックアップ</h3>
+                  <p className="text-sm text-gray-600 dark:text-gray-400">
+                    すべての設定をJSONファイルに書き出し、別の環境で読み込めます。シークレット（Client Secret など）は書き出しに含まれません。
+                  </p>
+                  <div className="flex space-x-2">
+                    <Button asChild variant="outline" className="flex items-center space-x-2">
+                      <a href={buildApiUrl('/api/settings/export')} download>
+                        <Download className="w-4 h-4" />
+                        <span>設定を書き出す</span>
+                      </a>
+                    </Button>
+                    <Button
+                      onClick={() => settingsImportRef.current?.click()}
+                      variant="outline"
+                      className="flex items-center space-x-2"
+                    >
+                      <Upload className="w-4 h-4" />
+                      <span>設定を読み込む</span>
+                    </Button>
+                    <input
+                      ref={settingsImportRef}
+                      type="file"
+                      accept="application/json,.json"
+                      className="hidden"
+                      onChange={(e) => {
+                        const file = e.target.files?.[0];
+                        if (file) handleImportSettings(file);
+                      }}
+                    />
+                  </div>
+                </div>
+
                 {/* 再起動に関する注意事項 */}
                 <Alert className="dark:bg-gray-700 dark:border-gray-600">
                   <AlertDescription className="dark:text-gray-300">
@@ -1895,7 +2317,7 @@ export const SettingsPage: React.FC = () => {
                         <span className="px-2 py-1 bg-green-100 dark:bg-green-900 text-green-800 dark:text-green-200 text-xs font-mono rounded">POST</span>
                         <span className="font-mono text-sm dark:text-gray-300">/api/music/upload</span>
                       </div>
-                      <p className="text-sm text-gray-600 dark:text-gray-400">ファイルアップロード (MP3/WAV/M4A/OGG)</p>
+                      <p className="text-sm text-gray-600 dark:text-gray-400">ファイルアップロード (MP3/WAV/M4A/OGG/FLAC)</p>
                     </div>
                     
                     <div className="p-4 bg-gray-50 dark:bg-gray-800 rounded-lg">
@@ -1906,6 +2328,14 @@ export const SettingsPage: React.FC = () => {
                       <p className="text-sm text-gray-600 dark:text-gray-400">全トラック取得</p>
                     </div>
                     
+                    <div className="p-4 bg-gray-50 dark:bg-gray-800 rounded-lg">
+                      <div className="flex items-center space-x-2 mb-2">
+                        <span className="px-2 py-1 bg-blue-100 dark:bg-blue-900 text-blue-800 dark:text-blue-200 text-xs font-mono rounded">GET</span>
+                        <span className="font-mono text-sm dark:text-gray-300">/api/music/tracks/search</span>
+                      </div>
+                      <p className="text-sm text-gray-600 dark:text-gray-400">トラック検索 (?q=&artist=&album=&limit=&offset=、総件数 total を返す)</p>
+                    </div>
+                    
                     <div className="p-4 bg-gray-50 dark:bg-gray-800 rounded-lg">
                       <div className="flex items-center space-x-2 mb-2">
                         <span className="px-2 py-1 bg-blue-100 dark:bg-blue-900 text-blue-800 dark:text-blue-200 text-xs font-mono rounded">GET</span>
@@ -1930,6 +2360,22 @@ export const SettingsPage: React.FC = () => {
                       <p className="text-sm text-gray-600 dark:text-gray-400">アートワーク画像取得</p>
                     </div>
                     
+                    <div className="p-4 bg-gray-50 dark:bg-gray-800 rounded-lg">
+                      <div className="flex items-center space-x-2 mb-2">
+                        <span className="px-2 py-1 bg-blue-100 dark:bg-blue-900 text-blue-800 dark:text-blue-200 text-xs font-mono rounded">GET</span>
+                        <span className="font-mono text-sm dark:text-gray-300">/api/music/track/{`{id}`}/waveform</span>
+                      </div>
+                      <p className="text-sm text-gray-600 dark:text-gray-400">波形のピークデータ取得 (?buckets=N、ffmpegが必要)</p>
+                    </div>
+                    
+                    <div className="p-4 bg-gray-50 dark:bg-gray-800 rounded-lg">
+                      <div className="flex items-center space-x-2 mb-2">
+                        <span className="px-2 py-1 bg-yellow-100 dark:bg-yellow-900 text-yellow-800 dark:text-yellow-200 text-xs font-mono rounded">PUT</span>
+                        <span className="font-mono text-sm dark:text-gray-300">/api/music/track/{`{id}`}</span>
+                      </div>
+                      <p className="text-sm text-gray-600 dark:text-gray-400">トラック情報の修正 (body: {`{"title", "artist", "album"}`}、空の項目は変更しない)</p>
+                    </div>
+                    
                     <div className="p-4 bg-gray-50 dark:bg-gray-800 rounded-lg">
                       <div className="flex items-center space-x-2 mb-2">
                         <span className="px-2 py-1 bg-red-100 dark:bg-red-900 text-red-800 dark:text-red-200 text-xs font-mono rounded">DELETE</span>
@@ -1984,6 +2430,22 @@ export const SettingsPage: React.FC = () => {
                       <p className="text-sm text-gray-600 dark:text-gray-400">プレイリストトラック取得</p>
                     </div>
                     
+                    <div className="p-4 bg-gray-50 dark:bg-gray-800 rounded-lg">
+                      <div className="flex items-center space-x-2 mb-2">
+                        <span className="px-2 py-1 bg-blue-100 dark:bg-blue-900 text-blue-800 dark:text-blue-200 text-xs font-mono rounded">GET</span>
+                        <span className="font-mono text-sm dark:text-gray-300">/api/music/playlist/{`{id}`}/export</span>
+                      </div>
+                      <p className="text-sm text-gray-600 dark:text-gray-400">プレイリストをJSONでエクスポート</p>
+                    </div>
+                    
+                    <div className="p-4 bg-gray-50 dark:bg-gray-800 rounded-lg">
+                      <div className="flex items-center space-x-2 mb-2">
+                        <span className="px-2 py-1 bg-green-100 dark:bg-green-900 text-green-800 dark:text-green-200 text-xs font-mono rounded">POST</span>
+                        <span className="font-mono text-sm dark:text-gray-300">/api/music/playlist/import</span>
+                      </div>
+                      <p className="text-sm text-gray-600 dark:text-gray-400">エクスポートしたJSONからプレイリストを作成 (?name= で別名。見つからない曲は missing に返る)</p>
+                    </div>
+                    
                     <div className="p-4 bg-gray-50 dark:bg-gray-800 rounded-lg">
                       <div className="flex items-center space-x-2 mb-2">
                         <span className="px-2 py-1 bg-yellow-100 dark:bg-yellow-900 text-yellow-800 dark:text-yellow-200 text-xs font-mono rounded">PUT</span>
@@ -2054,6 +2516,22 @@ export const SettingsPage: React.FC = () => {
                       <p className="text-sm text-gray-600 dark:text-gray-400">プレイリスト読み込み (body: {`{"playlist": "name"}`})</p>
                     </div>
                     
+                    <div className="p-4 bg-gray-50 dark:bg-gray-800 rounded-lg">
+                      <div className="flex items-center space-x-2 mb-2">
+                        <span className="px-2 py-1 bg-green-100 dark:bg-green-900 text-green-800 dark:text-green-200 text-xs font-mono rounded">POST</span>
+                        <span className="font-mono text-sm dark:text-gray-300">/api/music/control/shuffle</span>
+                      </div>
+                      <p className="text-sm text-gray-600 dark:text-gray-400">シャッフル切り替え (body省略で反転、または {`{"enabled": true}`})</p>
+                    </div>
+                    
+                    <div className="p-4 bg-gray-50 dark:bg-gray-800 rounded-lg">
+                      <div className="flex items-center space-x-2 mb-2">
+                        <span className="px-2 py-1 bg-green-100 dark:bg-green-900 text-green-800 dark:text-green-200 text-xs font-mono rounded">POST</span>
+                        <span className="font-mono text-sm dark:text-gray-300">/api/music/control/repeat</span>
+                      </div>
+                      <p className="text-sm text-gray-600 dark:text-gray-400">リピート切り替え (body省略で off→all→one、または {`{"mode": "off|one|all"}`})</p>
+                    </div>
+                    
                     <div className="p-4 bg-gray-50 dark:bg-gray-800 rounded-lg">
                       <div className="flex items-center space-x-2 mb-2">
                         <span className="px-2 py-1 bg-purple-100 dark:bg-purple-900 text-purple-800 dark:text-purple-200 text-xs font-mono rounded">SSE</span>
@@ -294,7 +294,7 @@ const MusicManagerEmbed = () => {
     const input = document.createElement('input');
     input.type = 'file';
     input.multiple = true;
-    input.accept = '.mp3,.wav,.m4a,.ogg';
+    input.accept = '.mp3,.wav,.m4a,.ogg,.flac';
     
     input.onchange = (e: Event) => {
       const files = Array.from((e.target as HTMLInputElement).files || []);
@@ -1,8 +1,8 @@
 import { useState, useEffect } from 'react';
 import { Button } from '../ui/button';
-import { Play, Pause, Square, SkipForward, SkipBack, Volume2, Music } from 'lucide-react';
+import { Play, Pause, Square, SkipForward, SkipBack, Volume2, Music, Shuffle, Repeat, Repeat1 } from 'lucide-react';
 import { buildApiUrl, buildEventSourceUrl } from '../../utils/api';
-import type { Playlist, Track } from '../../types/music';
+import type { Playlist, Track, RepeatMode } from '../../types/music';
 
 interface MusicStatus {
   playback_status?: 'playing' | 'paused' | 'stopped';
@@ -13,6 +13,8 @@ interface MusicStatus {
   duration: number;
   volume: number;
   playlist_name?: string;
+  shuffle_enabled?: boolean;
+  repeat_mode?: RepeatMode;
 }
 
 const MusicPlayerControls = () => {
@@ -170,6 +172,29 @@ const MusicPlayerControls = () => {
         >
           <Square className="w-4 h-4" />
         </Button>
+
+        <Button
+          onClick={() => sendControlCommand('shuffle')}
+          size="icon"
+          variant={musicStatus.shuffle_enabled ? 'default' : 'outline'}
+          title={musicStatus.shuffle_enabled ? 'シャッフル: オン' : 'シャッフル: オフ'}
+        >
+          <Shuffle className="w-4 h-4" />
+        </Button>
+
+        <Button
+          onClick={() => sendControlCommand('repeat')}
+          size="icon"
+          variant={musicStatus.repeat_mode && musicStatus.repeat_mode !== 'off' ? 'default' : 'outline'}
+          title={musicStatus.repeat_mode === 'one' ? 'リピート: 1曲' :
+                 musicStatus.repeat_mode === 'all' ? 'リピート: 全曲' : 'リピート: オフ'}
+        >
+          {musicStatus.repeat_mode === 'one' ? (
+            <Repeat1 className="w-4 h-4" />
+          ) : (
+            <Repeat className="w-4 h-4" />
+          )}
+        </Button>
       </div>
 
       {/* プログレスバー（表示のみ、シークは無効） */}
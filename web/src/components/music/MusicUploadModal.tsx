@@ -69,8 +69,8 @@ const MusicUploadModal = ({ isOpen, onClose, onUploadComplete, playlists = [], c
     
     for (const file of files) {
       // ファイル形式チェック
-      const validTypes = ['audio/mpeg', 'audio/mp3', 'audio/wav', 'audio/x-wav', 'audio/m4a', 'audio/ogg'];
-      if (!validTypes.includes(file.type) && !file.name.match(/\.(mp3|wav|m4a|ogg)$/i)) {
+      const validTypes = ['audio/mpeg', 'audio/mp3', 'audio/wav', 'audio/x-wav', 'audio/m4a', 'audio/ogg', 'audio/flac', 'audio/x-flac'];
+      if (!validTypes.includes(file.type) && !file.name.match(/\.(mp3|wav|m4a|ogg|flac)$/i)) {
         validFiles.push({
           file,
           status: 'error',
@@ -80,17 +80,7 @@ const MusicUploadModal = ({ isOpen, onClose, onUploadComplete, playlists = [], c
         continue;
       }
 
-      // ファイルサイズチェック（50MB）
-      if (file.size > 50 * 1024 * 1024) {
-        validFiles.push({
-          file,
-          status: 'error',
-          progress: 0,
-          error: 'ファイルサイズが50MBを超えています',
-        });
-        continue;
-      }
-
+      // ファイルサイズの上限（MUSIC_MAX_FILE_MB）はサーバー側でチェックする
       validFiles.push({
         file,
         status: 'pending',
@@ -163,10 +153,23 @@ const MusicUploadModal = ({ isOpen, onClose, onUploadComplete, playlists = [], c
               : f
           ));
           onUploadComplete(track);
+        } else if (xhr.status === 409) {
+          // 同じ内容のファイルが登録済み
+          let title = '';
+          try {
+            title = JSON.parse(xhr.responseText).track?.title ?? '';
+          } catch {
+            // ignore
+          }
+          setUploadQueue(prev => prev.map(f => 
+            f.file === fileStatus.file 
+              ? { ...f, status: 'error', error: title ? `登録済みの曲です: ${title}` : '登録済みの曲です' } 
+              : f
+          ));
         } else {
           setUploadQueue(prev => prev.map(f => 
             f.file === fileStatus.file 
-              ? { ...f, status: 'error', error: `アップロード失敗: ${xhr.statusText}` } 
+              ? { ...f, status: 'error', error: `アップロード失敗: ${xhr.responseText.trim() || xhr.statusText}` } 
               : f
           ));
         }
@@ -325,7 +328,7 @@ const MusicUploadModal = ({ isOpen, onClose, onUploadComplete, playlists = [], c
           <input
             ref={fileInputRef}
             type="file"
-            accept=".mp3,.wav,.m4a,.ogg"
+            accept=".mp3,.wav,.m4a,.ogg,.flac"
             multiple
             onChange={handleFileSelect}
             disabled={isUploading}
@@ -354,7 +357,7 @@ const MusicUploadModal = ({ isOpen, onClose, onUploadComplete, playlists = [], c
             ファイルを選択
           </button>
           <p style={{ marginTop: '10px', fontSize: '12px', color: '#666' }}>
-            MP3, WAV, M4A, OGG (最大50MB/ファイル)
+            MP3, WAV, M4A, OGG, FLAC
           </p>
         </div>
 
@@ -2,7 +2,7 @@ import React, { createContext, useContext, useEffect } from 'react';
 import { useMusicPlayer } from '../hooks/useMusicPlayer';
 import { buildEventSourceUrl } from '../utils/api';
 import { useSettings } from './SettingsContext';
-import type { Track, MusicPlayerState } from '../types/music';
+import type { Track, MusicPlayerState, RepeatMode } from '../types/music';
 
 interface MusicPlayerContextValue extends MusicPlayerState {
   play: () => void;
@@ -12,6 +12,8 @@ interface MusicPlayerContextValue extends MusicPlayerState {
   previous: () => void;
   seek: (time: number) => void;
   setVolume: (volume: number) => void;
+  setShuffle: (enabled: boolean) => void;
+  setRepeatMode: (mode: RepeatMode) => void;
   loadPlaylist: (playlistName?: string) => Promise<void>;
   loadTrack: (track: Track) => void;
   clearHistory: () => void;
@@ -64,6 +66,14 @@ export const MusicPlayerProvider = ({ children }: { children: React.ReactNode })
               player.seek(command.time);
             }
             break;
+          case 'shuffle':
+            player.setShuffle(command.enabled === true);
+            break;
+          case 'repeat':
+            if (command.mode === 'off' || command.mode === 'one' || command.mode === 'all') {
+              player.setRepeatMode(command.mode);
+            }
+            break;
         }
       } catch (error) {
         console.error('Failed to process music control command:', error);
@@ -77,7 +87,7 @@ export const MusicPlayerProvider = ({ children }: { children: React.ReactNode })
     return () => {
       eventSource.close();
     };
-  }, [player.play, player.pause, player.stop, player.next, player.previous, player.setVolume, player.loadPlaylist]);
+  }, [player.play, player.pause, player.stop, player.next, player.previous, player.setVolume, player.loadPlaylist, player.setShuffle, player.setRepeatMode]);
 
   return (
     <MusicPlayerContext.Provider value={player}>
@@ -1,5 +1,5 @@
 import { useState, useRef, useEffect, useCallback } from 'react';
-import type { Track, MusicPlayerState, PlaybackStatus } from '../types/music';
+import type { Track, MusicPlayerState, PlaybackStatus, RepeatMode } from '../types/music';
 import { buildApiUrl } from '../utils/api';
 
 interface UseMusicPlayerReturn extends MusicPlayerState {
@@ -10,6 +10,8 @@ interface UseMusicPlayerReturn extends MusicPlayerState {
   previous: () => void;
   seek: (time: number) => void;
   setVolume: (volume: number) => void;
+  setShuffle: (enabled: boolean) => void;
+  setRepeatMode: (mode: RepeatMode) => void;
   loadPlaylist: (playlistName?: string) => Promise<void>;
   loadTrack: (track: Track) => void;
   clearHistory: () => void;
@@ -45,9 +47,16 @@ const saveToStorage = (key: string, value: any): void => {
   }
 };
 
+// 音量(0-100)に曲ごとの補正(dB)を掛けて audio.volume (0-1) に変換
+const applyGain = (volume: number, gainDb?: number): number => {
+  const gain = Math.pow(10, Math.max(-12, Math.min(12, gainDb ?? 0)) / 20);
+  return Math.max(0, Math.min(1, (volume / 100) * gain));
+};
+
 export const useMusicPlayer = (initialVolume?: number): UseMusicPlayerReturn => {
   const audioRef = useRef<HTMLAudioElement | null>(null);
   const handleNextRef = useRef<(() => void) | null>(null);
+  const repeatModeRef = useRef<RepeatMode>('all');
   const isInitializedRef = useRef(false);
   
   // 保存された値を初期値として使用（Settingsからの音量を優先）
@@ -63,6 +72,8 @@ export const useMusicPlayer = (initialVolume?: number): UseMusicPlayerReturn =>
     volume: initialVolume ?? getFromStorage(STORAGE_KEYS.VOLUME, 70),
     isLoading: false,
     playHistory: getFromStorage(STORAGE_KEYS.PLAY_HISTORY, []),
+    shuffleEnabled: true,
+    repeatMode: 'all',
   });
 
   // 状態をlocalStorageに保存
@@ -100,6 +111,12 @@ export const useMusicPlayer = (initialVolume?: number): UseMusicPlayerReturn =>
     }
   }, [initialVolume]);
 
+  // 曲ごとの音量補正（gain_db）を反映（曲の切り替え・音量変更のたびに再計算）
+  useEffect(() => {
+    if (!audioRef.current) return;
+    audioRef.current.volume = applyGain(state.volume, state.currentTrack?.gain_db);
+  }, [state.volume, state.currentTrack?.gain_db, state.currentTrack?.id]);
+
   // オーディオ要素の初期化
   useEffect(() => {
     audioRef.current = new Audio();
@@ -121,6 +138,12 @@ export const useMusicPlayer = (initialVolume?: number): UseMusicPlayerReturn =>
     };
 
     const handleEnded = () => {
+      // 1曲リピートは同じ曲を頭から再生
+      if (repeatModeRef.current === 'one') {
+        audio.currentTime = 0;
+        audio.play().catch(err => console.error('Failed to repeat track:', err));
+        return;
+      }
       // 3秒のインターバルを設けてから次の曲へ
       setState(prev => ({ ...prev, playbackStatus: 'paused', isPlaying: false }));
       setTimeout(() => {
@@ -189,6 +212,50 @@ export const useMusicPlayer = (initialVolume?: number): UseMusicPlayerReturn =>
     return unplayedTracks[randomIndex];
   }, [state.playlist, state.playHistory, state.currentTrack]);
 
+  // 次のトラックを取得（シャッフル・リピート設定に従う。リピートなしで最後まで再生したら null）
+  const getNextTrack = useCallback((): Track | null => {
+    if (state.playlist.length === 0) return null;
+
+    if (state.shuffleEnabled) {
+      if (state.repeatMode === 'off') {
+        const allPlayed = state.playlist.every(
+          track => track.id === state.currentTrack?.id || state.playHistory.includes(track.id)
+        );
+        if (allPlayed) return null;
+      }
+      return getNextRandomTrack();
+    }
+
+    // プレイリスト順
+    const index = state.playlist.findIndex(track => track.id === state.currentTrack?.id);
+    if (index + 1 >= state.playlist.length) {
+      return state.repeatMode === 'off' ? null : state.playlist[0];
+    }
+    return state.playlist[index + 1];
+  }, [state.playlist, state.playHistory, state.currentTrack, state.shuffleEnabled, state.repeatMode, getNextRandomTrack]);
+
+  // プレイリスト順の再生ではサーバーに次の曲を決めてもらう（複数のオーバーレイで順序を揃えるため）
+  const resolveNextTrack = useCallback(async (): Promise<Track | null> => {
+    if (state.shuffleEnabled || !state.playlistName || !state.currentTrack) {
+      return getNextTrack();
+    }
+    // 手動の「次へ」では1曲リピート中でも次の曲に進む
+    const repeat = state.repeatMode === 'one' ? 'all' : state.repeatMode;
+    try {
+      const response = await fetch(buildApiUrl(
+        `/api/music/playlist/${encodeURIComponent(state.playlistName)}/next?after=${state.currentTrack.id}&shuffle=false&repeat=${repeat}`
+      ));
+      if (response.status === 404) return null; // リピートなしで最後まで再生した
+      if (response.ok) {
+        const track: Track = await response.json();
+        return state.playlist.find(t => t.id === track.id) ?? track;
+      }
+    } catch (error) {
+      console.error('Failed to get next track from server:', error);
+    }
+    return getNextTrack();
+  }, [state.shuffleEnabled, state.playlistName, state.currentTrack, state.repeatMode, state.playlist, getNextTrack]);
+
   // トラックを読み込む
   const loadTrack = useCallback((track: Track, autoPlay: boolean = false) => {
     if (!audioRef.current) return;
@@ -266,9 +333,12 @@ export const useMusicPlayer = (initialVolume?: number): UseMusicPlayerReturn =>
     }
     setState(prev => ({ ...prev, playbackStatus: 'paused', isPlaying: false }));
     
-    setTimeout(() => {
-      const nextTrack = getNextRandomTrack();
-      if (nextTrack) {
+    setTimeout(async () => {
+      const nextTrack = await resolveNextTrack();
+      if (!nextTrack) {
+        // リピートなしでプレイリストの最後まで再生した
+        setState(prev => ({ ...prev, playbackStatus: 'stopped', isPlaying: false }));
+      } else {
         // 現在のトラックを履歴に追加
         if (state.currentTrack) {
           setState(prev => ({
@@ -283,7 +353,7 @@ export const useMusicPlayer = (initialVolume?: number): UseMusicPlayerReturn =>
         loadTrack(nextTrack, true); // 明示的にautoPlay=trueを指定
       }
     }, 500); // next/prevボタンは少し短めのインターバル
-  }, [getNextRandomTrack, loadTrack, state.currentTrack]);
+  }, [resolveNextTrack, loadTrack, state.currentTrack]);
   
   // handleNextの参照を更新
   useEffect(() => {
@@ -334,6 +404,17 @@ export const useMusicPlayer = (initialVolume?: number): UseMusicPlayerReturn =>
     setState(prev => ({ ...prev, volume: clampedVolume }));
   }, []);
 
+  // シャッフル切り替え（サーバーからのコマンドで呼ばれる）
+  const setShuffle = useCallback((enabled: boolean) => {
+    setState(prev => ({ ...prev, shuffleEnabled: enabled }));
+  }, []);
+
+  // リピートモード切り替え（サーバーからのコマンドで呼ばれる）
+  const setRepeatMode = useCallback((mode: RepeatMode) => {
+    repeatModeRef.current = mode;
+    setState(prev => ({ ...prev, repeatMode: mode }));
+  }, []);
+
   // サーバーから再生状態を復元
   const restoreServerState = useCallback(async (tracks: Track[]) => {
     try {
@@ -536,6 +617,8 @@ export const useMusicPlayer = (initialVolume?: number): UseMusicPlayerReturn =>
     previous,
     seek,
     setVolume,
+    setShuffle,
+    setRepeatMode,
     loadPlaylist,
     loadTrack,
     clearHistory,
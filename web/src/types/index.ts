@@ -152,13 +152,39 @@ export interface StreamStatus {
 export interface PrinterStatusInfo {
   connected: boolean;
   dry_run_mode: boolean;
+  dry_run_active?: boolean;
+  quiet_hours?: boolean;
   printer_address: string;
   configured: boolean;
   last_print?: string | null;
   print_queue?: number;
+  fax_counters?: FaxCounters;
+  paused?: boolean;
   error?: string;
 }
 
+// 起動してからのFAXの処理件数（SSE fax_counters でも更新される）
+export interface FaxCounters {
+  printed: number;
+  dropped: number;
+  blocked: number;
+  failed: number;
+  last_failure?: string;
+  last_failure_at?: string;
+}
+
+// /healthz の各コンポーネントの状態
+export interface ComponentHealth {
+  healthy: boolean;
+  message?: string;
+}
+
+export interface HealthStatus {
+  healthy: boolean;
+  components: Record<string, ComponentHealth>;
+  timestamp: string;
+}
+
 // Bluetoothデバイス
 export interface BluetoothDevice {
   mac_address: string;
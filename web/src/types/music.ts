@@ -6,6 +6,7 @@ export interface Track {
   album: string;
   duration: number;
   has_artwork: boolean;
+  gain_db?: number; // 音量補正（dB、サーバー側で±12dBにクランプ）
   created_at: string;
 }
 
@@ -23,6 +24,8 @@ export interface PlaylistTrack extends Track {
 
 export type PlaybackStatus = 'playing' | 'paused' | 'stopped';
 
+export type RepeatMode = 'off' | 'one' | 'all';
+
 export interface MusicPlayerState {
   playbackStatus: PlaybackStatus;
   isPlaying: boolean; // 互換性のため残す（playbackStatus === 'playing'と同じ）
@@ -35,6 +38,8 @@ export interface MusicPlayerState {
   volume: number; // 0-100
   isLoading: boolean;
   playHistory: string[]; // 再生履歴（ランダム再生用）
+  shuffleEnabled: boolean; // サーバーから同期される
+  repeatMode: RepeatMode;
 }
 
 export interface MusicUploadProgress {
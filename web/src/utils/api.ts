@@ -15,21 +15,52 @@ export function getApiBaseUrl(): string {
   return '';
 }
 
+// API_TOKEN（サーバー側で設定されている場合、変更系のAPIに必要）はこのブラウザに保存する
+const API_TOKEN_STORAGE_KEY = 'apiToken';
+
+export function getApiToken(): string {
+  try {
+    return localStorage.getItem(API_TOKEN_STORAGE_KEY) || '';
+  } catch {
+    return '';
+  }
+}
+
+export function setApiToken(token: string): void {
+  try {
+    if (token) {
+      localStorage.setItem(API_TOKEN_STORAGE_KEY, token);
+    } else {
+      localStorage.removeItem(API_TOKEN_STORAGE_KEY);
+    }
+  } catch {
+    // localStorageが使えない環境では保存しない
+  }
+}
+
+// トークンがあれば ?token= を付ける（Authorizationヘッダーと違いCORSのプリフライトが不要）
+function withApiToken(url: string): string {
+  const token = getApiToken();
+  if (!token) return url;
+  const separator = url.includes('?') ? '&' : '?';
+  return `${url}${separator}token=${encodeURIComponent(token)}`;
+}
+
 // API URLを構築（通常のfetch用）
 export function buildApiUrl(path: string): string {
   // 環境変数が設定されている場合
   if (import.meta.env.VITE_API_BASE_URL) {
-    return `${import.meta.env.VITE_API_BASE_URL}${path}`;
+    return withApiToken(`${import.meta.env.VITE_API_BASE_URL}${path}`);
   }
   
   // 本番環境
   if (import.meta.env.PROD) {
-    return path;
+    return withApiToken(path);
   }
   
   // 開発環境: Viteプロキシが機能しない場合があるので、完全なURLを返す
   const backendPort = import.meta.env.VITE_BACKEND_PORT || '8080';
-  return `http://localhost:${backendPort}${path}`;
+  return withApiToken(`http://localhost:${backendPort}${path}`);
 }
 
 // EventSource用のURLを構築（開発環境では完全なURLが必要）